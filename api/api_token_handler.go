@@ -0,0 +1,154 @@
+/*
+ * Copyright (c) 2026 TFG Co <backend@tfgco.com>
+ * Author: TFG Co <backend@tfgco.com>
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy of
+ * this software and associated documentation files (the "Software"), to deal in
+ * the Software without restriction, including without limitation the rights to
+ * use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+ * the Software, and to permit persons to whom the Software is furnished to do so,
+ * subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+ * FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+ * COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+ * IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+ * CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+ */
+
+package api
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	uuid "github.com/satori/go.uuid"
+	"github.com/topfreegames/marathon/log"
+	"github.com/topfreegames/marathon/model"
+	"github.com/uber-go/zap"
+)
+
+// ListAPITokensHandler is the method called when a get to /users/:uid/tokens is called
+func (a *Application) ListAPITokensHandler(c echo.Context) error {
+	l := a.Logger.With(
+		zap.String("source", "apiTokenHandler"),
+		zap.String("operation", "listAPITokens"),
+		zap.String("userId", c.Param("uid")),
+	)
+	uid, err := uuid.FromString(c.Param("uid"))
+	if err != nil {
+		return c.JSON(http.StatusUnprocessableEntity, &Error{Reason: err.Error()})
+	}
+	tokens := []model.APIToken{}
+	err = WithSegment("db-select", c, func() error {
+		return a.DB.Model(&tokens).Where("user_id = ?", uid).Order("created_at").Select()
+	})
+	if err != nil {
+		log.E(l, "Failed to list API tokens.", func(cm log.CM) {
+			cm.Write(zap.Error(err))
+		})
+		return c.JSON(http.StatusInternalServerError, &Error{Reason: err.Error()})
+	}
+	return c.JSON(http.StatusOK, tokens)
+}
+
+// CreateAPITokenHandler is the method called when a post to /users/:uid/tokens is called, the
+// response is the only time the token is returned
+func (a *Application) CreateAPITokenHandler(c echo.Context) error {
+	l := a.Logger.With(
+		zap.String("source", "apiTokenHandler"),
+		zap.String("operation", "createAPIToken"),
+		zap.String("userId", c.Param("uid")),
+	)
+	uid, err := uuid.FromString(c.Param("uid"))
+	if err != nil {
+		return c.JSON(http.StatusUnprocessableEntity, &Error{Reason: err.Error()})
+	}
+	token := &model.APIToken{}
+	err = WithSegment("decodeAndValidate", c, func() error {
+		return decodeAndValidate(c, token)
+	})
+	if err != nil {
+		return c.JSON(http.StatusUnprocessableEntity, &Error{Reason: err.Error(), Value: token})
+	}
+	// only the name, scopes and expiration of the token come from the request
+	token.ID = uuid.NewV4()
+	token.UserID = uid
+	token.LastUsedAt = 0
+	token.RevokedAt = 0
+	token.CreatedBy = c.Get("user-email").(string)
+	token.CreatedAt = time.Now().UnixNano()
+
+	user := &model.User{ID: uid}
+	err = WithSegment("db-select", c, func() error {
+		return a.DB.Select(&user)
+	})
+	if err != nil {
+		if err.Error() == RecordNotFoundString {
+			return c.JSON(http.StatusNotFound, map[string]string{})
+		}
+		log.E(l, "Failed to retrieve user.", func(cm log.CM) {
+			cm.Write(zap.Error(err))
+		})
+		return c.JSON(http.StatusInternalServerError, &Error{Reason: err.Error(), Value: token})
+	}
+
+	err = token.GenerateToken()
+	if err == nil {
+		err = WithSegment("db-insert", c, func() error {
+			return a.DB.Insert(token)
+		})
+	}
+	if err != nil {
+		log.E(l, "Failed to create API token.", func(cm log.CM) {
+			cm.Write(zap.Error(err))
+		})
+		return c.JSON(http.StatusInternalServerError, &Error{Reason: err.Error()})
+	}
+	log.I(l, "Created API token successfully.", func(cm log.CM) {
+		cm.Write(zap.String("tokenId", token.ID.String()))
+	})
+	return c.JSON(http.StatusCreated, token)
+}
+
+// RevokeAPITokenHandler is the method called when a delete to /users/:uid/tokens/:tkid is called
+func (a *Application) RevokeAPITokenHandler(c echo.Context) error {
+	l := a.Logger.With(
+		zap.String("source", "apiTokenHandler"),
+		zap.String("operation", "revokeAPIToken"),
+		zap.String("userId", c.Param("uid")),
+		zap.String("tokenId", c.Param("tkid")),
+	)
+	uid, err := uuid.FromString(c.Param("uid"))
+	if err != nil {
+		return c.JSON(http.StatusUnprocessableEntity, &Error{Reason: err.Error()})
+	}
+	tkid, err := uuid.FromString(c.Param("tkid"))
+	if err != nil {
+		return c.JSON(http.StatusUnprocessableEntity, &Error{Reason: err.Error()})
+	}
+	tokens := []model.APIToken{}
+	err = WithSegment("db-update", c, func() error {
+		_, err := a.DB.Query(&tokens,
+			"UPDATE api_tokens SET revoked_at = ? WHERE id = ? AND user_id = ? AND coalesce(revoked_at, 0) = 0 RETURNING *",
+			time.Now().UnixNano(), tkid, uid,
+		)
+		return err
+	})
+	if err != nil {
+		log.E(l, "Failed to revoke API token.", func(cm log.CM) {
+			cm.Write(zap.Error(err))
+		})
+		return c.JSON(http.StatusInternalServerError, &Error{Reason: err.Error()})
+	}
+	if len(tokens) == 0 {
+		return c.JSON(http.StatusNotFound, map[string]string{})
+	}
+	log.I(l, "Revoked API token successfully.")
+	return c.JSON(http.StatusOK, tokens[0])
+}
@@ -0,0 +1,225 @@
+/*
+ * Copyright (c) 2026 TFG Co <backend@tfgco.com>
+ * Author: TFG Co <backend@tfgco.com>
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy of
+ * this software and associated documentation files (the "Software"), to deal in
+ * the Software without restriction, including without limitation the rights to
+ * use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+ * the Software, and to permit persons to whom the Software is furnished to do so,
+ * subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+ * FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+ * COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+ * IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+ * CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+ */
+
+package api_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	uuid "github.com/satori/go.uuid"
+	"github.com/topfreegames/marathon/model"
+	. "github.com/topfreegames/marathon/testing"
+	"github.com/uber-go/zap"
+)
+
+var _ = Describe("API Token Handler", func() {
+	logger := zap.New(
+		zap.NewJSONEncoder(zap.NoTime()), // drop timestamps in tests
+		zap.FatalLevel,
+	)
+	app := GetDefaultTestApp(logger)
+	var adminUser *model.User
+	var testUser *model.User
+	var existingApp *model.App
+
+	BeforeEach(func() {
+		app.DB.Exec("DELETE FROM apps;")
+		app.DB.Exec("DELETE FROM users;")
+		adminUser = CreateTestUser(app.DB, map[string]interface{}{"email": "admin@test.com"})
+		existingApp = CreateTestApp(app.DB)
+		testUser = CreateTestUser(app.DB, map[string]interface{}{
+			"email":       "user@test.com",
+			"isAdmin":     false,
+			"allowedApps": []uuid.UUID{},
+			"appRoles":    map[string][]string{existingApp.ID.String(): {model.RoleAppAdmin}},
+		})
+	})
+
+	Describe("Post /users/:uid/tokens", func() {
+		It("should return 201 and the token only once", func() {
+			payload := `{"name": "ci", "scopes": ["job_creator"]}`
+			url := fmt.Sprintf("/users/%s/tokens", testUser.ID)
+			status, body := Post(app, url, payload, "user@test.com")
+			Expect(status).To(Equal(http.StatusCreated))
+
+			var response map[string]interface{}
+			err := json.Unmarshal([]byte(body), &response)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(response["name"]).To(Equal("ci"))
+			Expect(response["userId"]).To(Equal(testUser.ID.String()))
+			Expect(response["createdBy"]).To(Equal("user@test.com"))
+			Expect(response["token"]).To(HavePrefix(model.APITokenPrefix))
+			Expect(response).NotTo(HaveKey("tokenHash"))
+
+			token := &model.APIToken{}
+			err = app.DB.Model(token).Where("user_id = ?", testUser.ID).Select()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(token.TokenHash).To(Equal(model.HashAPIToken(response["token"].(string))))
+			Expect(token.Scopes).To(Equal([]string{model.RoleJobCreator}))
+
+			status, body = Get(app, url, "user@test.com")
+			Expect(status).To(Equal(http.StatusOK))
+			Expect(body).NotTo(ContainSubstring(response["token"].(string)))
+		})
+
+		It("should ignore the fields set by the server", func() {
+			payload := fmt.Sprintf(`{"name": "ci", "scopes": ["viewer"], "userId": "%s", "revokedAt": 1}`, adminUser.ID)
+			status, body := Post(app, fmt.Sprintf("/users/%s/tokens", testUser.ID), payload, "user@test.com")
+			Expect(status).To(Equal(http.StatusCreated))
+
+			var response map[string]interface{}
+			err := json.Unmarshal([]byte(body), &response)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(response["userId"]).To(Equal(testUser.ID.String()))
+			Expect(response["revokedAt"]).To(BeEquivalentTo(0))
+		})
+
+		It("should return 422 if the scopes are invalid", func() {
+			payload := `{"name": "ci", "scopes": ["owner"]}`
+			status, body := Post(app, fmt.Sprintf("/users/%s/tokens", testUser.ID), payload, "user@test.com")
+			Expect(status).To(Equal(http.StatusUnprocessableEntity))
+
+			var response map[string]interface{}
+			err := json.Unmarshal([]byte(body), &response)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(response["reason"]).To(Equal("invalid scopes"))
+		})
+
+		It("should return 422 if the token is already expired", func() {
+			payload := fmt.Sprintf(`{"name": "ci", "scopes": ["viewer"], "expiresAt": %d}`, time.Now().Add(-time.Hour).UnixNano())
+			status, body := Post(app, fmt.Sprintf("/users/%s/tokens", testUser.ID), payload, "user@test.com")
+			Expect(status).To(Equal(http.StatusUnprocessableEntity))
+
+			var response map[string]interface{}
+			err := json.Unmarshal([]byte(body), &response)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(response["reason"]).To(Equal("invalid expiresAt"))
+		})
+
+		It("should return 403 if a non admin user creates a token for another user", func() {
+			payload := `{"name": "ci", "scopes": ["viewer"]}`
+			status, _ := Post(app, fmt.Sprintf("/users/%s/tokens", adminUser.ID), payload, "user@test.com")
+			Expect(status).To(Equal(http.StatusForbidden))
+		})
+
+		It("should return 404 if the user does not exist", func() {
+			payload := `{"name": "ci", "scopes": ["viewer"]}`
+			status, _ := Post(app, fmt.Sprintf("/users/%s/tokens", uuid.NewV4()), payload, "admin@test.com")
+			Expect(status).To(Equal(http.StatusNotFound))
+		})
+	})
+
+	Describe("Get /users/:uid/tokens", func() {
+		It("should return 200 and the tokens of the user", func() {
+			CreateTestAPIToken(app.DB, testUser.ID)
+			CreateTestAPIToken(app.DB, adminUser.ID)
+
+			status, body := Get(app, fmt.Sprintf("/users/%s/tokens", testUser.ID), "admin@test.com")
+			Expect(status).To(Equal(http.StatusOK))
+
+			var response []map[string]interface{}
+			err := json.Unmarshal([]byte(body), &response)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(response).To(HaveLen(1))
+			Expect(response[0]["userId"]).To(Equal(testUser.ID.String()))
+			Expect(response[0]).NotTo(HaveKey("token"))
+		})
+	})
+
+	Describe("Delete /users/:uid/tokens/:tkid", func() {
+		It("should return 200 and revoke the token", func() {
+			token := CreateTestAPIToken(app.DB, testUser.ID)
+			url := fmt.Sprintf("/users/%s/tokens/%s", testUser.ID, token.ID)
+			status, body := Delete(app, url, "user@test.com")
+			Expect(status).To(Equal(http.StatusOK))
+
+			var response map[string]interface{}
+			err := json.Unmarshal([]byte(body), &response)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(response["revokedAt"]).To(BeNumerically(">", 0))
+
+			status, _ = RequestWithToken(app, "GET", "/apps", "", token.Token)
+			Expect(status).To(Equal(http.StatusUnauthorized))
+
+			status, _ = Delete(app, url, "user@test.com")
+			Expect(status).To(Equal(http.StatusNotFound))
+		})
+
+		It("should return 404 if the token belongs to another user", func() {
+			token := CreateTestAPIToken(app.DB, adminUser.ID)
+			status, _ := Delete(app, fmt.Sprintf("/users/%s/tokens/%s", testUser.ID, token.ID), "admin@test.com")
+			Expect(status).To(Equal(http.StatusNotFound))
+		})
+	})
+
+	Describe("Bearer authentication", func() {
+		It("should authenticate the token user and set the token last use", func() {
+			token := CreateTestAPIToken(app.DB, testUser.ID)
+			status, _ := RequestWithToken(app, "GET", fmt.Sprintf("/apps/%s", existingApp.ID), "", token.Token)
+			Expect(status).To(Equal(http.StatusOK))
+
+			dbToken := &model.APIToken{ID: token.ID}
+			err := app.DB.Select(dbToken)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(dbToken.LastUsedAt).To(BeNumerically(">", 0))
+		})
+
+		It("should return 401 if the token is unknown", func() {
+			status, _ := RequestWithToken(app, "GET", "/apps", "", model.APITokenPrefix+"unknown")
+			Expect(status).To(Equal(http.StatusUnauthorized))
+		})
+
+		It("should return 401 if the token expired", func() {
+			token := CreateTestAPIToken(app.DB, testUser.ID, map[string]interface{}{
+				"expiresAt": time.Now().Add(-time.Minute).UnixNano(),
+			})
+			status, _ := RequestWithToken(app, "GET", "/apps", "", token.Token)
+			Expect(status).To(Equal(http.StatusUnauthorized))
+		})
+
+		It("should return 403 if the token scopes do not grant the route role", func() {
+			token := CreateTestAPIToken(app.DB, testUser.ID, map[string]interface{}{
+				"scopes": []string{model.RoleViewer},
+			})
+			url := fmt.Sprintf("/apps/%s/jobs?template=%s", existingApp.ID, "any")
+			status, _ := RequestWithToken(app, "POST", url, `{}`, token.Token)
+			Expect(status).To(Equal(http.StatusForbidden))
+
+			status, _ = RequestWithToken(app, "GET", fmt.Sprintf("/apps/%s/jobs", existingApp.ID), "", token.Token)
+			Expect(status).To(Equal(http.StatusOK))
+		})
+
+		It("should not grant more than the token user can do", func() {
+			token := CreateTestAPIToken(app.DB, testUser.ID)
+			status, _ := RequestWithToken(app, "GET", fmt.Sprintf("/apps/%s", uuid.NewV4()), "", token.Token)
+			Expect(status).To(Equal(http.StatusForbidden))
+
+			status, _ = RequestWithToken(app, "GET", fmt.Sprintf("/users/%s", adminUser.ID), "", token.Token)
+			Expect(status).To(Equal(http.StatusForbidden))
+		})
+	})
+})
@@ -23,6 +23,7 @@
 package api
 
 import (
+	"encoding/json"
 	"net/http"
 	"strings"
 	"time"
@@ -75,6 +76,10 @@ func (a *Application) PostAppHandler(c echo.Context) error {
 	if err != nil {
 		return c.JSON(http.StatusUnprocessableEntity, &Error{Reason: err.Error(), Value: app})
 	}
+	app.LocaleFallbacks = model.NormalizeLocaleFallbacks(app.LocaleFallbacks)
+	if app.HoldoutSalt == "" {
+		app.HoldoutSalt = uuid.NewV4().String()
+	}
 	err = WithSegment("db-insert", c, func() error {
 		return a.DB.Insert(&app)
 	})
@@ -124,6 +129,15 @@ func (a *Application) GetAppHandler(c echo.Context) error {
 	return c.JSON(http.StatusOK, app)
 }
 
+// appSettingColumns maps the optional app fields to the columns PutAppHandler updates
+var appSettingColumns = map[string]string{
+	"localeFallbacks":   "locale_fallbacks",
+	"holdoutPercentage": "holdout_percentage",
+	"frequencyCaps":     "frequency_caps",
+	"quietHours":        "quiet_hours",
+	"requiresApproval":  "requires_approval",
+}
+
 // PutAppHandler is the method called when a put to /apps/:aid is called
 func (a *Application) PutAppHandler(c echo.Context) error {
 	l := a.Logger.With(
@@ -135,19 +149,35 @@ func (a *Application) PutAppHandler(c echo.Context) error {
 	email := c.Get("user-email").(string)
 	app.CreatedBy = email
 	app.UpdatedAt = time.Now().UnixNano()
+	var fields map[string]json.RawMessage
 	err := WithSegment("decodeAndValidate", c, func() error {
-		return decodeAndValidate(c, app)
+		var err error
+		fields, err = decodeAndValidateFields(c, app)
+		return err
 	})
 	if err != nil {
 		return c.JSON(http.StatusUnprocessableEntity, &Error{Reason: err.Error(), Value: app})
 	}
+	app.LocaleFallbacks = model.NormalizeLocaleFallbacks(app.LocaleFallbacks)
 	id, err := uuid.FromString(c.Param("aid"))
 	if err != nil {
 		return c.JSON(http.StatusUnprocessableEntity, &Error{Reason: err.Error()})
 	}
 	app.ID = id
 	err = WithSegment("db-update", c, func() error {
-		_, err = a.DB.Model(&app).Column("name").Column("bundle_id").Column("updated_at").Returning("*").Update()
+		query := a.DB.Model(&app).Column("name").Column("bundle_id").Column("updated_at")
+		// the settings are only changed when given, so clients that only send the
+		// name and bundleId do not reset them
+		for field, column := range appSettingColumns {
+			if _, ok := fields[field]; ok {
+				query = query.Column(column)
+			}
+		}
+		// the salt is only changed when given, changing it moves every user to a new holdout bucket
+		if app.HoldoutSalt != "" {
+			query = query.Column("holdout_salt")
+		}
+		_, err = query.Returning("*").Update()
 		return err
 	})
 	if err != nil {
@@ -138,6 +138,48 @@ var _ = Describe("App Handler", func() {
 				Expect(dbApp.BundleID).To(Equal(payload["bundleId"]))
 				Expect(dbApp.CreatedBy).To(Equal("test@test.com"))
 			})
+
+			It("should return 201 and the created app with normalized locale fallbacks", func() {
+				payload := GetAppPayload()
+				payload["localeFallbacks"] = map[string]interface{}{
+					"pt_BR": []string{"PT", "es"},
+				}
+				pl, _ := json.Marshal(payload)
+				status, body := Post(app, "/apps", string(pl), "test@test.com")
+				Expect(status).To(Equal(http.StatusCreated))
+
+				var response map[string]interface{}
+				err := json.Unmarshal([]byte(body), &response)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(response["localeFallbacks"]).To(Equal(map[string]interface{}{
+					"pt-br": []interface{}{"pt", "es"},
+				}))
+
+				id, err := uuid.FromString(response["id"].(string))
+				Expect(err).NotTo(HaveOccurred())
+				dbApp := &model.App{
+					ID: id,
+				}
+				err = app.DB.Select(dbApp)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(dbApp.LocaleFallbacks).To(Equal(map[string][]string{
+					"pt-br": {"pt", "es"},
+				}))
+			})
+
+			It("should return 201 and the created app with a holdout salt", func() {
+				payload := GetAppPayload()
+				payload["holdoutPercentage"] = 0.05
+				pl, _ := json.Marshal(payload)
+				status, body := Post(app, "/apps", string(pl), "test@test.com")
+				Expect(status).To(Equal(http.StatusCreated))
+
+				var response map[string]interface{}
+				err := json.Unmarshal([]byte(body), &response)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(response["holdoutPercentage"]).To(Equal(0.05))
+				Expect(response["holdoutSalt"]).NotTo(BeEmpty())
+			})
 		})
 
 		Describe("Unsuccessfully", func() {
@@ -222,6 +264,62 @@ var _ = Describe("App Handler", func() {
 				Expect(err).NotTo(HaveOccurred())
 				Expect(response["reason"]).To(Equal("invalid bundleId"))
 			})
+
+			It("should return 422 if invalid localeFallbacks", func() {
+				payload := GetAppPayload()
+				payload["localeFallbacks"] = map[string]interface{}{
+					"pt-br": []string{"not a locale"},
+				}
+				pl, _ := json.Marshal(payload)
+				status, body := Post(app, "/apps", string(pl), "test@test.com")
+				Expect(status).To(Equal(http.StatusUnprocessableEntity))
+
+				var response map[string]interface{}
+				err := json.Unmarshal([]byte(body), &response)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(response["reason"]).To(Equal("invalid localeFallbacks"))
+			})
+
+			It("should return 422 if invalid holdoutPercentage", func() {
+				payload := GetAppPayload()
+				payload["holdoutPercentage"] = 1.0
+				pl, _ := json.Marshal(payload)
+				status, body := Post(app, "/apps", string(pl), "test@test.com")
+				Expect(status).To(Equal(http.StatusUnprocessableEntity))
+
+				var response map[string]interface{}
+				err := json.Unmarshal([]byte(body), &response)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(response["reason"]).To(Equal("invalid holdoutPercentage"))
+			})
+
+			It("should return 422 if invalid frequencyCaps", func() {
+				payload := GetAppPayload()
+				payload["frequencyCaps"] = []map[string]interface{}{
+					{"category": "promo", "maxPushes": 0, "window": 3600000000000},
+				}
+				pl, _ := json.Marshal(payload)
+				status, body := Post(app, "/apps", string(pl), "test@test.com")
+				Expect(status).To(Equal(http.StatusUnprocessableEntity))
+
+				var response map[string]interface{}
+				err := json.Unmarshal([]byte(body), &response)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(response["reason"]).To(Equal("invalid frequencyCaps"))
+			})
+
+			It("should return 422 if invalid quietHours", func() {
+				payload := GetAppPayload()
+				payload["quietHours"] = map[string]interface{}{"start": "22h", "end": "08:00"}
+				pl, _ := json.Marshal(payload)
+				status, body := Post(app, "/apps", string(pl), "test@test.com")
+				Expect(status).To(Equal(http.StatusUnprocessableEntity))
+
+				var response map[string]interface{}
+				err := json.Unmarshal([]byte(body), &response)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(response["reason"]).To(Equal("invalid quietHours"))
+			})
 		})
 	})
 
@@ -341,6 +439,44 @@ var _ = Describe("App Handler", func() {
 				Expect(dbApp.BundleID).To(Equal(payload["bundleId"]))
 				Expect(dbApp.CreatedBy).To(Equal(existingApp.CreatedBy))
 			})
+
+			It("should return 200 and keep the settings that are not in the payload", func() {
+				CreateTestUser(app.DB, map[string]interface{}{"email": "update@test.com", "isAdmin": true})
+				existingApp := CreateTestApp(app.DB, map[string]interface{}{"requiresApproval": true, "holdoutPercentage": 0.1})
+				payload := GetAppPayload()
+				pl, _ := json.Marshal(payload)
+				status, body := Put(app, fmt.Sprintf("/apps/%s", existingApp.ID), string(pl), "update@test.com")
+				Expect(status).To(Equal(http.StatusOK))
+
+				var response map[string]interface{}
+				err := json.Unmarshal([]byte(body), &response)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(response["name"]).To(Equal(payload["name"]))
+				Expect(response["requiresApproval"]).To(BeTrue())
+				Expect(response["holdoutPercentage"]).To(Equal(0.1))
+
+				dbApp := &model.App{ID: existingApp.ID}
+				err = app.DB.Select(dbApp)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(dbApp.Name).To(Equal(payload["name"]))
+				Expect(dbApp.RequiresApproval).To(BeTrue())
+				Expect(dbApp.HoldoutPercentage).To(Equal(0.1))
+			})
+
+			It("should return 200 and update the settings that are in the payload", func() {
+				CreateTestUser(app.DB, map[string]interface{}{"email": "update@test.com", "isAdmin": true})
+				existingApp := CreateTestApp(app.DB, map[string]interface{}{"requiresApproval": true})
+				payload := GetAppPayload()
+				payload["requiresApproval"] = false
+				pl, _ := json.Marshal(payload)
+				status, _ := Put(app, fmt.Sprintf("/apps/%s", existingApp.ID), string(pl), "update@test.com")
+				Expect(status).To(Equal(http.StatusOK))
+
+				dbApp := &model.App{ID: existingApp.ID}
+				err := app.DB.Select(dbApp)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(dbApp.RequiresApproval).To(BeFalse())
+			})
 		})
 
 		Describe("Unsuccessfully", func() {
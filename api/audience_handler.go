@@ -0,0 +1,117 @@
+/*
+ * Copyright (c) 2026 TFG Co <backend@tfgco.com>
+ * Author: TFG Co <backend@tfgco.com>
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy of
+ * this software and associated documentation files (the "Software"), to deal in
+ * the Software without restriction, including without limitation the rights to
+ * use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+ * the Software, and to permit persons to whom the Software is furnished to do so,
+ * subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+ * FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+ * COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+ * IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+ * CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+ */
+
+package api
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	uuid "github.com/satori/go.uuid"
+	"github.com/topfreegames/marathon/log"
+	"github.com/topfreegames/marathon/model"
+	"github.com/topfreegames/marathon/worker"
+	"github.com/uber-go/zap"
+)
+
+// AudienceEstimateResponse is the response of the audience estimate route
+type AudienceEstimateResponse struct {
+	Tokens       int                         `json:"tokens"`
+	ControlGroup int                         `json:"controlGroup"`
+	Services     map[string]*worker.Audience `json:"services"`
+}
+
+// PostAudienceEstimateHandler is the method called when a post to /apps/:aid/audience/estimate is called
+func (a *Application) PostAudienceEstimateHandler(c echo.Context) error {
+	l := a.Logger.With(
+		zap.String("source", "audienceHandler"),
+		zap.String("operation", "postAudienceEstimate"),
+		zap.String("appId", c.Param("aid")),
+	)
+	aid, err := uuid.FromString(c.Param("aid"))
+	if err != nil {
+		return c.JSON(http.StatusUnprocessableEntity, &Error{Reason: err.Error()})
+	}
+
+	app := &model.App{ID: aid}
+	err = WithSegment("db-select", c, func() error {
+		return a.DB.Select(&app)
+	})
+	if err != nil {
+		if err.Error() == RecordNotFoundString {
+			return c.JSON(http.StatusNotFound, &Error{Reason: "App not found with given id."})
+		}
+		log.E(l, "Failed to retrieve app.", func(cm log.CM) {
+			cm.Write(zap.Error(err))
+		})
+		return c.JSON(http.StatusInternalServerError, &Error{Reason: err.Error(), Value: app})
+	}
+
+	estimate := &model.AudienceEstimate{}
+	err = WithSegment("decodeAndValidate", c, func() error {
+		return decodeAndValidate(c, estimate)
+	})
+	if err != nil {
+		return c.JSON(http.StatusUnprocessableEntity, &Error{Reason: err.Error(), Value: estimate})
+	}
+
+	res := &AudienceEstimateResponse{Services: map[string]*worker.Audience{}}
+	for _, service := range estimate.Services() {
+		job := estimate.Job(app, service)
+		skip, err := a.checkFilters(job, c)
+		if err != nil || skip {
+			return err
+		}
+
+		var audience *worker.Audience
+		err = WithSegment("db-select", c, func() error {
+			if len(job.CSVPath) > 0 {
+				audience, err = worker.EstimateAudienceFromCSV(a.PushDB, a.S3Client, job, a.getCSVChunkSize(), a.getAudienceBatchSize())
+			} else {
+				audience, err = worker.EstimateAudienceFromFilters(a.PushDB, job)
+			}
+			return err
+		})
+		if err != nil {
+			log.E(l, "Failed to estimate audience.", func(cm log.CM) {
+				cm.Write(zap.String("service", service), zap.Error(err))
+			})
+			return c.JSON(http.StatusInternalServerError, &Error{Reason: err.Error(), Value: estimate})
+		}
+		res.Services[service] = audience
+		res.Tokens += audience.Tokens
+		res.ControlGroup += audience.ControlGroup
+	}
+
+	log.D(l, "Estimated audience successfully.", func(cm log.CM) {
+		cm.Write(zap.Object("audience", res))
+	})
+	return c.JSON(http.StatusOK, res)
+}
+
+func (a *Application) getCSVChunkSize() int {
+	return int(a.Config.GetFloat64("workers.csvSplitWorker.csvSizeLimitMB") * 1024 * 1024)
+}
+
+func (a *Application) getAudienceBatchSize() int {
+	return a.Config.GetInt("audience.estimate.batchSize")
+}
@@ -0,0 +1,224 @@
+/*
+ * Copyright (c) 2026 TFG Co <backend@tfgco.com>
+ * Author: TFG Co <backend@tfgco.com>
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy of
+ * this software and associated documentation files (the "Software"), to deal in
+ * the Software without restriction, including without limitation the rights to
+ * use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+ * the Software, and to permit persons to whom the Software is furnished to do so,
+ * subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+ * FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+ * COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+ * IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+ * CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+ */
+
+package api_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	uuid "github.com/satori/go.uuid"
+	"github.com/topfreegames/marathon/model"
+	. "github.com/topfreegames/marathon/testing"
+	"github.com/uber-go/zap"
+)
+
+var _ = Describe("Audience Handler", func() {
+	logger := zap.New(
+		zap.NewJSONEncoder(zap.NoTime()), // drop timestamps in tests
+		zap.FatalLevel,
+	)
+	app := GetDefaultTestApp(logger)
+	faultyDb := GetFaultyTestDB(app)
+	fakeS3 := NewFakeS3(app.Config)
+	app.S3Client = fakeS3
+	var existingApp *model.App
+	var baseRoute string
+
+	BeforeEach(func() {
+		app.DB.Exec("DELETE FROM apps;")
+		app.DB.Exec("DELETE FROM users;")
+		CreateTestUser(app.DB, map[string]interface{}{"email": "test@test.com", "isAdmin": true})
+		app.Worker.RedisClient.FlushAll()
+
+		existingApp = CreateTestApp(app.DB)
+		baseRoute = fmt.Sprintf("/apps/%s/audience/estimate", existingApp.ID)
+
+		csv := []byte("userIds\n9e558649-9c23-469d-a11c-59b05813e3d5\r\na8e8d2d5-f178-4d90-9b31-683ad3aae920\n57be9009-e616-42c6-9cfe-505508ede2d0\nnotinthedb\n")
+		fakeS3.PutObject("test/jobs/audience.csv", &csv)
+	})
+
+	Describe("Post /apps/:id/audience/estimate", func() {
+		Describe("Sucesfully", func() {
+			It("should return 200 and the audience of the filters", func() {
+				pl, _ := json.Marshal(map[string]interface{}{
+					"service":      "gcm",
+					"filters":      map[string]interface{}{"region": "BR"},
+					"controlGroup": 0.5,
+				})
+				status, body := Post(app, baseRoute, string(pl), "test@test.com")
+				Expect(status).To(Equal(http.StatusOK))
+
+				var response map[string]interface{}
+				err := json.Unmarshal([]byte(body), &response)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(response["tokens"]).To(BeEquivalentTo(6))
+				Expect(response["controlGroup"]).To(BeEquivalentTo(3))
+
+				services := response["services"].(map[string]interface{})
+				Expect(services).To(HaveLen(1))
+				gcm := services["gcm"].(map[string]interface{})
+				Expect(gcm["tokens"]).To(BeEquivalentTo(6))
+				Expect(gcm["controlGroup"]).To(BeEquivalentTo(3))
+				Expect(gcm["locales"]).To(Equal(map[string]interface{}{"PT": float64(6)}))
+				Expect(gcm["timezones"]).To(Equal(map[string]interface{}{"-0300": float64(4), "-0500": float64(2)}))
+			})
+
+			It("should return 200 and the audience of a filter expression", func() {
+				pl, _ := json.Marshal(map[string]interface{}{
+					"service": "gcm",
+					"filters": map[string]interface{}{
+						"and": []interface{}{
+							map[string]interface{}{"column": "locale", "op": "eq", "value": "en"},
+							map[string]interface{}{"column": "tz", "op": "neq", "value": "-0500"},
+						},
+					},
+				})
+				status, body := Post(app, baseRoute, string(pl), "test@test.com")
+				Expect(status).To(Equal(http.StatusOK))
+
+				var response map[string]interface{}
+				err := json.Unmarshal([]byte(body), &response)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(response["tokens"]).To(BeEquivalentTo(3))
+				Expect(response["controlGroup"]).To(BeEquivalentTo(0))
+				gcm := response["services"].(map[string]interface{})["gcm"].(map[string]interface{})
+				Expect(gcm["locales"]).To(Equal(map[string]interface{}{"EN": float64(3)}))
+				Expect(gcm["timezones"]).To(Equal(map[string]interface{}{"-0300": float64(3)}))
+			})
+
+			It("should return 200 and the audience of a csv for every service", func() {
+				pl, _ := json.Marshal(map[string]interface{}{
+					"csvPath": "test/jobs/audience.csv",
+				})
+				status, body := Post(app, baseRoute, string(pl), "test@test.com")
+				Expect(status).To(Equal(http.StatusOK))
+
+				var response map[string]interface{}
+				err := json.Unmarshal([]byte(body), &response)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(response["tokens"]).To(BeEquivalentTo(5))
+
+				services := response["services"].(map[string]interface{})
+				apns := services["apns"].(map[string]interface{})
+				Expect(apns["tokens"]).To(BeEquivalentTo(3))
+				Expect(apns["locales"]).To(Equal(map[string]interface{}{"pt": float64(2), "en": float64(1)}))
+				Expect(apns["timezones"]).To(Equal(map[string]interface{}{"-0300": float64(3)}))
+				gcm := services["gcm"].(map[string]interface{})
+				Expect(gcm["tokens"]).To(BeEquivalentTo(2))
+				Expect(gcm["locales"]).To(Equal(map[string]interface{}{"PT": float64(1), "EN": float64(1)}))
+			})
+
+			It("should not enqueue any job", func() {
+				pl, _ := json.Marshal(map[string]interface{}{
+					"filters": map[string]interface{}{"locale": "en"},
+				})
+				status, _ := Post(app, baseRoute, string(pl), "test@test.com")
+				Expect(status).To(Equal(http.StatusOK))
+
+				keys, err := app.Worker.RedisClient.Keys("*").Result()
+				Expect(err).NotTo(HaveOccurred())
+				Expect(keys).To(BeEmpty())
+
+				var jobs []model.Job
+				err = app.DB.Model(&jobs).Where("app_id = ?", existingApp.ID).Select()
+				Expect(err).NotTo(HaveOccurred())
+				Expect(jobs).To(BeEmpty())
+			})
+		})
+
+		Describe("Unsucesfully", func() {
+			It("should return 401 if no authenticated user", func() {
+				status, _ := Post(app, baseRoute, "{}", "")
+				Expect(status).To(Equal(http.StatusUnauthorized))
+			})
+
+			It("should return 404 if app does not exist", func() {
+				route := fmt.Sprintf("/apps/%s/audience/estimate", uuid.NewV4())
+				status, _ := Post(app, route, "{}", "test@test.com")
+				Expect(status).To(Equal(http.StatusNotFound))
+			})
+
+			It("should return 422 if app id is not UUID", func() {
+				status, _ := Post(app, "/apps/not-uuid/audience/estimate", "{}", "test@test.com")
+				Expect(status).To(Equal(http.StatusUnprocessableEntity))
+			})
+
+			It("should return 422 if invalid service", func() {
+				pl, _ := json.Marshal(map[string]interface{}{"service": "email"})
+				status, body := Post(app, baseRoute, string(pl), "test@test.com")
+				Expect(status).To(Equal(http.StatusUnprocessableEntity))
+
+				var response map[string]interface{}
+				err := json.Unmarshal([]byte(body), &response)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(response["reason"]).To(Equal("invalid service"))
+			})
+
+			It("should return 422 if both csvPath and filters are provided", func() {
+				pl, _ := json.Marshal(map[string]interface{}{
+					"filters": map[string]interface{}{"locale": "en"},
+					"csvPath": "test/jobs/audience.csv",
+				})
+				status, body := Post(app, baseRoute, string(pl), "test@test.com")
+				Expect(status).To(Equal(http.StatusUnprocessableEntity))
+
+				var response map[string]interface{}
+				err := json.Unmarshal([]byte(body), &response)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(response["reason"]).To(Equal("invalid filters or csvPath must exist, not both"))
+			})
+
+			It("should return 422 if filters use a column that does not exist in push db", func() {
+				pl, _ := json.Marshal(map[string]interface{}{
+					"filters": map[string]interface{}{"column": "age", "op": "gt", "value": 18},
+				})
+				status, body := Post(app, baseRoute, string(pl), "test@test.com")
+				Expect(status).To(Equal(http.StatusUnprocessableEntity))
+
+				var response map[string]interface{}
+				err := json.Unmarshal([]byte(body), &response)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(response["reason"]).To(Equal("invalid filters: column age does not exist"))
+			})
+
+			It("should return 500 if csv does not exist", func() {
+				pl, _ := json.Marshal(map[string]interface{}{
+					"csvPath": "test/jobs/notfound.csv",
+				})
+				status, _ := Post(app, baseRoute, string(pl), "test@test.com")
+				Expect(status).To(Equal(http.StatusInternalServerError))
+			})
+
+			It("should return 500 if some error occured", func() {
+				goodDB := app.DB
+				app.DB = faultyDb
+				status, _ := Post(app, baseRoute, "{}", "test@test.com")
+				Expect(status).To(Equal(http.StatusInternalServerError))
+				app.DB = goodDB
+			})
+		})
+	})
+})
@@ -0,0 +1,239 @@
+/*
+ * Copyright (c) 2026 TFG Co <backend@tfgco.com>
+ * Author: TFG Co <backend@tfgco.com>
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy of
+ * this software and associated documentation files (the "Software"), to deal in
+ * the Software without restriction, including without limitation the rights to
+ * use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+ * the Software, and to permit persons to whom the Software is furnished to do so,
+ * subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+ * FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+ * COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+ * IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+ * CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+ */
+
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	uuid "github.com/satori/go.uuid"
+	"github.com/topfreegames/marathon/log"
+	"github.com/topfreegames/marathon/model"
+	"github.com/uber-go/zap"
+)
+
+// auditCollections are the last path segments of the routes that create resources
+var auditCollections = map[string]bool{
+	"apps":      true,
+	"templates": true,
+	"jobs":      true,
+	"schedules": true,
+	"users":     true,
+	"tokens":    true,
+	"import":    true,
+}
+
+// auditSkippedRoutes are the routes that use POST but do not change anything
+var auditSkippedRoutes = map[string]bool{
+	"POST /apps/:aid/templates/:tid/preview": true,
+	"POST /apps/:aid/audience/estimate":      true,
+}
+
+// auditDryRunRoutes are the routes that change nothing when called with dryRun=true, the other
+// routes ignore the dryRun param and are always audited
+var auditDryRunRoutes = map[string]bool{
+	"POST /apps/:aid/templates/import": true,
+}
+
+// auditSecretFields are the response fields that are never written to audit logs
+var auditSecretFields = []string{"token"}
+
+// auditResponseWriter keeps a copy of the response body, the id of a created resource is only
+// known from the response
+type auditResponseWriter struct {
+	http.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *auditResponseWriter) Write(b []byte) (int, error) {
+	w.body.Write(b)
+	return w.ResponseWriter.Write(b)
+}
+
+// auditTarget returns the route param of the resource changed by the request, it is empty if
+// the request creates a resource
+func auditTarget(c echo.Context) (string, uuid.UUID) {
+	segments := strings.Split(c.Path(), "/")
+	if auditCollections[segments[len(segments)-1]] {
+		return "", uuid.Nil
+	}
+	names := c.ParamNames()
+	if len(names) == 0 {
+		return "", uuid.Nil
+	}
+	param := names[len(names)-1]
+	id, err := uuid.FromString(c.Param(param))
+	if err != nil {
+		return "", uuid.Nil
+	}
+	return param, id
+}
+
+// auditSnapshot returns the resource of the route param as it is returned by the API, or nil if
+// it does not exist
+func (a *Application) auditSnapshot(param string, id uuid.UUID) (map[string]interface{}, error) {
+	var resource interface{}
+	switch param {
+	case "aid":
+		resource = &model.App{ID: id}
+	case "tid":
+		resource = &model.Template{ID: id}
+	case "jid":
+		resource = &model.Job{ID: id}
+	case "sid":
+		resource = &model.Schedule{ID: id}
+	case "uid":
+		resource = &model.User{ID: id}
+	case "tkid":
+		resource = &model.APIToken{ID: id}
+	case "gid":
+		// a job group only changes through its jobs
+		jobs := []model.Job{}
+		err := a.DB.Model(&jobs).Column("id", "status").Where("job_group_id = ?", id).Select()
+		if err != nil {
+			return nil, err
+		}
+		snapshot := map[string]interface{}{}
+		for _, job := range jobs {
+			snapshot[job.ID.String()] = job.Status
+		}
+		return snapshot, nil
+	default:
+		return nil, nil
+	}
+	err := a.DB.Select(resource)
+	if err != nil {
+		if err.Error() == RecordNotFoundString {
+			return nil, nil
+		}
+		return nil, err
+	}
+	b, err := json.Marshal(resource)
+	if err != nil {
+		return nil, err
+	}
+	snapshot := map[string]interface{}{}
+	err = json.Unmarshal(b, &snapshot)
+	return snapshot, err
+}
+
+// auditCreated returns the snapshot of the resources created by a request from its response, a
+// request that creates many resources has a snapshot keyed by their ids
+func auditCreated(body []byte) (map[string]interface{}, string) {
+	created := map[string]interface{}{}
+	if json.Unmarshal(body, &created) == nil {
+		for _, field := range auditSecretFields {
+			delete(created, field)
+		}
+		id, _ := created["id"].(string)
+		return created, id
+	}
+	resources := []map[string]interface{}{}
+	if json.Unmarshal(body, &resources) != nil {
+		return nil, ""
+	}
+	for _, resource := range resources {
+		for _, field := range auditSecretFields {
+			delete(resource, field)
+		}
+		if id, ok := resource["id"].(string); ok {
+			created[id] = resource
+		}
+	}
+	return created, ""
+}
+
+// ListAuditLogsHandler is the method called when a get to /audit is called
+func (a *Application) ListAuditLogsHandler(c echo.Context) error {
+	l := a.Logger.With(
+		zap.String("source", "auditHandler"),
+		zap.String("operation", "listAuditLogs"),
+	)
+	auditLogs := []model.AuditLog{}
+	query := a.DB.Model(&auditLogs)
+	if appID := c.QueryParam("appId"); appID != "" {
+		aid, err := uuid.FromString(appID)
+		if err != nil {
+			return c.JSON(http.StatusUnprocessableEntity, &Error{Reason: err.Error()})
+		}
+		query = query.Where("app_id = ?", aid)
+	}
+	if actor := c.QueryParam("actor"); actor != "" {
+		query = query.Where("actor = ?", actor)
+	}
+	for param, condition := range map[string]string{"from": "created_at >= ?", "to": "created_at < ?"} {
+		value := c.QueryParam(param)
+		if value == "" {
+			continue
+		}
+		at, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return c.JSON(http.StatusUnprocessableEntity, &Error{Reason: model.InvalidField(param).Error()})
+		}
+		query = query.Where(condition, at)
+	}
+	limit := 100
+	if value := c.QueryParam("limit"); value != "" {
+		var err error
+		limit, err = strconv.Atoi(value)
+		if err != nil || limit < 1 || limit > 1000 {
+			return c.JSON(http.StatusUnprocessableEntity, &Error{Reason: model.InvalidField("limit").Error()})
+		}
+	}
+	err := WithSegment("db-select", c, func() error {
+		return query.Order("created_at DESC").Limit(limit).Select()
+	})
+	if err != nil {
+		log.E(l, "Failed to list audit logs.", func(cm log.CM) {
+			cm.Write(zap.Error(err))
+		})
+		return c.JSON(http.StatusInternalServerError, &Error{Reason: err.Error()})
+	}
+	return c.JSON(http.StatusOK, auditLogs)
+}
+
+// writeAuditLog appends the audit log of a request, a failure is only logged since the
+// response was already written
+func (a *Application) writeAuditLog(c echo.Context, auditLog *model.AuditLog) {
+	l := a.Logger.With(
+		zap.String("source", "auditHandler"),
+		zap.String("operation", "writeAuditLog"),
+		zap.String("route", auditLog.Route),
+		zap.String("requestId", auditLog.RequestID),
+	)
+	auditLog.ID = uuid.NewV4()
+	auditLog.CreatedAt = time.Now().UnixNano()
+	err := WithSegment("db-insert", c, func() error {
+		return a.DB.Insert(auditLog)
+	})
+	if err != nil {
+		log.E(l, "Failed to write audit log.", func(cm log.CM) {
+			cm.Write(zap.Error(err))
+		})
+	}
+}
@@ -0,0 +1,245 @@
+/*
+ * Copyright (c) 2026 TFG Co <backend@tfgco.com>
+ * Author: TFG Co <backend@tfgco.com>
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy of
+ * this software and associated documentation files (the "Software"), to deal in
+ * the Software without restriction, including without limitation the rights to
+ * use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+ * the Software, and to permit persons to whom the Software is furnished to do so,
+ * subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+ * FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+ * COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+ * IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+ * CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+ */
+
+package api_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	uuid "github.com/satori/go.uuid"
+	"github.com/topfreegames/marathon/model"
+	. "github.com/topfreegames/marathon/testing"
+	"github.com/uber-go/zap"
+)
+
+var _ = Describe("Audit Handler", func() {
+	logger := zap.New(
+		zap.NewJSONEncoder(zap.NoTime()), // drop timestamps in tests
+		zap.FatalLevel,
+	)
+	app := GetDefaultTestApp(logger)
+	var existingApp *model.App
+
+	listAuditLogs := func(query string) []map[string]interface{} {
+		status, body := Get(app, fmt.Sprintf("/audit%s", query), "admin@test.com")
+		Expect(status).To(Equal(http.StatusOK))
+
+		var response []map[string]interface{}
+		err := json.Unmarshal([]byte(body), &response)
+		Expect(err).NotTo(HaveOccurred())
+		return response
+	}
+
+	BeforeEach(func() {
+		app.DB.Exec("DELETE FROM apps;")
+		app.DB.Exec("DELETE FROM users;")
+		app.DB.Exec("TRUNCATE TABLE audit_logs;")
+		CreateTestUser(app.DB, map[string]interface{}{"email": "admin@test.com"})
+		existingApp = CreateTestApp(app.DB)
+	})
+
+	Describe("Audit middleware", func() {
+		It("should write the diff of an updated template", func() {
+			template := CreateTestTemplate(app.DB, existingApp.ID)
+			payload := GetTemplatePayload()
+			pl, _ := json.Marshal(payload)
+			url := fmt.Sprintf("/apps/%s/templates/%s", existingApp.ID, template.ID)
+			status, _ := Put(app, url, string(pl), "admin@test.com")
+			Expect(status).To(Equal(http.StatusOK))
+
+			auditLogs := listAuditLogs("")
+			Expect(auditLogs).To(HaveLen(1))
+			Expect(auditLogs[0]["actor"]).To(Equal("admin@test.com"))
+			Expect(auditLogs[0]["method"]).To(Equal("PUT"))
+			Expect(auditLogs[0]["route"]).To(Equal("/apps/:aid/templates/:tid"))
+			Expect(auditLogs[0]["path"]).To(Equal(url))
+			Expect(auditLogs[0]["appId"]).To(Equal(existingApp.ID.String()))
+			Expect(auditLogs[0]["status"]).To(BeEquivalentTo(http.StatusOK))
+			Expect(auditLogs[0]["requestId"]).NotTo(BeEmpty())
+			Expect(auditLogs[0]["targetIds"]).To(Equal(map[string]interface{}{
+				"aid": existingApp.ID.String(),
+				"tid": template.ID.String(),
+			}))
+
+			diff := auditLogs[0]["diff"].(map[string]interface{})
+			Expect(diff["name"]).To(Equal(map[string]interface{}{
+				"before": template.Name,
+				"after":  payload["name"],
+			}))
+			Expect(diff).NotTo(HaveKey("id"))
+			Expect(diff).NotTo(HaveKey("createdBy"))
+		})
+
+		It("should write the created resource and keep the request id", func() {
+			payload := GetAppPayload()
+			pl, _ := json.Marshal(payload)
+			status, body := RequestWithHeaders(app, "POST", "/apps", string(pl), map[string]string{
+				"x-forwarded-email": "admin@test.com",
+				"X-Request-Id":      "request-1",
+			})
+			Expect(status).To(Equal(http.StatusCreated))
+
+			var created map[string]interface{}
+			err := json.Unmarshal([]byte(body), &created)
+			Expect(err).NotTo(HaveOccurred())
+
+			auditLogs := listAuditLogs("")
+			Expect(auditLogs).To(HaveLen(1))
+			Expect(auditLogs[0]["requestId"]).To(Equal("request-1"))
+			Expect(auditLogs[0]["appId"]).To(Equal(created["id"]))
+			Expect(auditLogs[0]["targetIds"]).To(Equal(map[string]interface{}{"id": created["id"]}))
+			diff := auditLogs[0]["diff"].(map[string]interface{})
+			Expect(diff["name"]).To(Equal(map[string]interface{}{"before": nil, "after": payload["name"]}))
+		})
+
+		It("should write the diff of a deleted resource", func() {
+			status, _ := Delete(app, fmt.Sprintf("/apps/%s", existingApp.ID), "admin@test.com")
+			Expect(status).To(Equal(http.StatusNoContent))
+
+			auditLogs := listAuditLogs("")
+			Expect(auditLogs).To(HaveLen(1))
+			diff := auditLogs[0]["diff"].(map[string]interface{})
+			Expect(diff["name"]).To(Equal(map[string]interface{}{"before": existingApp.Name, "after": nil}))
+		})
+
+		It("should write requests with dryRun to routes that do not support it", func() {
+			status, _ := Delete(app, fmt.Sprintf("/apps/%s?dryRun=true", existingApp.ID), "admin@test.com")
+			Expect(status).To(Equal(http.StatusNoContent))
+
+			auditLogs := listAuditLogs("")
+			Expect(auditLogs).To(HaveLen(1))
+			Expect(auditLogs[0]["targetIds"]).To(Equal(map[string]interface{}{"aid": existingApp.ID.String()}))
+		})
+
+		It("should write the access given to a user", func() {
+			user := CreateTestUser(app.DB, map[string]interface{}{"isAdmin": false})
+			payload := GetUserPayload(map[string]interface{}{
+				"email":       user.Email,
+				"isAdmin":     false,
+				"allowedApps": []uuid.UUID{existingApp.ID},
+			})
+			pl, _ := json.Marshal(payload)
+			status, _ := Put(app, fmt.Sprintf("/users/%s", user.ID), string(pl), "admin@test.com")
+			Expect(status).To(Equal(http.StatusOK))
+
+			auditLogs := listAuditLogs("")
+			Expect(auditLogs).To(HaveLen(1))
+			Expect(auditLogs[0]["targetIds"]).To(Equal(map[string]interface{}{"uid": user.ID.String()}))
+			diff := auditLogs[0]["diff"].(map[string]interface{})
+			Expect(diff["allowedApps"]).To(Equal(map[string]interface{}{
+				"before": []interface{}{user.AllowedApps[0].String()},
+				"after":  []interface{}{existingApp.ID.String()},
+			}))
+			Expect(diff).NotTo(HaveKey("isAdmin"))
+		})
+
+		It("should not write created API tokens", func() {
+			user := CreateTestUser(app.DB)
+			status, body := Post(app, fmt.Sprintf("/users/%s/tokens", user.ID), `{"name": "ci", "scopes": ["viewer"]}`, "admin@test.com")
+			Expect(status).To(Equal(http.StatusCreated))
+
+			var created map[string]interface{}
+			err := json.Unmarshal([]byte(body), &created)
+			Expect(err).NotTo(HaveOccurred())
+
+			auditLogs := listAuditLogs("")
+			Expect(auditLogs).To(HaveLen(1))
+			Expect(auditLogs[0]["diff"]).To(HaveKey("name"))
+			Expect(auditLogs[0]["diff"]).NotTo(HaveKey("token"))
+		})
+
+		It("should not write requests that do not change anything", func() {
+			template := CreateTestTemplate(app.DB, existingApp.ID)
+			status, _ := Get(app, fmt.Sprintf("/apps/%s/templates/%s", existingApp.ID, template.ID), "admin@test.com")
+			Expect(status).To(Equal(http.StatusOK))
+			status, _ = Post(app, fmt.Sprintf("/apps/%s/templates/%s/preview", existingApp.ID, template.ID), "{}", "admin@test.com")
+			Expect(status).NotTo(Equal(http.StatusUnauthorized))
+			document := fmt.Sprintf(`{"name": "%s", "locales": [{"locale": "en", "body": {"alert": "hi"}}]}`, template.Name)
+			status, _ = Post(app, fmt.Sprintf("/apps/%s/templates/import?dryRun=true", existingApp.ID), document, "admin@test.com")
+			Expect(status).To(Equal(http.StatusOK))
+
+			Expect(listAuditLogs("")).To(BeEmpty())
+		})
+
+		It("should not allow audit logs to be changed", func() {
+			status, _ := Delete(app, fmt.Sprintf("/apps/%s", existingApp.ID), "admin@test.com")
+			Expect(status).To(Equal(http.StatusNoContent))
+
+			_, err := app.DB.Exec("UPDATE audit_logs SET actor = 'other@test.com'")
+			Expect(err).NotTo(HaveOccurred())
+			_, err = app.DB.Exec("DELETE FROM audit_logs")
+			Expect(err).NotTo(HaveOccurred())
+
+			auditLogs := listAuditLogs("")
+			Expect(auditLogs).To(HaveLen(1))
+			Expect(auditLogs[0]["actor"]).To(Equal("admin@test.com"))
+		})
+	})
+
+	Describe("Get /audit", func() {
+		BeforeEach(func() {
+			CreateTestUser(app.DB, map[string]interface{}{"email": "other@test.com"})
+			otherApp := CreateTestApp(app.DB)
+			Put(app, fmt.Sprintf("/apps/%s", existingApp.ID), `{"name": "first", "bundleId": "com.app.first"}`, "admin@test.com")
+			Put(app, fmt.Sprintf("/apps/%s", otherApp.ID), `{"name": "second", "bundleId": "com.app.second"}`, "other@test.com")
+		})
+
+		It("should return the audit logs of the app", func() {
+			auditLogs := listAuditLogs(fmt.Sprintf("?appId=%s", existingApp.ID))
+			Expect(auditLogs).To(HaveLen(1))
+			Expect(auditLogs[0]["appId"]).To(Equal(existingApp.ID.String()))
+		})
+
+		It("should return the audit logs of the actor", func() {
+			auditLogs := listAuditLogs("?actor=other@test.com")
+			Expect(auditLogs).To(HaveLen(1))
+			Expect(auditLogs[0]["actor"]).To(Equal("other@test.com"))
+		})
+
+		It("should return the audit logs of the time range", func() {
+			Expect(listAuditLogs(fmt.Sprintf("?from=%d", time.Now().Add(-time.Minute).UnixNano()))).To(HaveLen(2))
+			Expect(listAuditLogs(fmt.Sprintf("?to=%d", time.Now().Add(-time.Minute).UnixNano()))).To(BeEmpty())
+			Expect(listAuditLogs("?limit=1")).To(HaveLen(1))
+		})
+
+		It("should return 422 if the filters are invalid", func() {
+			status, body := Get(app, "/audit?from=yesterday", "admin@test.com")
+			Expect(status).To(Equal(http.StatusUnprocessableEntity))
+
+			var response map[string]interface{}
+			err := json.Unmarshal([]byte(body), &response)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(response["reason"]).To(Equal("invalid from"))
+		})
+
+		It("should return 403 if the user is not an admin", func() {
+			CreateTestUser(app.DB, map[string]interface{}{"email": "user@test.com", "isAdmin": false})
+			status, _ := Get(app, "/audit", "user@test.com")
+			Expect(status).To(Equal(http.StatusForbidden))
+		})
+	})
+})
@@ -25,6 +25,8 @@ package api
 import (
 	"github.com/labstack/echo/v4"
 	newrelic "github.com/newrelic/go-agent"
+	"github.com/topfreegames/marathon/interfaces"
+	pg "gopkg.in/pg.v5"
 )
 
 // RecordNotFoundString is the string returned when a record is not found
@@ -56,3 +58,17 @@ func WithSegment(name string, c echo.Context, f func() error) error {
 	defer segment.End()
 	return f()
 }
+
+// withTransaction runs f in a database transaction, which is rolled back if f fails
+func withTransaction(db interfaces.DB, f func(tx *pg.Tx) error) error {
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	err = f(tx)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
@@ -0,0 +1,158 @@
+/*
+ * Copyright (c) 2026 TFG Co <backend@tfgco.com>
+ * Author: TFG Co <backend@tfgco.com>
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy of
+ * this software and associated documentation files (the "Software"), to deal in
+ * the Software without restriction, including without limitation the rights to
+ * use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+ * the Software, and to permit persons to whom the Software is furnished to do so,
+ * subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+ * FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+ * COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+ * IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+ * CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+ */
+
+package api
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	uuid "github.com/satori/go.uuid"
+	"github.com/topfreegames/marathon/log"
+	"github.com/topfreegames/marathon/model"
+	"github.com/topfreegames/marathon/worker"
+	"github.com/uber-go/zap"
+)
+
+const holdoutExportPageSize = 10000
+
+// HoldoutResponse is the response of the holdout route
+type HoldoutResponse struct {
+	HoldoutPercentage float64 `json:"holdoutPercentage"`
+	HoldoutBuckets    int     `json:"holdoutBuckets"`
+	UserID            string  `json:"userId,omitempty"`
+	Bucket            *int    `json:"bucket,omitempty"`
+	InHoldout         *bool   `json:"inHoldout,omitempty"`
+}
+
+func (a *Application) getHoldoutApp(c echo.Context, l zap.Logger) (*model.App, bool, error) {
+	aid, err := uuid.FromString(c.Param("aid"))
+	if err != nil {
+		return nil, true, c.JSON(http.StatusUnprocessableEntity, &Error{Reason: err.Error()})
+	}
+	app := &model.App{ID: aid}
+	err = WithSegment("db-select", c, func() error {
+		return a.DB.Select(&app)
+	})
+	if err != nil {
+		if err.Error() == RecordNotFoundString {
+			return nil, true, c.JSON(http.StatusNotFound, map[string]string{})
+		}
+		log.E(l, "Failed to retrieve app.", func(cm log.CM) {
+			cm.Write(zap.Error(err))
+		})
+		return nil, true, c.JSON(http.StatusInternalServerError, &Error{Reason: err.Error(), Value: app})
+	}
+	return app, false, nil
+}
+
+// GetHoldoutHandler is the method called when a get to /apps/:aid/holdout is called
+func (a *Application) GetHoldoutHandler(c echo.Context) error {
+	l := a.Logger.With(
+		zap.String("source", "holdoutHandler"),
+		zap.String("operation", "getHoldout"),
+		zap.String("appId", c.Param("aid")),
+	)
+	app, skip, err := a.getHoldoutApp(c, l)
+	if err != nil || skip {
+		return err
+	}
+
+	res := &HoldoutResponse{
+		HoldoutPercentage: app.HoldoutPercentage,
+		HoldoutBuckets:    model.HoldoutBuckets,
+	}
+	if userID := c.QueryParam("userId"); userID != "" {
+		bucket := app.HoldoutBucket(userID)
+		inHoldout := app.InHoldout(userID)
+		res.UserID = userID
+		res.Bucket = &bucket
+		res.InHoldout = &inHoldout
+	}
+	log.D(l, "Retrieved holdout successfully.", func(cm log.CM) {
+		cm.Write(zap.Object("holdout", res))
+	})
+	return c.JSON(http.StatusOK, res)
+}
+
+// ExportHoldoutHandler is the method called when a get to /apps/:aid/holdout/export is called,
+// it writes a csv with the users of the push db table of the service that are in the holdout group
+func (a *Application) ExportHoldoutHandler(c echo.Context) error {
+	l := a.Logger.With(
+		zap.String("source", "holdoutHandler"),
+		zap.String("operation", "exportHoldout"),
+		zap.String("appId", c.Param("aid")),
+	)
+	service := c.QueryParam("service")
+	if service != "apns" && service != "gcm" {
+		return c.JSON(http.StatusUnprocessableEntity, &Error{Reason: model.InvalidField("service").Error()})
+	}
+	app, skip, err := a.getHoldoutApp(c, l)
+	if err != nil || skip {
+		return err
+	}
+
+	tableName := worker.GetPushDBTableName(app.Name, service)
+	var ids []string
+	err = WithSegment("db-select", c, func() error {
+		ids, err = worker.GetUserIDsPage(a.PushDB, tableName, "", holdoutExportPageSize)
+		return err
+	})
+	if err != nil {
+		log.E(l, "Failed to retrieve users.", func(cm log.CM) {
+			cm.Write(zap.Error(err))
+		})
+		return c.JSON(http.StatusInternalServerError, &Error{Reason: err.Error(), Value: app})
+	}
+
+	res := c.Response()
+	res.Header().Set(echo.HeaderContentType, "text/csv")
+	res.Header().Set(echo.HeaderContentDisposition, fmt.Sprintf("attachment; filename=holdout-%s-%s.csv", app.Name, service))
+	res.WriteHeader(http.StatusOK)
+	res.Write([]byte("userId\n"))
+	exported := 0
+	for len(ids) > 0 {
+		for _, id := range ids {
+			if app.InHoldout(id) {
+				res.Write([]byte(fmt.Sprintf("%s\n", id)))
+				exported++
+			}
+		}
+		res.Flush()
+		if len(ids) < holdoutExportPageSize {
+			break
+		}
+		ids, err = worker.GetUserIDsPage(a.PushDB, tableName, ids[len(ids)-1], holdoutExportPageSize)
+		if err != nil {
+			// the response was already started, the csv is left incomplete
+			log.E(l, "Failed to retrieve users.", func(cm log.CM) {
+				cm.Write(zap.Error(err))
+			})
+			return err
+		}
+	}
+	log.D(l, "Exported holdout successfully.", func(cm log.CM) {
+		cm.Write(zap.Int("exported", exported))
+	})
+	return nil
+}
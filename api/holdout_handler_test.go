@@ -0,0 +1,121 @@
+/*
+ * Copyright (c) 2026 TFG Co <backend@tfgco.com>
+ * Author: TFG Co <backend@tfgco.com>
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy of
+ * this software and associated documentation files (the "Software"), to deal in
+ * the Software without restriction, including without limitation the rights to
+ * use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+ * the Software, and to permit persons to whom the Software is furnished to do so,
+ * subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+ * FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+ * COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+ * IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+ * CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+ */
+
+package api_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	uuid "github.com/satori/go.uuid"
+	"github.com/topfreegames/marathon/model"
+	. "github.com/topfreegames/marathon/testing"
+	"github.com/uber-go/zap"
+	"gopkg.in/pg.v5"
+)
+
+var _ = Describe("Holdout Handler", func() {
+	logger := zap.New(
+		zap.NewJSONEncoder(zap.NoTime()), // drop timestamps in tests
+		zap.FatalLevel,
+	)
+	app := GetDefaultTestApp(logger)
+	var existingApp *model.App
+	var baseRoute string
+
+	BeforeEach(func() {
+		app.DB.Exec("DELETE FROM apps;")
+		app.DB.Exec("DELETE FROM users;")
+		CreateTestUser(app.DB, map[string]interface{}{"email": "test@test.com", "isAdmin": true})
+
+		existingApp = CreateTestApp(app.DB, map[string]interface{}{
+			"holdoutPercentage": 0.5,
+			"holdoutSalt":       "salt",
+		})
+		baseRoute = fmt.Sprintf("/apps/%s/holdout", existingApp.ID)
+	})
+
+	Describe("Get /apps/:aid/holdout", func() {
+		It("should return 200 and the app holdout", func() {
+			status, body := Get(app, baseRoute, "test@test.com")
+			Expect(status).To(Equal(http.StatusOK))
+
+			var response map[string]interface{}
+			err := json.Unmarshal([]byte(body), &response)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(response["holdoutPercentage"]).To(Equal(0.5))
+			Expect(response["holdoutBuckets"]).To(BeEquivalentTo(model.HoldoutBuckets))
+			Expect(response).NotTo(HaveKey("inHoldout"))
+		})
+
+		It("should return 200 and the holdout membership of the user", func() {
+			status, body := Get(app, fmt.Sprintf("%s?userId=user1", baseRoute), "test@test.com")
+			Expect(status).To(Equal(http.StatusOK))
+
+			var response map[string]interface{}
+			err := json.Unmarshal([]byte(body), &response)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(response["userId"]).To(Equal("user1"))
+			Expect(response["bucket"]).To(BeEquivalentTo(existingApp.HoldoutBucket("user1")))
+			Expect(response["inHoldout"]).To(Equal(existingApp.InHoldout("user1")))
+		})
+
+		It("should return 404 if the app does not exist", func() {
+			status, _ := Get(app, fmt.Sprintf("/apps/%s/holdout", uuid.NewV4()), "test@test.com")
+			Expect(status).To(Equal(http.StatusNotFound))
+		})
+	})
+
+	Describe("Get /apps/:aid/holdout/export", func() {
+		It("should return 200 and a csv with the users of the push db in the holdout", func() {
+			var ids pg.Strings
+			_, err := app.PushDB.Query(&ids, "SELECT DISTINCT user_id FROM testapp_apns")
+			Expect(err).NotTo(HaveOccurred())
+			expected := []string{}
+			for _, id := range ids {
+				if existingApp.InHoldout(id) {
+					expected = append(expected, id)
+				}
+			}
+
+			status, body := Get(app, fmt.Sprintf("%s/export?service=apns", baseRoute), "test@test.com")
+			Expect(status).To(Equal(http.StatusOK))
+			lines := strings.Split(strings.TrimSpace(body), "\n")
+			Expect(lines[0]).To(Equal("userId"))
+			Expect(lines[1:]).To(ConsistOf(expected))
+		})
+
+		It("should return 422 if invalid service", func() {
+			status, body := Get(app, fmt.Sprintf("%s/export?service=sms", baseRoute), "test@test.com")
+			Expect(status).To(Equal(http.StatusUnprocessableEntity))
+
+			var response map[string]interface{}
+			err := json.Unmarshal([]byte(body), &response)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(response["reason"]).To(Equal("invalid service"))
+		})
+	})
+})
@@ -24,6 +24,7 @@ package api
 
 import (
 	"encoding/json"
+	"io/ioutil"
 
 	"github.com/labstack/echo/v4"
 
@@ -56,3 +57,21 @@ func decodeAndValidate(c echo.Context, v InputValidation) error {
 	}
 	return v.Validate(c)
 }
+
+// decodeAndValidateFields works like decodeAndValidate and also returns the
+// json fields present in the body, so updates can leave out the missing ones
+func decodeAndValidateFields(c echo.Context, v InputValidation) (map[string]json.RawMessage, error) {
+	defer c.Request().Body.Close()
+	body, err := ioutil.ReadAll(c.Request().Body)
+	if err != nil {
+		return nil, err
+	}
+	fields := map[string]json.RawMessage{}
+	if err := json.Unmarshal(body, &fields); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(body, v); err != nil {
+		return nil, err
+	}
+	return fields, v.Validate(c)
+}
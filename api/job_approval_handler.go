@@ -0,0 +1,208 @@
+/*
+ * Copyright (c) 2026 TFG Co <backend@tfgco.com>
+ * Author: TFG Co <backend@tfgco.com>
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy of
+ * this software and associated documentation files (the "Software"), to deal in
+ * the Software without restriction, including without limitation the rights to
+ * use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+ * the Software, and to permit persons to whom the Software is furnished to do so,
+ * subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+ * FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+ * COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+ * IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+ * CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+ */
+
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	uuid "github.com/satori/go.uuid"
+	"github.com/topfreegames/marathon/email"
+	"github.com/topfreegames/marathon/log"
+	"github.com/topfreegames/marathon/model"
+	"github.com/uber-go/zap"
+)
+
+const (
+	pendingApprovalJobStatus = "pending_approval"
+	rejectedJobStatus        = "rejected"
+)
+
+// getJobToReview returns the job of the route if it is pending approval and the user can
+// review it, it returns skip if the response was already written
+func (a *Application) getJobToReview(c echo.Context, l zap.Logger) (*model.Job, bool, error) {
+	aid, err := uuid.FromString(c.Param("aid"))
+	if err != nil {
+		return nil, true, c.JSON(http.StatusUnprocessableEntity, &Error{Reason: err.Error()})
+	}
+	jid, err := uuid.FromString(c.Param("jid"))
+	if err != nil {
+		return nil, true, c.JSON(http.StatusUnprocessableEntity, &Error{Reason: err.Error()})
+	}
+	userEmail := c.Get("user-email").(string)
+
+	user := &model.User{}
+	err = WithSegment("db-select", c, func() error {
+		return a.DB.Model(&user).Column("*").Where("email = ?", userEmail).Select()
+	})
+	if err != nil {
+		log.E(l, "Failed to retrieve user.", func(cm log.CM) {
+			cm.Write(zap.Error(err))
+		})
+		return nil, true, c.JSON(http.StatusInternalServerError, &Error{Reason: err.Error()})
+	}
+	if !user.CanApprove(aid) {
+		return nil, true, c.JSON(http.StatusForbidden, &Error{Reason: "user is not an approver"})
+	}
+
+	job := &model.Job{}
+	err = WithSegment("db-select", c, func() error {
+		return a.DB.Model(&job).Column("job.*", "App").Where("job.id = ? AND job.app_id = ?", jid, aid).Select()
+	})
+	if err != nil {
+		if err.Error() == RecordNotFoundString {
+			return nil, true, c.JSON(http.StatusNotFound, map[string]string{})
+		}
+		log.E(l, "Failed to retrieve job.", func(cm log.CM) {
+			cm.Write(zap.Error(err))
+		})
+		return nil, true, c.JSON(http.StatusInternalServerError, &Error{Reason: err.Error(), Value: job})
+	}
+	if job.Status != pendingApprovalJobStatus {
+		return nil, true, c.JSON(http.StatusConflict, &Error{Reason: "job is not pending approval", Value: job})
+	}
+	if job.CreatedBy == userEmail {
+		return nil, true, c.JSON(http.StatusForbidden, &Error{Reason: "job cannot be reviewed by its creator", Value: job})
+	}
+	return job, false, nil
+}
+
+// updateReviewedJob changes the status of the job if it is still pending approval, so a job
+// reviewed at the same time by two users is only approved or rejected once
+func (a *Application) updateReviewedJob(c echo.Context, job *model.Job, status interface{}) (bool, error) {
+	job.UpdatedAt = time.Now().UnixNano()
+	var updated int
+	err := WithSegment("db-update", c, func() error {
+		res, err := a.DB.Model(job).
+			Set("status = ?, updated_at = ?", status, job.UpdatedAt).
+			Where("id = ? AND status = ?", job.ID, pendingApprovalJobStatus).
+			Update()
+		if err != nil {
+			return err
+		}
+		updated = res.RowsAffected()
+		return nil
+	})
+	return updated > 0, err
+}
+
+// ApproveJobHandler is the method called when a put to apps/:id/jobs/:jid/approve is called
+func (a *Application) ApproveJobHandler(c echo.Context) error {
+	l := a.Logger.With(
+		zap.String("source", "jobApprovalHandler"),
+		zap.String("operation", "approveJob"),
+		zap.String("appId", c.Param("aid")),
+		zap.String("jobId", c.Param("jid")),
+	)
+	job, skip, err := a.getJobToReview(c, l)
+	if err != nil || skip {
+		return err
+	}
+	userEmail := c.Get("user-email").(string)
+
+	updated, err := a.updateReviewedJob(c, job, nil)
+	if err != nil {
+		log.E(l, "Failed to approve job.", func(cm log.CM) {
+			cm.Write(zap.Error(err))
+		})
+		return c.JSON(http.StatusInternalServerError, &Error{Reason: err.Error(), Value: job})
+	}
+	if !updated {
+		return c.JSON(http.StatusConflict, &Error{Reason: "job is not pending approval", Value: job})
+	}
+	job.Status = ""
+
+	err = WithSegment("create-job-workers", c, func() error {
+		return a.createJobWorkers(job, c)
+	})
+	if err != nil {
+		log.E(l, "Failed to create approved job workers.", func(cm log.CM) {
+			cm.Write(zap.Error(err))
+		})
+		return c.JSON(http.StatusInternalServerError, &Error{Reason: err.Error(), Value: job})
+	}
+	job.TagSuccess(a.DB, "approval", fmt.Sprintf("approved by %s", userEmail))
+	log.I(l, "Approved job successfully.", func(cm log.CM) {
+		cm.Write(zap.String("approvedBy", userEmail))
+	})
+
+	if a.SendgridClient != nil {
+		err := email.SendApprovedJobEmail(a.SendgridClient, job, job.App.Name, userEmail)
+		if err != nil {
+			log.E(l, "Failed to send email with approved job info.", func(cm log.CM) {
+				cm.Write(zap.Error(err))
+			})
+		}
+	}
+	return c.JSON(http.StatusOK, job)
+}
+
+// RejectJobHandler is the method called when a put to apps/:id/jobs/:jid/reject is called
+func (a *Application) RejectJobHandler(c echo.Context) error {
+	l := a.Logger.With(
+		zap.String("source", "jobApprovalHandler"),
+		zap.String("operation", "rejectJob"),
+		zap.String("appId", c.Param("aid")),
+		zap.String("jobId", c.Param("jid")),
+	)
+	rejection := &model.JobRejection{}
+	err := WithSegment("decodeAndValidate", c, func() error {
+		return decodeAndValidate(c, rejection)
+	})
+	if err != nil {
+		return c.JSON(http.StatusUnprocessableEntity, &Error{Reason: err.Error(), Value: rejection})
+	}
+	job, skip, err := a.getJobToReview(c, l)
+	if err != nil || skip {
+		return err
+	}
+	userEmail := c.Get("user-email").(string)
+
+	updated, err := a.updateReviewedJob(c, job, rejectedJobStatus)
+	if err != nil {
+		log.E(l, "Failed to reject job.", func(cm log.CM) {
+			cm.Write(zap.Error(err))
+		})
+		return c.JSON(http.StatusInternalServerError, &Error{Reason: err.Error(), Value: job})
+	}
+	if !updated {
+		return c.JSON(http.StatusConflict, &Error{Reason: "job is not pending approval", Value: job})
+	}
+	job.Status = rejectedJobStatus
+	job.TagError(a.DB, "approval", fmt.Sprintf("rejected by %s: %s", userEmail, rejection.Reason))
+	log.I(l, "Rejected job successfully.", func(cm log.CM) {
+		cm.Write(zap.String("rejectedBy", userEmail))
+	})
+
+	if a.SendgridClient != nil {
+		err := email.SendRejectedJobEmail(a.SendgridClient, job, job.App.Name, userEmail, rejection.Reason)
+		if err != nil {
+			log.E(l, "Failed to send email with rejected job info.", func(cm log.CM) {
+				cm.Write(zap.Error(err))
+			})
+		}
+	}
+	return c.JSON(http.StatusOK, job)
+}
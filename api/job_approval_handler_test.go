@@ -0,0 +1,193 @@
+/*
+ * Copyright (c) 2026 TFG Co <backend@tfgco.com>
+ * Author: TFG Co <backend@tfgco.com>
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy of
+ * this software and associated documentation files (the "Software"), to deal in
+ * the Software without restriction, including without limitation the rights to
+ * use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+ * the Software, and to permit persons to whom the Software is furnished to do so,
+ * subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+ * FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+ * COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+ * IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+ * CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+ */
+
+package api_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	uuid "github.com/satori/go.uuid"
+	"github.com/topfreegames/marathon/model"
+	. "github.com/topfreegames/marathon/testing"
+	"github.com/uber-go/zap"
+)
+
+var _ = Describe("Job Approval Handler", func() {
+	logger := zap.New(
+		zap.NewJSONEncoder(zap.NoTime()), // drop timestamps in tests
+		zap.FatalLevel,
+	)
+	app := GetDefaultTestApp(logger)
+	var existingApp *model.App
+	var existingTemplate *model.Template
+	var job *model.Job
+	var baseRoute string
+
+	BeforeEach(func() {
+		app.DB.Exec("DELETE FROM apps;")
+		app.DB.Exec("DELETE FROM users;")
+		app.Worker.RedisClient.FlushAll()
+
+		existingApp = CreateTestApp(app.DB, map[string]interface{}{"requiresApproval": true})
+		existingTemplate = CreateTestTemplate(app.DB, existingApp.ID, map[string]interface{}{
+			"locale": "en",
+		})
+		CreateTestUser(app.DB, map[string]interface{}{"email": "creator@test.com", "isAdmin": true})
+		CreateTestUser(app.DB, map[string]interface{}{
+			"email":       "approver@test.com",
+			"isAdmin":     false,
+			"isApprover":  true,
+			"allowedApps": []uuid.UUID{existingApp.ID},
+		})
+		CreateTestUser(app.DB, map[string]interface{}{
+			"email":       "user@test.com",
+			"isAdmin":     false,
+			"allowedApps": []uuid.UUID{existingApp.ID},
+		})
+
+		job = CreateTestJob(app.DB, existingApp.ID, existingTemplate.Name, map[string]interface{}{
+			"createdBy": "creator@test.com",
+		})
+		_, err := app.DB.Model(job).Set("status = 'pending_approval'").Update()
+		Expect(err).NotTo(HaveOccurred())
+		baseRoute = fmt.Sprintf("/apps/%s/jobs/%s", existingApp.ID, job.ID)
+	})
+
+	Describe("Post /apps/:aid/jobs", func() {
+		It("should create the job pending approval without sending it", func() {
+			payload := GetJobPayload()
+			pl, _ := json.Marshal(payload)
+			route := fmt.Sprintf("/apps/%s/jobs?template=%s", existingApp.ID, existingTemplate.Name)
+			status, body := Post(app, route, string(pl), "creator@test.com")
+			Expect(status).To(Equal(http.StatusCreated))
+
+			var response map[string]interface{}
+			err := json.Unmarshal([]byte(body), &response)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(response["status"]).To(Equal("pending_approval"))
+
+			scheduled, err := app.Worker.RedisClient.ZCard("schedule").Result()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(scheduled).To(BeEquivalentTo(0))
+		})
+	})
+
+	Describe("Put /apps/:aid/jobs/:jid/approve", func() {
+		It("should return 200 and send the approved job", func() {
+			status, body := Put(app, fmt.Sprintf("%s/approve", baseRoute), "", "approver@test.com")
+			Expect(status).To(Equal(http.StatusOK))
+
+			var response map[string]interface{}
+			err := json.Unmarshal([]byte(body), &response)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(response["status"]).To(Equal(""))
+
+			dbJob := &model.Job{ID: job.ID}
+			err = app.DB.Select(dbJob)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(dbJob.Status).To(Equal(""))
+
+			scheduled, err := app.Worker.RedisClient.ZCard("schedule").Result()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(scheduled).To(BeNumerically(">", 0))
+
+			var statuses []*model.Status
+			err = app.DB.Model(&statuses).Where("job_id = ?", job.ID).Column("status.*", "Events").Select()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(statuses).To(HaveLen(1))
+			Expect(statuses[0].Name).To(Equal("approval"))
+			Expect(statuses[0].Events[0].State).To(Equal("success"))
+			Expect(statuses[0].Events[0].Message).To(Equal("approved by approver@test.com"))
+		})
+
+		It("should return 403 if the user is the job creator", func() {
+			status, _ := Put(app, fmt.Sprintf("%s/approve", baseRoute), "", "creator@test.com")
+			Expect(status).To(Equal(http.StatusForbidden))
+		})
+
+		It("should return 403 if the user is not an approver", func() {
+			status, body := Put(app, fmt.Sprintf("%s/approve", baseRoute), "", "user@test.com")
+			Expect(status).To(Equal(http.StatusForbidden))
+
+			var response map[string]interface{}
+			err := json.Unmarshal([]byte(body), &response)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(response["reason"]).To(Equal("user is not an approver"))
+		})
+
+		It("should return 409 if the job is not pending approval", func() {
+			status, _ := Put(app, fmt.Sprintf("%s/approve", baseRoute), "", "approver@test.com")
+			Expect(status).To(Equal(http.StatusOK))
+
+			status, _ = Put(app, fmt.Sprintf("%s/approve", baseRoute), "", "approver@test.com")
+			Expect(status).To(Equal(http.StatusConflict))
+		})
+
+		It("should return 404 if the job does not exist", func() {
+			route := fmt.Sprintf("/apps/%s/jobs/%s/approve", existingApp.ID, uuid.NewV4())
+			status, _ := Put(app, route, "", "approver@test.com")
+			Expect(status).To(Equal(http.StatusNotFound))
+		})
+	})
+
+	Describe("Put /apps/:aid/jobs/:jid/reject", func() {
+		It("should return 200 and reject the job", func() {
+			pl, _ := json.Marshal(map[string]interface{}{"reason": "wrong template"})
+			status, body := Put(app, fmt.Sprintf("%s/reject", baseRoute), string(pl), "approver@test.com")
+			Expect(status).To(Equal(http.StatusOK))
+
+			var response map[string]interface{}
+			err := json.Unmarshal([]byte(body), &response)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(response["status"]).To(Equal("rejected"))
+
+			scheduled, err := app.Worker.RedisClient.ZCard("schedule").Result()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(scheduled).To(BeEquivalentTo(0))
+
+			var statuses []*model.Status
+			err = app.DB.Model(&statuses).Where("job_id = ?", job.ID).Column("status.*", "Events").Select()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(statuses).To(HaveLen(1))
+			Expect(statuses[0].Events[0].State).To(Equal("fail"))
+			Expect(statuses[0].Events[0].Message).To(Equal("rejected by approver@test.com: wrong template"))
+
+			status, _ = Put(app, fmt.Sprintf("%s/approve", baseRoute), "", "approver@test.com")
+			Expect(status).To(Equal(http.StatusConflict))
+		})
+
+		It("should return 422 if the reason is missing", func() {
+			pl, _ := json.Marshal(map[string]interface{}{})
+			status, body := Put(app, fmt.Sprintf("%s/reject", baseRoute), string(pl), "approver@test.com")
+			Expect(status).To(Equal(http.StatusUnprocessableEntity))
+
+			var response map[string]interface{}
+			err := json.Unmarshal([]byte(body), &response)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(response["reason"]).To(Equal("invalid reason"))
+		})
+	})
+})
@@ -0,0 +1,245 @@
+/*
+ * Copyright (c) 2026 TFG Co <backend@tfgco.com>
+ * Author: TFG Co <backend@tfgco.com>
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy of
+ * this software and associated documentation files (the "Software"), to deal in
+ * the Software without restriction, including without limitation the rights to
+ * use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+ * the Software, and to permit persons to whom the Software is furnished to do so,
+ * subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+ * FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+ * COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+ * IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+ * CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+ */
+
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	uuid "github.com/satori/go.uuid"
+	"github.com/topfreegames/marathon/log"
+	"github.com/topfreegames/marathon/model"
+	"github.com/uber-go/zap"
+)
+
+// getJobGroup returns the job group of the route with its jobs and totals, it returns skip if
+// the response was already written
+func (a *Application) getJobGroup(c echo.Context, l zap.Logger) (*model.JobGroup, bool, error) {
+	aid, err := uuid.FromString(c.Param("aid"))
+	if err != nil {
+		return nil, true, c.JSON(http.StatusUnprocessableEntity, &Error{Reason: err.Error()})
+	}
+	gid, err := uuid.FromString(c.Param("gid"))
+	if err != nil {
+		return nil, true, c.JSON(http.StatusUnprocessableEntity, &Error{Reason: err.Error()})
+	}
+
+	jobGroup := &model.JobGroup{}
+	err = WithSegment("db-select", c, func() error {
+		return a.DB.Model(jobGroup).Where("id = ?", gid).Where("app_id = ?", aid).Select()
+	})
+	if err != nil {
+		if err.Error() == RecordNotFoundString {
+			return nil, true, c.JSON(http.StatusNotFound, map[string]string{})
+		}
+		log.E(l, "Failed to retrieve job group.", func(cm log.CM) {
+			cm.Write(zap.Error(err))
+		})
+		return nil, true, c.JSON(http.StatusInternalServerError, &Error{Reason: err.Error()})
+	}
+
+	jobs := []*model.Job{}
+	err = WithSegment("db-select", c, func() error {
+		return a.DB.Model(&jobs).Column("job.*", "App").Where("job.job_group_id = ?", gid).Order("job.starts_at").Select()
+	})
+	if err != nil {
+		log.E(l, "Failed to list job group jobs.", func(cm log.CM) {
+			cm.Write(zap.Error(err))
+		})
+		return nil, true, c.JSON(http.StatusInternalServerError, &Error{Reason: err.Error()})
+	}
+	jobGroup.Jobs = jobs
+	jobGroup.Aggregate()
+	return jobGroup, false, nil
+}
+
+// updateJobGroupStatus sets the status of the jobs of the group that match the condition in a
+// single statement, so every job or none is changed, and returns the changed jobs
+func (a *Application) updateJobGroupStatus(c echo.Context, jobGroup *model.JobGroup, status interface{}, condition string) ([]model.Job, error) {
+	jobs := []model.Job{}
+	err := WithSegment("db-update", c, func() error {
+		_, err := a.DB.Query(&jobs, fmt.Sprintf(
+			"UPDATE jobs SET status = ?, updated_at = ? WHERE job_group_id = ? AND coalesce(completed_at, 0) = 0 AND %s RETURNING *",
+			condition,
+		), status, time.Now().UnixNano(), jobGroup.ID)
+		return err
+	})
+	return jobs, err
+}
+
+// GetJobGroupHandler is the method called when a get to /apps/:aid/jobgroups/:gid is called
+func (a *Application) GetJobGroupHandler(c echo.Context) error {
+	l := a.Logger.With(
+		zap.String("source", "jobGroupHandler"),
+		zap.String("operation", "getJobGroup"),
+		zap.String("appId", c.Param("aid")),
+		zap.String("jobGroupId", c.Param("gid")),
+	)
+	jobGroup, skip, err := a.getJobGroup(c, l)
+	if err != nil || skip {
+		return err
+	}
+	log.D(l, "Retrieved job group successfully.", func(cm log.CM) {
+		cm.Write(zap.Int("jobs", len(jobGroup.Jobs)))
+	})
+	return c.JSON(http.StatusOK, jobGroup)
+}
+
+// ListJobGroupJobsHandler is the method called when a get to /apps/:aid/jobgroups/:gid/jobs is called
+func (a *Application) ListJobGroupJobsHandler(c echo.Context) error {
+	l := a.Logger.With(
+		zap.String("source", "jobGroupHandler"),
+		zap.String("operation", "listJobGroupJobs"),
+		zap.String("appId", c.Param("aid")),
+		zap.String("jobGroupId", c.Param("gid")),
+	)
+	jobGroup, skip, err := a.getJobGroup(c, l)
+	if err != nil || skip {
+		return err
+	}
+	log.D(l, "Listed job group jobs successfully.", func(cm log.CM) {
+		cm.Write(zap.Int("jobs", len(jobGroup.Jobs)))
+	})
+	return c.JSON(http.StatusOK, jobGroup.Jobs)
+}
+
+// PauseJobGroupHandler is the method called when a put to /apps/:aid/jobgroups/:gid/pause is called
+func (a *Application) PauseJobGroupHandler(c echo.Context) error {
+	l := a.Logger.With(
+		zap.String("source", "jobGroupHandler"),
+		zap.String("operation", "pauseJobGroup"),
+		zap.String("appId", c.Param("aid")),
+		zap.String("jobGroupId", c.Param("gid")),
+	)
+	jobGroup, skip, err := a.getJobGroup(c, l)
+	if err != nil || skip {
+		return err
+	}
+	jobs, err := a.updateJobGroupStatus(c, jobGroup, "paused", "coalesce(status, '') = ''")
+	if err != nil {
+		log.E(l, "Failed to pause job group.", func(cm log.CM) {
+			cm.Write(zap.Error(err))
+		})
+		return c.JSON(http.StatusInternalServerError, &Error{Reason: err.Error()})
+	}
+	if len(jobs) == 0 {
+		return c.JSON(http.StatusForbidden, &Error{Reason: "no job of the group can be paused"})
+	}
+	log.I(l, "Paused job group successfully.", func(cm log.CM) {
+		cm.Write(zap.Int("jobs", len(jobs)))
+	})
+	return a.GetJobGroupHandler(c)
+}
+
+// StopJobGroupHandler is the method called when a put to /apps/:aid/jobgroups/:gid/stop is called
+func (a *Application) StopJobGroupHandler(c echo.Context) error {
+	l := a.Logger.With(
+		zap.String("source", "jobGroupHandler"),
+		zap.String("operation", "stopJobGroup"),
+		zap.String("appId", c.Param("aid")),
+		zap.String("jobGroupId", c.Param("gid")),
+	)
+	jobGroup, skip, err := a.getJobGroup(c, l)
+	if err != nil || skip {
+		return err
+	}
+	jobs, err := a.updateJobGroupStatus(c, jobGroup, "stopped", "coalesce(status, '') <> 'stopped'")
+	if err != nil {
+		log.E(l, "Failed to stop job group.", func(cm log.CM) {
+			cm.Write(zap.Error(err))
+		})
+		return c.JSON(http.StatusInternalServerError, &Error{Reason: err.Error()})
+	}
+	if len(jobs) == 0 {
+		return c.JSON(http.StatusForbidden, &Error{Reason: "no job of the group can be stopped"})
+	}
+
+	for i := range jobs {
+		job := &jobs[i]
+		if job.StartsAt <= time.Now().UnixNano() {
+			continue
+		}
+		// the job has not started yet, so its scheduled messages are removed from the workers
+		var removed int
+		err = WithSegment("remove-scheduled-job", c, func() error {
+			removed, err = a.Worker.RemoveScheduledJob(job)
+			return err
+		})
+		if err != nil {
+			log.E(l, "Failed to remove scheduled job.", func(cm log.CM) {
+				cm.Write(zap.String("jobId", job.ID.String()), zap.Error(err))
+			})
+		}
+		log.I(l, "Removed scheduled job.", func(cm log.CM) {
+			cm.Write(zap.String("jobId", job.ID.String()), zap.Int("removed", removed))
+		})
+	}
+	log.I(l, "Stopped job group successfully.", func(cm log.CM) {
+		cm.Write(zap.Int("jobs", len(jobs)))
+	})
+	return a.GetJobGroupHandler(c)
+}
+
+// ResumeJobGroupHandler is the method called when a put to /apps/:aid/jobgroups/:gid/resume is called
+func (a *Application) ResumeJobGroupHandler(c echo.Context) error {
+	l := a.Logger.With(
+		zap.String("source", "jobGroupHandler"),
+		zap.String("operation", "resumeJobGroup"),
+		zap.String("appId", c.Param("aid")),
+		zap.String("jobGroupId", c.Param("gid")),
+	)
+	jobGroup, skip, err := a.getJobGroup(c, l)
+	if err != nil || skip {
+		return err
+	}
+	jobs, err := a.updateJobGroupStatus(c, jobGroup, nil, "status IN ('paused', 'circuitbreak')")
+	if err != nil {
+		log.E(l, "Failed to resume job group.", func(cm log.CM) {
+			cm.Write(zap.Error(err))
+		})
+		return c.JSON(http.StatusInternalServerError, &Error{Reason: err.Error()})
+	}
+	if len(jobs) == 0 {
+		return c.JSON(http.StatusForbidden, &Error{Reason: "no job of the group can be resumed"})
+	}
+
+	for _, job := range jobs {
+		var wJobID string
+		err = WithSegment("resume-job", c, func() error {
+			wJobID, err = a.Worker.CreateResumeJob(&[]string{job.ID.String()})
+			return err
+		})
+		if err != nil {
+			log.E(l, "Failed to send job to resume_job_worker.", func(cm log.CM) {
+				cm.Write(zap.String("jobId", job.ID.String()), zap.Error(err))
+			})
+			return c.JSON(http.StatusInternalServerError, &Error{Reason: err.Error()})
+		}
+		log.I(l, "Job successfully sent to resume_job_worker", func(cm log.CM) {
+			cm.Write(zap.String("jobId", job.ID.String()), zap.String("workerJobId", wJobID))
+		})
+	}
+	return a.GetJobGroupHandler(c)
+}
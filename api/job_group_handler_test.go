@@ -0,0 +1,229 @@
+/*
+ * Copyright (c) 2026 TFG Co <backend@tfgco.com>
+ * Author: TFG Co <backend@tfgco.com>
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy of
+ * this software and associated documentation files (the "Software"), to deal in
+ * the Software without restriction, including without limitation the rights to
+ * use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+ * the Software, and to permit persons to whom the Software is furnished to do so,
+ * subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+ * FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+ * COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+ * IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+ * CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+ */
+
+package api_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	uuid "github.com/satori/go.uuid"
+	"github.com/topfreegames/marathon/model"
+	. "github.com/topfreegames/marathon/testing"
+	"github.com/uber-go/zap"
+)
+
+var _ = Describe("Job Group Handler", func() {
+	logger := zap.New(
+		zap.NewJSONEncoder(zap.NoTime()), // drop timestamps in tests
+		zap.FatalLevel,
+	)
+	app := GetDefaultTestApp(logger)
+	var existingApp *model.App
+	var existingTemplate *model.Template
+	var jobGroup *model.JobGroup
+	var jobs []*model.Job
+	var baseRoute string
+
+	BeforeEach(func() {
+		app.DB.Exec("DELETE FROM apps;")
+		app.DB.Exec("DELETE FROM users;")
+		CreateTestUser(app.DB, map[string]interface{}{"email": "test@test.com", "isAdmin": true})
+		app.Worker.RedisClient.FlushAll()
+
+		existingApp = CreateTestApp(app.DB)
+		existingTemplate = CreateTestTemplate(app.DB, existingApp.ID, map[string]interface{}{
+			"locale": "en",
+		})
+
+		jobGroup = &model.JobGroup{ID: uuid.NewV4(), AppID: existingApp.ID}
+		err := app.DB.Insert(jobGroup)
+		Expect(err).NotTo(HaveOccurred())
+		jobs = CreateTestJobs(app.DB, existingApp.ID, existingTemplate.Name, 2)
+		for _, job := range jobs {
+			job.JobGroupID = jobGroup.ID
+			_, err = app.DB.Model(job).Column("job_group_id").Update()
+			Expect(err).NotTo(HaveOccurred())
+		}
+		baseRoute = fmt.Sprintf("/apps/%s/jobgroups/%s", existingApp.ID, jobGroup.ID)
+	})
+
+	Describe("Get /apps/:aid/jobgroups/:gid", func() {
+		Describe("Sucesfully", func() {
+			It("should return 200 and the jobs of the group with the totals", func() {
+				_, err := app.DB.Model(jobs[0]).Set("total_tokens = 10, completed_tokens = 4, feedbacks = '{\"ack\": 3, \"BadDeviceToken\": 1}'").Update()
+				Expect(err).NotTo(HaveOccurred())
+				_, err = app.DB.Model(jobs[1]).Set("total_tokens = 5, completed_tokens = 5, feedbacks = '{\"ack\": 5}'").Update()
+				Expect(err).NotTo(HaveOccurred())
+
+				status, body := Get(app, baseRoute, "test@test.com")
+				Expect(status).To(Equal(http.StatusOK))
+
+				var response map[string]interface{}
+				err = json.Unmarshal([]byte(body), &response)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(response["id"]).To(Equal(jobGroup.ID.String()))
+				Expect(response["jobs"]).To(HaveLen(2))
+				Expect(response["totalTokens"]).To(BeEquivalentTo(15))
+				Expect(response["completedTokens"]).To(BeEquivalentTo(9))
+				Expect(response["feedbacks"]).To(Equal(map[string]interface{}{"ack": 8.0, "BadDeviceToken": 1.0}))
+			})
+		})
+
+		Describe("Unsucesfully", func() {
+			It("should return 404 if the job group is of another app", func() {
+				otherApp := CreateTestApp(app.DB, map[string]interface{}{"name": "otherapp"})
+				status, _ := Get(app, fmt.Sprintf("/apps/%s/jobgroups/%s", otherApp.ID, jobGroup.ID), "test@test.com")
+				Expect(status).To(Equal(http.StatusNotFound))
+			})
+		})
+	})
+
+	Describe("Put /apps/:aid/jobgroups/:gid/pause", func() {
+		It("should return 200 and pause every job of the group", func() {
+			status, body := Put(app, fmt.Sprintf("%s/pause", baseRoute), "", "test@test.com")
+			Expect(status).To(Equal(http.StatusOK))
+
+			var response map[string]interface{}
+			err := json.Unmarshal([]byte(body), &response)
+			Expect(err).NotTo(HaveOccurred())
+			for _, job := range response["jobs"].([]interface{}) {
+				Expect(job.(map[string]interface{})["status"]).To(Equal("paused"))
+			}
+		})
+
+		It("should not pause the completed jobs", func() {
+			_, err := app.DB.Model(jobs[0]).Set("completed_at = ?", time.Now().UnixNano()).Update()
+			Expect(err).NotTo(HaveOccurred())
+
+			status, _ := Put(app, fmt.Sprintf("%s/pause", baseRoute), "", "test@test.com")
+			Expect(status).To(Equal(http.StatusOK))
+
+			dbJob := &model.Job{ID: jobs[0].ID}
+			err = app.DB.Select(dbJob)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(dbJob.Status).To(BeEmpty())
+		})
+
+		It("should return 403 if no job can be paused", func() {
+			status, _ := Put(app, fmt.Sprintf("%s/stop", baseRoute), "", "test@test.com")
+			Expect(status).To(Equal(http.StatusOK))
+
+			status, _ = Put(app, fmt.Sprintf("%s/pause", baseRoute), "", "test@test.com")
+			Expect(status).To(Equal(http.StatusForbidden))
+		})
+	})
+
+	Describe("Put /apps/:aid/jobgroups/:gid/stop", func() {
+		It("should return 200 and stop every job of the group", func() {
+			_, err := app.DB.Model(jobs[0]).Set("status = 'paused'").Update()
+			Expect(err).NotTo(HaveOccurred())
+
+			status, body := Put(app, fmt.Sprintf("%s/stop", baseRoute), "", "test@test.com")
+			Expect(status).To(Equal(http.StatusOK))
+
+			var response map[string]interface{}
+			err = json.Unmarshal([]byte(body), &response)
+			Expect(err).NotTo(HaveOccurred())
+			for _, job := range response["jobs"].([]interface{}) {
+				Expect(job.(map[string]interface{})["status"]).To(Equal("stopped"))
+			}
+		})
+	})
+
+	Describe("Put /apps/:aid/jobgroups/:gid/resume", func() {
+		It("should return 200 and resume the paused jobs of the group", func() {
+			status, _ := Put(app, fmt.Sprintf("%s/pause", baseRoute), "", "test@test.com")
+			Expect(status).To(Equal(http.StatusOK))
+
+			status, body := Put(app, fmt.Sprintf("%s/resume", baseRoute), "", "test@test.com")
+			Expect(status).To(Equal(http.StatusOK))
+
+			var response map[string]interface{}
+			err := json.Unmarshal([]byte(body), &response)
+			Expect(err).NotTo(HaveOccurred())
+			for _, job := range response["jobs"].([]interface{}) {
+				Expect(job.(map[string]interface{})["status"]).To(BeEmpty())
+			}
+
+			res, err := app.Worker.RedisClient.LLen("queue:resume_job_worker").Result()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(res).To(BeEquivalentTo(2))
+		})
+
+		It("should return 403 if no job is paused", func() {
+			status, _ := Put(app, fmt.Sprintf("%s/resume", baseRoute), "", "test@test.com")
+			Expect(status).To(Equal(http.StatusForbidden))
+		})
+	})
+
+	Describe("Get /apps/:aid/jobgroups/:gid/jobs", func() {
+		Describe("Sucesfully", func() {
+			It("should return 200 and the jobs of each timezone of a localized job", func() {
+				startsAt := time.Now().Add(time.Hour)
+				payload := GetJobPayload(map[string]interface{}{
+					"filters":   map[string]interface{}{"locale": "en"},
+					"startsAt":  startsAt.UnixNano(),
+					"expiresAt": startsAt.Add(24 * time.Hour).UnixNano(),
+				})
+				payload["localized"] = true
+				pl, _ := json.Marshal(payload)
+				status, body := Post(app, fmt.Sprintf("/apps/%s/jobs?template=%s", existingApp.ID, existingTemplate.Name), string(pl), "test@test.com")
+				Expect(status).To(Equal(http.StatusCreated))
+
+				var job map[string]interface{}
+				err := json.Unmarshal([]byte(body), &job)
+				Expect(err).NotTo(HaveOccurred())
+
+				status, body = Get(app, fmt.Sprintf("/apps/%s/jobgroups/%s/jobs", existingApp.ID, job["jobGroupId"]), "test@test.com")
+				Expect(status).To(Equal(http.StatusOK))
+
+				// the push db users with locale en are in -0300 and -0500
+				var jobs []map[string]interface{}
+				err = json.Unmarshal([]byte(body), &jobs)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(jobs).To(HaveLen(2))
+				Expect(jobs[0]["filters"]).To(Equal(map[string]interface{}{"locale": "en", "tz": "-0300"}))
+				Expect(jobs[0]["startsAt"]).To(BeEquivalentTo(startsAt.Add(3 * time.Hour).UnixNano()))
+				Expect(jobs[1]["filters"]).To(Equal(map[string]interface{}{"locale": "en", "tz": "-0500"}))
+				Expect(jobs[1]["startsAt"]).To(BeEquivalentTo(startsAt.Add(5 * time.Hour).UnixNano()))
+				Expect(jobs[1]["id"]).To(Equal(job["id"]))
+			})
+		})
+
+		Describe("Unsucesfully", func() {
+			It("should return 404 if the job group does not exist", func() {
+				status, _ := Get(app, fmt.Sprintf("/apps/%s/jobgroups/%s/jobs", existingApp.ID, uuid.NewV4()), "test@test.com")
+				Expect(status).To(Equal(http.StatusNotFound))
+			})
+
+			It("should return 422 if invalid job group id", func() {
+				status, _ := Get(app, fmt.Sprintf("/apps/%s/jobgroups/not-uuid/jobs", existingApp.ID), "test@test.com")
+				Expect(status).To(Equal(http.StatusUnprocessableEntity))
+			})
+		})
+	})
+})
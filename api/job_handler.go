@@ -28,6 +28,7 @@ import (
 	"strings"
 	"time"
 
+	"gopkg.in/pg.v5"
 	"gopkg.in/pg.v5/types"
 
 	"github.com/labstack/echo/v4"
@@ -131,11 +132,33 @@ func (a *Application) PostJobHandler(c echo.Context) error {
 		return err
 	}
 
+	skip, err = a.checkCSVColumns(job, c)
+	if err != nil || skip {
+		return err
+	}
+
+	err = WithSegment("db-select", c, func() error {
+		return job.PinTemplateVersions(a.DB)
+	})
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, &Error{Reason: err.Error(), Value: job})
+	}
+
+	skip, err = a.checkJobTemplates(job, c)
+	if err != nil || skip {
+		return err
+	}
+
 	if job.StartsAt == 0 && job.Localized {
 		localeErr := "Job can not be localized and don't have an start time"
 		return c.JSON(http.StatusUnprocessableEntity, &Error{Reason: localeErr, Value: job})
 	}
 
+	if app.RequiresApproval {
+		// the workers are only created when another user approves the job
+		job.Status = pendingApprovalJobStatus
+	}
+
 	err = WithSegment("create-job", c, func() error {
 		scheduleJob := job.StartsAt
 
@@ -162,26 +185,24 @@ func (a *Application) PostJobHandler(c echo.Context) error {
 			return nil
 		}
 
-		// create a job for each tz
-		for i := -12; i <= 14; i++ {
-			tzs := []string{
-				fmt.Sprintf("%+.4d", i*100-55), // 100 - 55 = 45
-				fmt.Sprintf("%+.4d", i*100),
-				fmt.Sprintf("%+.4d", i*100+15),
-				fmt.Sprintf("%+.4d", i*100+30),
-			}
-			sendTime := time.Unix(0, scheduleJob).Add(time.Duration(i) * time.Hour)
-			if sendTime.Before(time.Now()) {
-				if job.PastTimeStrategy == "skip" {
-					continue
-				}
-				sendTime = sendTime.Add(time.Duration(24) * time.Hour)
-			}
-
-			job.StartsAt = sendTime.UnixNano()
-			job.Filters["tz"] = strings.Join(tzs, ",")
+		// create a job for each group of timezones in which the job is sent at the same time
+		var timezones []string
+		err = WithSegment("timezones-select", c, func() error {
+			ttl := a.Worker.Config.GetDuration("workers.timezones.cacheTTL")
+			timezones, err = worker.GetPushDBTimezones(a.Worker.RedisClient, a.PushDB, job, ttl)
+			return err
+		})
+		if err != nil {
+			return err
+		}
+		filters := job.Filters
+		for _, group := range worker.GroupTimezones(timezones, scheduleJob, job.PastTimeStrategy, time.Now()) {
+			job.StartsAt = group.StartsAt
+			job.Filters = worker.WithColumnFilter(filters, "tz", group.Timezones)
 			job.ID = uuid.NewV4()
-			log.I(l, "Create a timezone job.")
+			log.I(l, "Create a timezone job.", func(cm log.CM) {
+				cm.Write(zap.Object("timezones", group.Timezones))
+			})
 
 			err = a.createJob(job, c)
 			if err != nil {
@@ -222,69 +243,92 @@ func (a *Application) PostJobHandler(c echo.Context) error {
 }
 
 func (a *Application) checkFilters(job *model.Job, c echo.Context) (bool, error) {
-	if job.Filters["region"] != nil || job.Filters["NOTregion"] != nil || job.Filters["locale"] != nil || job.Filters["NOTlocale"] != nil {
-		var users []worker.User
-		query := fmt.Sprintf("SELECT locale, region FROM %s WHERE locale is not NULL AND region is not NULL LIMIT 1;", worker.GetPushDBTableName(job.App.Name, job.Service))
-		a.PushDB.Query(&users, query)
-		if len(users) != 1 {
-			return true, c.JSON(http.StatusInternalServerError, &Error{Reason: "Failed to check filters in Push DB"})
-		}
-		locale := users[0].Locale
-		region := users[0].Region
+	filter, err := worker.ParseFilters(job.Filters)
+	if err != nil {
+		return true, c.JSON(http.StatusUnprocessableEntity, &Error{Reason: err.Error(), Value: job})
+	}
+	if filter == nil {
+		return false, nil
+	}
 
-		localeSettings := map[string]bool{
-			"isUpperCase": strings.ToUpper(locale) == locale,
-			"isLowerCase": strings.ToLower(locale) == locale,
-		}
+	tableName := worker.GetPushDBTableName(job.App.Name, job.Service)
+	var columns map[string]string
+	err = WithSegment("db-select", c, func() error {
+		columns, err = worker.GetPushDBColumns(a.PushDB, tableName)
+		return err
+	})
+	if err != nil || len(columns) == 0 {
+		return true, c.JSON(http.StatusInternalServerError, &Error{Reason: "Failed to check filters in Push DB"})
+	}
+	err = filter.Validate(columns)
+	if err != nil {
+		return true, c.JSON(http.StatusUnprocessableEntity, &Error{Reason: err.Error(), Value: job})
+	}
 
-		regionSettings := map[string]bool{
-			"isUpperCase": strings.ToUpper(region) == region,
-			"isLowerCase": strings.ToLower(region) == region,
-		}
+	filterColumns := map[string]bool{}
+	for _, column := range filter.Columns() {
+		filterColumns[column] = true
+	}
+	if !filterColumns["region"] && !filterColumns["locale"] {
+		return false, nil
+	}
 
-		if job.Filters["locale"] != nil {
-			if localeSettings["isUpperCase"] && !localeSettings["isLowerCase"] {
-				job.Filters["locale"] = strings.ToUpper(job.Filters["locale"].(string))
-			} else if localeSettings["isLowerCase"] && !localeSettings["isUpperCase"] {
-				job.Filters["locale"] = strings.ToLower(job.Filters["locale"].(string))
-			} else {
-				return true, c.JSON(http.StatusInternalServerError, &Error{Reason: "Locale case check failed in Push DB"})
-			}
+	var users []worker.User
+	query := fmt.Sprintf("SELECT locale, region FROM %s WHERE locale is not NULL AND region is not NULL LIMIT 1;", tableName)
+	a.PushDB.Query(&users, query)
+	if len(users) != 1 {
+		return true, c.JSON(http.StatusInternalServerError, &Error{Reason: "Failed to check filters in Push DB"})
+	}
+
+	caseFuncs := map[string]func(string) string{}
+	if filterColumns["locale"] {
+		toCase, ok := getCaseFunc(users[0].Locale)
+		if !ok {
+			return true, c.JSON(http.StatusInternalServerError, &Error{Reason: "Locale case check failed in Push DB"})
 		}
+		caseFuncs["locale"] = toCase
+	}
 
-		if job.Filters["NOTlocale"] != nil {
-			if localeSettings["isUpperCase"] && !localeSettings["isLowerCase"] {
-				job.Filters["NOTlocale"] = strings.ToUpper(job.Filters["NOTlocale"].(string))
-			} else if localeSettings["isLowerCase"] && !localeSettings["isUpperCase"] {
-				job.Filters["NOTlocale"] = strings.ToLower(job.Filters["NOTlocale"].(string))
-			} else {
-				return true, c.JSON(http.StatusInternalServerError, &Error{Reason: "Locale case check failed in Push DB"})
-			}
+	if filterColumns["region"] {
+		toCase, ok := getCaseFunc(users[0].Region)
+		if !ok {
+			return true, c.JSON(http.StatusInternalServerError, &Error{Reason: "Region case check failed in Push DB"})
 		}
+		caseFuncs["region"] = toCase
+	}
 
-		if job.Filters["region"] != nil {
-			if regionSettings["isUpperCase"] && !regionSettings["isLowerCase"] {
-				job.Filters["region"] = strings.ToUpper(job.Filters["region"].(string))
-			} else if regionSettings["isLowerCase"] && !regionSettings["isUpperCase"] {
-				job.Filters["region"] = strings.ToLower(job.Filters["region"].(string))
-			} else {
-				return true, c.JSON(http.StatusInternalServerError, &Error{Reason: "Region case check failed in Push DB"})
-			}
+	if worker.IsFilterExpression(job.Filters) {
+		for column, toCase := range caseFuncs {
+			filter.MapValues(column, toCase)
 		}
+		job.Filters, err = filter.ToMap()
+		if err != nil {
+			return true, c.JSON(http.StatusInternalServerError, &Error{Reason: err.Error(), Value: job})
+		}
+		return false, nil
+	}
 
-		if job.Filters["NOTregion"] != nil {
-			if regionSettings["isUpperCase"] && !regionSettings["isLowerCase"] {
-				job.Filters["NOTregion"] = strings.ToUpper(job.Filters["NOTregion"].(string))
-			} else if regionSettings["isLowerCase"] && !regionSettings["isUpperCase"] {
-				job.Filters["NOTregion"] = strings.ToLower(job.Filters["NOTregion"].(string))
-			} else {
-				return true, c.JSON(http.StatusInternalServerError, &Error{Reason: "Region case check failed in Push DB"})
-			}
+	for key, val := range job.Filters {
+		if toCase, ok := caseFuncs[strings.TrimPrefix(key, "NOT")]; ok {
+			job.Filters[key] = toCase(val.(string))
 		}
 	}
 	return false, nil
 }
 
+// getCaseFunc returns the function that converts filter values to the case used in the push db
+func getCaseFunc(sample string) (func(string) string, bool) {
+	isUpperCase := strings.ToUpper(sample) == sample
+	isLowerCase := strings.ToLower(sample) == sample
+	if isUpperCase && !isLowerCase {
+		return strings.ToUpper, true
+	}
+	if isLowerCase && !isUpperCase {
+		return strings.ToLower, true
+	}
+	return nil, false
+}
+
 func (a *Application) checkTemplateName(templateName string, job *model.Job, c echo.Context) (bool, error) {
 	for _, tpl := range strings.Split(templateName, ",") {
 		template := &model.Template{}
@@ -309,6 +353,94 @@ func (a *Application) checkTemplateName(templateName string, job *model.Job, c e
 			return true, c.JSON(http.StatusInternalServerError, &Error{Reason: err.Error(), Value: job})
 		}
 	}
+	return a.checkTemplateUserColumns(templateName, job, c)
+}
+
+// checkJobTemplates validates the job templates for the job service, with the job context and
+// metadata. Jobs with a csv can fill the template variables with its columns, so they are
+// not checked
+func (a *Application) checkJobTemplates(job *model.Job, c echo.Context) (bool, error) {
+	var templatesByNameAndLocale map[string]map[string]model.Template
+	err := WithSegment("db-select", c, func() error {
+		var err error
+		templatesByNameAndLocale, err = job.GetJobTemplatesByNameAndLocale(a.DB)
+		return err
+	})
+	if err != nil {
+		return true, c.JSON(http.StatusInternalServerError, &Error{Reason: err.Error(), Value: job})
+	}
+	for _, templatesByLocale := range templatesByNameAndLocale {
+		for _, template := range templatesByLocale {
+			template.PushOptions = job.GetPushOptions(&template)
+			reason := ""
+			if template.Service != "" && template.Service != job.Service {
+				reason = fmt.Sprintf("template %s (%s) is only sent to %s", template.Name, template.Locale, template.Service)
+			} else if err := worker.ValidateTemplate(template, []string{job.Service}, job.Context, job.Metadata); err != nil {
+				reason = fmt.Sprintf("template %s (%s): %s", template.Name, template.Locale, err.Error())
+			} else if missing := worker.MissingTemplateVariables(template, job.Context); len(missing) > 0 && job.CSVPath == "" {
+				reason = fmt.Sprintf("template %s (%s) variables are not filled by its defaults or the job context: %s", template.Name, template.Locale, strings.Join(missing, ", "))
+			}
+			if reason != "" {
+				return true, c.JSON(http.StatusUnprocessableEntity, &Error{Reason: reason, Value: job})
+			}
+		}
+	}
+	return false, nil
+}
+
+func (a *Application) checkTemplateUserColumns(templateName string, job *model.Job, c echo.Context) (bool, error) {
+	var templates []model.Template
+	err := WithSegment("db-select", c, func() error {
+		return a.DB.Model(&templates).Where("app_id = ? AND name IN (?)", job.AppID, pg.In(strings.Split(templateName, ","))).Select()
+	})
+	if err != nil {
+		return true, c.JSON(http.StatusInternalServerError, &Error{Reason: err.Error(), Value: job})
+	}
+	extraColumns := worker.TemplateUserColumns(templates...)
+	if len(extraColumns) == 0 {
+		return false, nil
+	}
+
+	var columns map[string]string
+	err = WithSegment("db-select", c, func() error {
+		columns, err = worker.GetPushDBColumns(a.PushDB, worker.GetPushDBTableName(job.App.Name, job.Service))
+		return err
+	})
+	if err != nil {
+		return true, c.JSON(http.StatusInternalServerError, &Error{Reason: err.Error(), Value: job})
+	}
+	for _, column := range extraColumns {
+		if _, ok := columns[column]; !ok {
+			reason := fmt.Sprintf("template uses user.%s but column %s does not exist in push db", column, column)
+			return true, c.JSON(http.StatusUnprocessableEntity, &Error{Reason: reason, Value: job})
+		}
+	}
+	return false, nil
+}
+
+// checkCSVColumns validates the header of the job csv, the same way the csv split worker
+// does before sending, so jobs with unusable columns are not created
+func (a *Application) checkCSVColumns(job *model.Job, c echo.Context) (bool, error) {
+	if job.CSVPath == "" {
+		return false, nil
+	}
+
+	var header []byte
+	err := WithSegment("s3-download", c, func() error {
+		var err error
+		_, header, err = worker.GetCSVHeader(a.S3Client, job.CSVPath)
+		return err
+	})
+	if err != nil {
+		return true, c.JSON(http.StatusInternalServerError, &Error{Reason: err.Error(), Value: job})
+	}
+	columns, err := worker.GetCSVColumns(header)
+	if err == nil {
+		err = worker.ValidateCSVColumns(columns, job.Context)
+	}
+	if err != nil {
+		return true, c.JSON(http.StatusUnprocessableEntity, &Error{Reason: err.Error(), Value: job})
+	}
 	return false, nil
 }
 
@@ -346,6 +478,10 @@ func (a *Application) createJob(job *model.Job, c echo.Context) error {
 		return err
 	}
 
+	if job.Status == pendingApprovalJobStatus {
+		return nil
+	}
+
 	err = a.createJobWorkers(job, c)
 	if err != nil {
 		l.Error("Failed to create job worker", zap.Error(err))
@@ -388,6 +524,7 @@ func (a *Application) GetJobHandler(c echo.Context) error {
 		})
 		return c.JSON(http.StatusInternalServerError, &Error{Reason: err.Error(), Value: job})
 	}
+	job.Throttle = job.GetThrottle(time.Now())
 	log.D(l, "Retrieved job successfully.", func(cm log.CM) {
 		cm.Write(zap.Object("job", job))
 	})
@@ -507,6 +644,23 @@ func (a *Application) StopJobHandler(c echo.Context) error {
 		cm.Write(zap.Object("job", job))
 	})
 
+	if job.StartsAt > time.Now().UnixNano() {
+		// the job has not started yet, so its scheduled messages are removed from the workers
+		var removed int
+		err = WithSegment("remove-scheduled-job", c, func() error {
+			removed, err = a.Worker.RemoveScheduledJob(job)
+			return err
+		})
+		if err != nil {
+			log.E(l, "Failed to remove scheduled job.", func(cm log.CM) {
+				cm.Write(zap.Error(err))
+			})
+		}
+		log.I(l, "Removed scheduled job.", func(cm log.CM) {
+			cm.Write(zap.Int("removed", removed))
+		})
+	}
+
 	if a.SendgridClient != nil {
 		log.D(l, "sending email with stopped job info")
 		app := &model.App{ID: aid}
@@ -523,6 +677,141 @@ func (a *Application) StopJobHandler(c echo.Context) error {
 	return c.JSON(http.StatusOK, job)
 }
 
+// RescheduleJobHandler is the method called when a put to apps/:id/jobs/:jid/reschedule is called
+func (a *Application) RescheduleJobHandler(c echo.Context) error {
+	l := a.Logger.With(
+		zap.String("source", "jobHandler"),
+		zap.String("operation", "rescheduleJob"),
+		zap.String("appId", c.Param("aid")),
+		zap.String("jobId", c.Param("jid")),
+	)
+	aid, err := uuid.FromString(c.Param("aid"))
+	if err != nil {
+		return c.JSON(http.StatusUnprocessableEntity, &Error{Reason: err.Error()})
+	}
+	jid, err := uuid.FromString(c.Param("jid"))
+	if err != nil {
+		return c.JSON(http.StatusUnprocessableEntity, &Error{Reason: err.Error()})
+	}
+	userEmail := c.Get("user-email").(string)
+	reschedule := &model.JobReschedule{}
+	err = WithSegment("decodeAndValidate", c, func() error {
+		return decodeAndValidate(c, reschedule)
+	})
+	if err != nil {
+		return c.JSON(http.StatusUnprocessableEntity, &Error{Reason: err.Error(), Value: reschedule})
+	}
+
+	job := &model.Job{}
+	err = WithSegment("db-select", c, func() error {
+		return a.DB.Model(&job).Column("job.*", "App").Where("job.id = ? AND job.app_id = ?", jid, aid).Select()
+	})
+	if err != nil {
+		if err.Error() == RecordNotFoundString {
+			return c.JSON(http.StatusNotFound, map[string]string{})
+		}
+		log.E(l, "Failed to retrieve job.", func(cm log.CM) {
+			cm.Write(zap.Error(err))
+		})
+		return c.JSON(http.StatusInternalServerError, &Error{Reason: err.Error(), Value: job})
+	}
+	if job.Status != "" || job.StartsAt <= time.Now().UnixNano() {
+		return c.JSON(http.StatusForbidden, &Error{Reason: "cannot reschedule a job that is not pending"})
+	}
+
+	if reschedule.TemplateName != "" {
+		skip, err := a.checkTemplateName(reschedule.TemplateName, job, c)
+		if err != nil || skip {
+			return err
+		}
+		rescheduled := &model.Job{TemplateName: reschedule.TemplateName, TemplateWeights: job.TemplateWeights}
+		if !rescheduled.ValidTemplateWeights() {
+			return c.JSON(http.StatusUnprocessableEntity, &Error{Reason: model.InvalidField("templateWeights").Error(), Value: reschedule})
+		}
+	}
+
+	rescheduled := *job
+	if reschedule.TemplateName != "" {
+		rescheduled.TemplateName = reschedule.TemplateName
+		err = WithSegment("db-select", c, func() error {
+			return rescheduled.PinTemplateVersions(a.DB)
+		})
+		if err != nil {
+			return c.JSON(http.StatusInternalServerError, &Error{Reason: err.Error(), Value: job})
+		}
+	}
+	if reschedule.Context != nil {
+		rescheduled.Context = reschedule.Context
+	}
+	if reschedule.Metadata != nil {
+		rescheduled.Metadata = reschedule.Metadata
+	}
+	skip, err := a.checkJobTemplates(&rescheduled, c)
+	if err != nil || skip {
+		return err
+	}
+
+	var removed int
+	err = WithSegment("remove-scheduled-job", c, func() error {
+		removed, err = a.Worker.RemoveScheduledJob(job)
+		return err
+	})
+	if err != nil {
+		log.E(l, "Failed to remove scheduled job.", func(cm log.CM) {
+			cm.Write(zap.Error(err))
+		})
+		return c.JSON(http.StatusInternalServerError, &Error{Reason: err.Error(), Value: job})
+	}
+	if removed == 0 {
+		return c.JSON(http.StatusConflict, &Error{Reason: "job already started", Value: job})
+	}
+
+	job.StartsAt = reschedule.StartsAt
+	if reschedule.ExpiresAt != 0 {
+		job.ExpiresAt = reschedule.ExpiresAt
+	}
+	if reschedule.TemplateName != "" {
+		job.TemplateName = reschedule.TemplateName
+		job.TemplateVersions = rescheduled.TemplateVersions
+	}
+	if reschedule.Context != nil {
+		job.Context = reschedule.Context
+	}
+	if reschedule.Metadata != nil {
+		job.Metadata = reschedule.Metadata
+	}
+	job.UpdatedAt = time.Now().UnixNano()
+
+	err = WithSegment("db-update", c, func() error {
+		_, err = a.DB.Model(&job).
+			Column("starts_at", "expires_at", "template_name", "template_versions", "context", "metadata", "updated_at").
+			Update()
+		return err
+	})
+	if err != nil {
+		log.E(l, "Failed to reschedule job.", func(cm log.CM) {
+			cm.Write(zap.Error(err))
+		})
+		return c.JSON(http.StatusInternalServerError, &Error{Reason: err.Error(), Value: job})
+	}
+
+	err = WithSegment("create-job-workers", c, func() error {
+		return a.createJobWorkers(job, c)
+	})
+	if err != nil {
+		log.E(l, "Failed to schedule rescheduled job.", func(cm log.CM) {
+			cm.Write(zap.Error(err))
+		})
+		return c.JSON(http.StatusInternalServerError, &Error{Reason: err.Error(), Value: job})
+	}
+	job.TagSuccess(a.DB, "reschedule", fmt.Sprintf("rescheduled by %s", userEmail))
+
+	log.D(l, "Rescheduled job successfully.", func(cm log.CM) {
+		cm.Write(zap.Object("job", job))
+	})
+	return c.JSON(http.StatusOK, job)
+}
+
 // ResumeJobHandler is the method called when a put to apps/:id/jobs/:jid/resume is called
 func (a *Application) ResumeJobHandler(c echo.Context) error {
 	l := a.Logger.With(
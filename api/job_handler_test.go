@@ -29,6 +29,7 @@ import (
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 	uuid "github.com/satori/go.uuid"
+	"github.com/topfreegames/marathon/messages"
 	"github.com/topfreegames/marathon/model"
 	. "github.com/topfreegames/marathon/testing"
 	"github.com/topfreegames/marathon/worker"
@@ -42,6 +43,8 @@ var _ = Describe("Job Handler", func() {
 	)
 	app := GetDefaultTestApp(logger)
 	faultyDb := GetFaultyTestDB(app)
+	fakeS3 := NewFakeS3(app.Config)
+	app.S3Client = fakeS3
 	var existingApp *model.App
 	var existingTemplate *model.Template
 	var anotherTemplate *model.Template
@@ -68,6 +71,10 @@ var _ = Describe("Job Handler", func() {
 		})
 		baseRoute = fmt.Sprintf("/apps/%s/jobs?template=%s", existingApp.ID, existingTemplate.Name)
 		baseRouteWithoutTemplate = fmt.Sprintf("/apps/%s/jobs", existingApp.ID)
+
+		csv := []byte("userIds\n9e558649-9c23-469d-a11c-59b05813e3d5\n")
+		fakeS3.PutObject("s3.aws.com/my-link", &csv)
+		fakeS3.PutObject("bucket/somecsv", &csv)
 	})
 
 	Describe("Get /apps/:id/jobs?template=:templateName", func() {
@@ -181,6 +188,32 @@ var _ = Describe("Job Handler", func() {
 
 	Describe("Post /apps/:id/jobs?template=:templateName", func() {
 		Describe("Sucesfully", func() {
+			It("should return 201 and the created job with push options", func() {
+				payload := GetJobPayload()
+				payload["pushOptions"] = map[string]interface{}{
+					"mediaUrl":       "https://cdn.example.com/reward.png",
+					"priority":       "high",
+					"analyticsLabel": "reward",
+				}
+				pl, _ := json.Marshal(payload)
+				status, body := Post(app, baseRoute, string(pl), "success@test.com")
+				Expect(status).To(Equal(http.StatusCreated))
+
+				var job map[string]interface{}
+				err := json.Unmarshal([]byte(body), &job)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(job["pushOptions"]).To(Equal(payload["pushOptions"]))
+
+				dbJob := &model.Job{ID: uuid.FromStringOrNil(job["id"].(string))}
+				err = app.DB.Select(dbJob)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(dbJob.PushOptions).To(Equal(&messages.PushOptions{
+					MediaURL:       "https://cdn.example.com/reward.png",
+					Priority:       "high",
+					AnalyticsLabel: "reward",
+				}))
+			})
+
 			It("should return 201 and the created job with filters", func() {
 				payload := GetJobPayload()
 				delete(payload, "csvPath")
@@ -203,6 +236,7 @@ var _ = Describe("Job Handler", func() {
 				Expect(job["controlGroup"]).To(BeEquivalentTo(0.0))
 				Expect(job["controlGroupCsvPath"]).To(Equal(""))
 				Expect(job["service"]).To(Equal(payload["service"]))
+				Expect(job["priority"]).To(Equal("normal"))
 				Expect(job["createdBy"]).To(Equal("success@test.com"))
 				Expect(job["createdAt"]).ToNot(BeNil())
 				Expect(job["createdAt"]).ToNot(Equal(0))
@@ -300,6 +334,44 @@ var _ = Describe("Job Handler", func() {
 				}
 			})
 
+			It("should return 201 and the created job with a filter expression converting values to the correct case", func() {
+				payload := GetJobPayload()
+				payload["service"] = "gcm"
+				payload["filters"] = map[string]interface{}{
+					"and": []interface{}{
+						map[string]interface{}{"column": "region", "op": "in", "value": []interface{}{"US", "CA"}},
+						map[string]interface{}{"not": map[string]interface{}{"column": "locale", "op": "eq", "value": "en"}},
+						map[string]interface{}{"column": "created_at", "op": "after", "value": "2016-01-01T00:00:00Z"},
+					},
+				}
+				delete(payload, "csvPath")
+				pl, _ := json.Marshal(payload)
+				status, body := Post(app, baseRoute, string(pl), "success@test.com")
+				Expect(status).To(Equal(http.StatusCreated))
+
+				var job map[string]interface{}
+				err := json.Unmarshal([]byte(body), &job)
+				Expect(err).NotTo(HaveOccurred())
+
+				expectedFilters := map[string]interface{}{
+					"and": []interface{}{
+						map[string]interface{}{"column": "region", "op": "in", "value": []interface{}{"us", "ca"}},
+						map[string]interface{}{"not": map[string]interface{}{"column": "locale", "op": "eq", "value": "EN"}},
+						map[string]interface{}{"column": "created_at", "op": "after", "value": "2016-01-01T00:00:00Z"},
+					},
+				}
+				Expect(job["filters"]).To(Equal(expectedFilters))
+
+				id, err := uuid.FromString(job["id"].(string))
+				Expect(err).NotTo(HaveOccurred())
+				dbJob := &model.Job{
+					ID: id,
+				}
+				err = app.DB.Select(&dbJob)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(dbJob.Filters).To(Equal(expectedFilters))
+			})
+
 			It("should return 201 and the created job with localized set to false by default", func() {
 				payload := GetJobPayload()
 				delete(payload, "csvPath")
@@ -685,9 +757,10 @@ var _ = Describe("Job Handler", func() {
 				err := json.Unmarshal([]byte(body), &job)
 				Expect(err).NotTo(HaveOccurred())
 
+				// the push db users are in -0300, -0500, -0800 and an invalid tz sent in UTC
 				res, err := w.RedisClient.ZRange("schedule", 0, -1).Result()
 				Expect(err).NotTo(HaveOccurred())
-				Expect(len(res)).To(BeEquivalentTo(27))
+				Expect(len(res)).To(BeEquivalentTo(4))
 				res1, err := w.RedisClient.LLen("queue:csv_split_worker").Result()
 				Expect(err).NotTo(HaveOccurred())
 				Expect(res1).To(BeEquivalentTo(0))
@@ -695,7 +768,7 @@ var _ = Describe("Job Handler", func() {
 
 			It("should start the job if payload with startsAt, localized=true and past_time_strategy=skip", func() {
 				payload := GetJobPayload()
-				payload["startsAt"] = time.Now().Add(3 * time.Second).UnixNano()
+				payload["startsAt"] = time.Now().Add(-4 * time.Hour).UnixNano()
 				payload["csvPath"] = "bucket/somecsv"
 				payload["localized"] = true
 				payload["PastTimeStrategy"] = "skip"
@@ -710,7 +783,8 @@ var _ = Describe("Job Handler", func() {
 
 				res, err := w.RedisClient.ZRange("schedule", 0, -1).Result()
 				Expect(err).NotTo(HaveOccurred())
-				Expect(len(res)).To(BeEquivalentTo(15))
+				// only the -0500 and -0800 users have not passed the start time
+				Expect(len(res)).To(BeEquivalentTo(2))
 				res1, err := w.RedisClient.LLen("queue:csv_split_worker").Result()
 				Expect(err).NotTo(HaveOccurred())
 				Expect(res1).To(BeEquivalentTo(0))
@@ -732,13 +806,13 @@ var _ = Describe("Job Handler", func() {
 
 				res, err := w.RedisClient.ZRange("schedule", 0, -1).Result()
 				Expect(err).NotTo(HaveOccurred())
-				Expect(len(res)).To(BeEquivalentTo(27))
+				Expect(len(res)).To(BeEquivalentTo(4))
 				var result map[string]interface{}
-				err = json.Unmarshal([]byte(res[26]), &result)
+				err = json.Unmarshal([]byte(res[3]), &result)
 				Expect(err).NotTo(HaveOccurred())
 				Expect(result["queue"]).To(Equal("csv_split_worker"))
 				Expect(result["args"].(string)).To(Equal(job["id"]))
-				Expect(result["at"].(float64)).To(Equal(float64(payload["startsAt"].(int64))/1000000000.0 + 14*60*60.0))
+				Expect(result["at"].(float64)).To(BeNumerically("~", float64(payload["startsAt"].(int64))/1000000000.0+8*60*60.0, 0.001))
 
 				res1, err := w.RedisClient.LLen("queue:csv_split_worker").Result()
 				Expect(err).NotTo(HaveOccurred())
@@ -842,6 +916,93 @@ var _ = Describe("Job Handler", func() {
 				Expect(response["reason"]).To(Equal("Cannot create job if there is no template for locale 'en'."))
 			})
 
+			It("should return 422 if template uses a user column that does not exist in push db", func() {
+				badTemplate := CreateTestTemplate(app.DB, existingApp.ID, map[string]interface{}{
+					"locale": "en",
+					"body":   map[string]interface{}{"alert": "{{user.level}} levels"},
+				})
+				payload := GetJobPayload()
+				pl, _ := json.Marshal(payload)
+				status, body := Post(app, fmt.Sprintf("/apps/%s/jobs?template=%s", existingApp.ID, badTemplate.Name), string(pl), "test@test.com")
+				Expect(status).To(Equal(http.StatusUnprocessableEntity))
+
+				var response map[string]interface{}
+				err := json.Unmarshal([]byte(body), &response)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(response["reason"]).To(Equal("template uses user.level but column level does not exist in push db"))
+			})
+
+			It("should return 422 if template variables are not filled by its defaults or the job context", func() {
+				badTemplate := CreateTestTemplate(app.DB, existingApp.ID, map[string]interface{}{
+					"locale": "en",
+					"body":   map[string]interface{}{"alert": "{{reward}} for {{nick|you}}"},
+				})
+				payload := GetJobPayload()
+				pl, _ := json.Marshal(payload)
+				status, body := Post(app, fmt.Sprintf("/apps/%s/jobs?template=%s", existingApp.ID, badTemplate.Name), string(pl), "test@test.com")
+				Expect(status).To(Equal(http.StatusUnprocessableEntity))
+
+				var response map[string]interface{}
+				err := json.Unmarshal([]byte(body), &response)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(response["reason"]).To(Equal(fmt.Sprintf("template %s (en) variables are not filled by its defaults or the job context: reward", badTemplate.Name)))
+
+				payload["context"] = map[string]interface{}{"reward": "gold"}
+				pl, _ = json.Marshal(payload)
+				status, _ = Post(app, fmt.Sprintf("/apps/%s/jobs?template=%s", existingApp.ID, badTemplate.Name), string(pl), "test@test.com")
+				Expect(status).To(Equal(http.StatusCreated))
+			})
+
+			It("should return 422 if invalid push options", func() {
+				payload := GetJobPayload()
+				payload["pushOptions"] = map[string]interface{}{"interruptionLevel": "urgent"}
+				pl, _ := json.Marshal(payload)
+				status, body := Post(app, fmt.Sprintf("/apps/%s/jobs?template=%s", existingApp.ID, existingTemplate.Name), string(pl), "test@test.com")
+				Expect(status).To(Equal(http.StatusUnprocessableEntity))
+
+				var response map[string]interface{}
+				err := json.Unmarshal([]byte(body), &response)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(response["reason"]).To(Equal("invalid pushOptions.interruptionLevel"))
+			})
+
+			It("should return 422 if template is only sent to another service", func() {
+				gcmTemplate := CreateTestTemplate(app.DB, existingApp.ID, map[string]interface{}{
+					"locale":  "en",
+					"service": "gcm",
+				})
+				payload := GetJobPayload(map[string]interface{}{"service": "apns"})
+				pl, _ := json.Marshal(payload)
+				status, body := Post(app, fmt.Sprintf("/apps/%s/jobs?template=%s", existingApp.ID, gcmTemplate.Name), string(pl), "test@test.com")
+				Expect(status).To(Equal(http.StatusUnprocessableEntity))
+
+				var response map[string]interface{}
+				err := json.Unmarshal([]byte(body), &response)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(response["reason"]).To(Equal(fmt.Sprintf("template %s (en) is only sent to gcm", gcmTemplate.Name)))
+			})
+
+			It("should return 422 if an apns job template without service is not a valid aps dictionary", func() {
+				anyTemplate := CreateTestTemplate(app.DB, existingApp.ID, map[string]interface{}{
+					"locale": "en",
+					"body":   map[string]interface{}{"alert": "hello", "reward": "gold"},
+				})
+				payload := GetJobPayload(map[string]interface{}{"service": "apns"})
+				pl, _ := json.Marshal(payload)
+				status, body := Post(app, fmt.Sprintf("/apps/%s/jobs?template=%s", existingApp.ID, anyTemplate.Name), string(pl), "test@test.com")
+				Expect(status).To(Equal(http.StatusUnprocessableEntity))
+
+				var response map[string]interface{}
+				err := json.Unmarshal([]byte(body), &response)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(response["reason"]).To(Equal(fmt.Sprintf("template %s (en): invalid template: unknown aps key reward", anyTemplate.Name)))
+
+				payload["service"] = "gcm"
+				pl, _ = json.Marshal(payload)
+				status, _ = Post(app, fmt.Sprintf("/apps/%s/jobs?template=%s", existingApp.ID, anyTemplate.Name), string(pl), "test@test.com")
+				Expect(status).To(Equal(http.StatusCreated))
+			})
+
 			It("should return 422 if template is not specified", func() {
 				payload := GetJobPayload()
 				pl, _ := json.Marshal(payload)
@@ -867,6 +1028,48 @@ var _ = Describe("Job Handler", func() {
 				Expect(response["reason"]).To(Equal("invalid filters or csvPath must exist, not both"))
 			})
 
+			It("should return 422 if the csv has an invalid column", func() {
+				csv := []byte("userIds,friend name\n9e558649-9c23-469d-a11c-59b05813e3d5,john\n")
+				fakeS3.PutObject("bucket/invalidcsv", &csv)
+				payload := GetJobPayload()
+				delete(payload, "filters")
+				payload["csvPath"] = "bucket/invalidcsv"
+				pl, _ := json.Marshal(payload)
+				status, body := Post(app, baseRoute, string(pl), "test@test.com")
+				Expect(status).To(Equal(http.StatusUnprocessableEntity))
+
+				var response map[string]interface{}
+				err := json.Unmarshal([]byte(body), &response)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(response["reason"]).To(Equal(`invalid csv column: "friend name"`))
+			})
+
+			It("should return 422 if a csv column is also a context key", func() {
+				csv := []byte("userIds,coins\n9e558649-9c23-469d-a11c-59b05813e3d5,10\n")
+				fakeS3.PutObject("bucket/contextcsv", &csv)
+				payload := GetJobPayload()
+				delete(payload, "filters")
+				payload["csvPath"] = "bucket/contextcsv"
+				payload["context"] = map[string]interface{}{"coins": 20}
+				pl, _ := json.Marshal(payload)
+				status, body := Post(app, baseRoute, string(pl), "test@test.com")
+				Expect(status).To(Equal(http.StatusUnprocessableEntity))
+
+				var response map[string]interface{}
+				err := json.Unmarshal([]byte(body), &response)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(response["reason"]).To(Equal("invalid csv column: coins is also a context key"))
+			})
+
+			It("should return 500 if the csv can not be read", func() {
+				payload := GetJobPayload()
+				delete(payload, "filters")
+				payload["csvPath"] = "bucket/notfound"
+				pl, _ := json.Marshal(payload)
+				status, _ := Post(app, baseRoute, string(pl), "test@test.com")
+				Expect(status).To(Equal(http.StatusInternalServerError))
+			})
+
 			It("should return 422 if controlGroup is < 0", func() {
 				payload := GetJobPayload()
 				payload["controlGroup"] = -0.10
@@ -893,6 +1096,46 @@ var _ = Describe("Job Handler", func() {
 				Expect(response["reason"]).To(Equal("invalid controlGroup"))
 			})
 
+			It("should return 422 if templateWeights does not match the templates", func() {
+				payload := GetJobPayload()
+				payload["templateWeights"] = map[string]int{"other-template": 1}
+				pl, _ := json.Marshal(payload)
+				status, body := Post(app, baseRoute, string(pl), "test@test.com")
+				Expect(status).To(Equal(http.StatusUnprocessableEntity))
+
+				var response map[string]interface{}
+				err := json.Unmarshal([]byte(body), &response)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(response["reason"]).To(Equal("invalid templateWeights"))
+			})
+
+			It("should return 422 if both maxPushesPerSecond and spreadOver are set", func() {
+				payload := GetJobPayload()
+				payload["maxPushesPerSecond"] = 100
+				payload["spreadOver"] = int64(time.Hour)
+				pl, _ := json.Marshal(payload)
+				status, body := Post(app, baseRoute, string(pl), "test@test.com")
+				Expect(status).To(Equal(http.StatusUnprocessableEntity))
+
+				var response map[string]interface{}
+				err := json.Unmarshal([]byte(body), &response)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(response["reason"]).To(Equal("invalid spreadOver"))
+			})
+
+			It("should return 422 if invalid priority", func() {
+				payload := GetJobPayload()
+				payload["priority"] = "urgent"
+				pl, _ := json.Marshal(payload)
+				status, body := Post(app, baseRoute, string(pl), "test@test.com")
+				Expect(status).To(Equal(http.StatusUnprocessableEntity))
+
+				var response map[string]interface{}
+				err := json.Unmarshal([]byte(body), &response)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(response["reason"]).To(Equal("invalid priority"))
+			})
+
 			It("should return 422 if missing service", func() {
 				payload := GetJobPayload()
 				delete(payload, "service")
@@ -945,6 +1188,58 @@ var _ = Describe("Job Handler", func() {
 				Expect(response["reason"]).To(ContainSubstring("cannot unmarshal string into Go struct"))
 			})
 
+			It("should return 422 if filters use a column that does not exist in push db", func() {
+				payload := GetJobPayload()
+				payload["filters"] = map[string]interface{}{
+					"column": "age",
+					"op":     "gt",
+					"value":  18,
+				}
+				delete(payload, "csvPath")
+				pl, _ := json.Marshal(payload)
+				status, body := Post(app, baseRoute, string(pl), "test@test.com")
+				Expect(status).To(Equal(http.StatusUnprocessableEntity))
+
+				var response map[string]interface{}
+				err := json.Unmarshal([]byte(body), &response)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(response["reason"]).To(Equal("invalid filters: column age does not exist"))
+			})
+
+			It("should return 422 if legacy filters use a column that does not exist in push db", func() {
+				payload := GetJobPayload()
+				payload["filters"] = map[string]interface{}{
+					"NOTage\"='1' OR \"1": "18",
+				}
+				delete(payload, "csvPath")
+				pl, _ := json.Marshal(payload)
+				status, body := Post(app, baseRoute, string(pl), "test@test.com")
+				Expect(status).To(Equal(http.StatusUnprocessableEntity))
+
+				var response map[string]interface{}
+				err := json.Unmarshal([]byte(body), &response)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(response["reason"]).To(ContainSubstring("invalid filters: invalid column"))
+			})
+
+			It("should return 422 if filter expression is invalid", func() {
+				payload := GetJobPayload()
+				payload["filters"] = map[string]interface{}{
+					"column": "locale",
+					"op":     "in",
+					"value":  "en",
+				}
+				delete(payload, "csvPath")
+				pl, _ := json.Marshal(payload)
+				status, body := Post(app, baseRoute, string(pl), "test@test.com")
+				Expect(status).To(Equal(http.StatusUnprocessableEntity))
+
+				var response map[string]interface{}
+				err := json.Unmarshal([]byte(body), &response)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(response["reason"]).To(Equal("invalid filters: locale in needs a non empty list of values"))
+			})
+
 			It("should return 422 if invalid expiresAt", func() {
 				payload := GetJobPayload()
 				payload["expiresAt"] = "not-json"
@@ -1040,6 +1335,27 @@ var _ = Describe("Job Handler", func() {
 				for key := range plMetadata {
 					Expect(tempMetadata[key]).To(Equal(plMetadata[key]))
 				}
+				Expect(job).NotTo(HaveKey("throttle"))
+			})
+
+			It("should return 200 and the progress of a throttled job", func() {
+				existingJob := CreateTestJob(app.DB, existingApp.ID, existingTemplate.Name, map[string]interface{}{
+					"startsAt": time.Now().Add(-10 * time.Second).UnixNano(),
+				})
+				_, err := app.DB.Model(existingJob).Set("max_pushes_per_second = 10, total_tokens = 1000, completed_tokens = 50").Update()
+				Expect(err).NotTo(HaveOccurred())
+
+				status, body := Get(app, fmt.Sprintf("%s/%s", baseRouteWithoutTemplate, existingJob.ID), "success@test.com")
+				Expect(status).To(Equal(http.StatusOK))
+
+				var job map[string]interface{}
+				err = json.Unmarshal([]byte(body), &job)
+				Expect(err).NotTo(HaveOccurred())
+				throttle := job["throttle"].(map[string]interface{})
+				Expect(throttle["pushRate"]).To(Equal(10.0))
+				Expect(throttle["targetTokens"]).To(BeNumerically("~", 100, 10))
+				Expect(throttle["completedTokens"]).To(Equal(50.0))
+				Expect(throttle["estimatedCompletedAt"]).To(BeNumerically(">", time.Now().Add(90*time.Second).UnixNano()))
 			})
 		})
 
@@ -1197,6 +1513,31 @@ var _ = Describe("Job Handler", func() {
 				Expect(dbJob.ID).To(Equal(existingJob.ID))
 				Expect(dbJob.Status).To(Equal("stopped"))
 			})
+
+			It("should remove the scheduled messages of a job that has not started", func() {
+				payload := GetJobPayload()
+				payload["startsAt"] = time.Now().Add(time.Hour).UnixNano()
+				payload["csvPath"] = "bucket/somecsv"
+				payload["filters"] = map[string]interface{}{}
+				pl, _ := json.Marshal(payload)
+				status, body := Post(app, baseRoute, string(pl), "success@test.com")
+				Expect(status).To(Equal(http.StatusCreated))
+
+				var job map[string]interface{}
+				err := json.Unmarshal([]byte(body), &job)
+				Expect(err).NotTo(HaveOccurred())
+
+				res, err := w.RedisClient.ZRange("schedule", 0, -1).Result()
+				Expect(err).NotTo(HaveOccurred())
+				Expect(res).To(HaveLen(1))
+
+				status, _ = Put(app, fmt.Sprintf("%s/%s/stop", baseRouteWithoutTemplate, job["id"]), "", "success@test.com")
+				Expect(status).To(Equal(http.StatusOK))
+
+				res, err = w.RedisClient.ZRange("schedule", 0, -1).Result()
+				Expect(err).NotTo(HaveOccurred())
+				Expect(res).To(HaveLen(0))
+			})
 		})
 
 		Describe("Unsucesfully", func() {
@@ -1224,6 +1565,100 @@ var _ = Describe("Job Handler", func() {
 		})
 	})
 
+	Describe("Put /apps/:id/jobs/:jid/reschedule", func() {
+		var createScheduledJob = func() map[string]interface{} {
+			payload := GetJobPayload()
+			payload["startsAt"] = time.Now().Add(time.Hour).UnixNano()
+			payload["csvPath"] = "bucket/somecsv"
+			payload["filters"] = map[string]interface{}{}
+			pl, _ := json.Marshal(payload)
+			status, body := Post(app, baseRoute, string(pl), "success@test.com")
+			Expect(status).To(Equal(http.StatusCreated))
+
+			var job map[string]interface{}
+			err := json.Unmarshal([]byte(body), &job)
+			Expect(err).NotTo(HaveOccurred())
+			return job
+		}
+
+		Describe("Sucesfully", func() {
+			It("should return 200 and replace the scheduled message", func() {
+				job := createScheduledJob()
+				startsAt := time.Now().Add(2 * time.Hour).UnixNano()
+				pl, _ := json.Marshal(map[string]interface{}{
+					"startsAt":     startsAt,
+					"templateName": anotherTemplate.Name,
+					"context":      map[string]interface{}{"value": "rescheduled"},
+				})
+				status, body := Put(app, fmt.Sprintf("%s/%s/reschedule", baseRouteWithoutTemplate, job["id"]), string(pl), "success@test.com")
+				Expect(status).To(Equal(http.StatusOK))
+
+				var rescheduled map[string]interface{}
+				err := json.Unmarshal([]byte(body), &rescheduled)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(rescheduled["id"]).To(Equal(job["id"]))
+				Expect(rescheduled["jobGroupId"]).To(Equal(job["jobGroupId"]))
+				Expect(rescheduled["startsAt"]).To(Equal(float64(startsAt)))
+				Expect(rescheduled["templateName"]).To(Equal(anotherTemplate.Name))
+				Expect(rescheduled["context"]).To(Equal(map[string]interface{}{"value": "rescheduled"}))
+
+				res, err := w.RedisClient.ZRange("schedule", 0, -1).Result()
+				Expect(err).NotTo(HaveOccurred())
+				Expect(res).To(HaveLen(1))
+				var result map[string]interface{}
+				err = json.Unmarshal([]byte(res[0]), &result)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(result["args"]).To(Equal(job["id"]))
+				Expect(result["at"].(float64)).To(Equal(float64(startsAt) / 1000000000.0))
+
+				id, err := uuid.FromString(job["id"].(string))
+				Expect(err).NotTo(HaveOccurred())
+				dbJob := &model.Job{ID: id}
+				err = app.DB.Select(&dbJob)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(dbJob.StartsAt).To(Equal(startsAt))
+				Expect(dbJob.TemplateName).To(Equal(anotherTemplate.Name))
+			})
+		})
+
+		Describe("Unsucesfully", func() {
+			It("should return 401 if no authenticated user", func() {
+				job := createScheduledJob()
+				status, _ := Put(app, fmt.Sprintf("%s/%s/reschedule", baseRouteWithoutTemplate, job["id"]), "{}", "")
+				Expect(status).To(Equal(http.StatusUnauthorized))
+			})
+
+			It("should return 404 if the job does not exist", func() {
+				pl, _ := json.Marshal(map[string]interface{}{"startsAt": time.Now().Add(time.Hour).UnixNano()})
+				status, _ := Put(app, fmt.Sprintf("%s/%s/reschedule", baseRouteWithoutTemplate, uuid.NewV4().String()), string(pl), "test@test.com")
+				Expect(status).To(Equal(http.StatusNotFound))
+			})
+
+			It("should return 422 if startsAt is in the past", func() {
+				job := createScheduledJob()
+				pl, _ := json.Marshal(map[string]interface{}{"startsAt": time.Now().Add(-time.Hour).UnixNano()})
+				status, _ := Put(app, fmt.Sprintf("%s/%s/reschedule", baseRouteWithoutTemplate, job["id"]), string(pl), "test@test.com")
+				Expect(status).To(Equal(http.StatusUnprocessableEntity))
+			})
+
+			It("should return 403 if the job is not pending", func() {
+				existingJob := CreateTestJob(app.DB, existingApp.ID, existingTemplate.Name, map[string]interface{}{
+					"startsAt": time.Now().Add(-time.Hour).UnixNano(),
+				})
+				pl, _ := json.Marshal(map[string]interface{}{"startsAt": time.Now().Add(time.Hour).UnixNano()})
+				status, _ := Put(app, fmt.Sprintf("%s/%s/reschedule", baseRouteWithoutTemplate, existingJob.ID), string(pl), "test@test.com")
+				Expect(status).To(Equal(http.StatusForbidden))
+			})
+
+			It("should return 409 if the job was already enqueued by the workers", func() {
+				existingJob := CreateTestJob(app.DB, existingApp.ID, existingTemplate.Name)
+				pl, _ := json.Marshal(map[string]interface{}{"startsAt": time.Now().Add(2 * time.Hour).UnixNano()})
+				status, _ := Put(app, fmt.Sprintf("%s/%s/reschedule", baseRouteWithoutTemplate, existingJob.ID), string(pl), "test@test.com")
+				Expect(status).To(Equal(http.StatusConflict))
+			})
+		})
+	})
+
 	Describe("Put /apps/:id/jobs/:jid/resume", func() {
 		Describe("Sucesfully", func() {
 			It("should start the resume_job_worker and return 200 and the updated job", func() {
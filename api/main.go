@@ -197,6 +197,7 @@ func (a *Application) configureApplication() {
 	appGroup.Use(NewVersionMiddleware().Serve)
 	appGroup.Use(NewSentryMiddleware(a).Serve)
 	appGroup.Use(NewNewRelicMiddleware(a, a.Logger).Serve)
+	appGroup.Use(NewAuditMiddleware(a).Serve)
 
 	// Apps Routes
 	appGroup.POST("", a.PostAppHandler)
@@ -204,13 +205,21 @@ func (a *Application) configureApplication() {
 	appGroup.GET("/:aid", a.GetAppHandler)
 	appGroup.PUT("/:aid", a.PutAppHandler)
 	appGroup.DELETE("/:aid", a.DeleteAppHandler)
+	appGroup.GET("/:aid/holdout", a.GetHoldoutHandler)
+	appGroup.GET("/:aid/holdout/export", a.ExportHoldoutHandler)
 
 	// Templates Routes
 	appGroup.POST("/:aid/templates", a.PostTemplateHandler)
 	appGroup.GET("/:aid/templates", a.ListTemplatesHandler)
+	appGroup.POST("/:aid/templates/import", a.ImportTemplatesHandler)
+	appGroup.GET("/:aid/templates/export", a.ExportTemplatesHandler)
 	appGroup.GET("/:aid/templates/:tid", a.GetTemplateHandler)
 	appGroup.PUT("/:aid/templates/:tid", a.PutTemplateHandler)
 	appGroup.DELETE("/:aid/templates/:tid", a.DeleteTemplateHandler)
+	appGroup.POST("/:aid/templates/:tid/preview", a.PreviewTemplateHandler)
+	appGroup.GET("/:aid/templates/:tid/versions", a.ListTemplateVersionsHandler)
+	appGroup.GET("/:aid/templates/:tid/versions/diff", a.DiffTemplateVersionsHandler)
+	appGroup.PUT("/:aid/templates/:tid/rollback", a.RollbackTemplateHandler)
 
 	// Jobs Routes
 	appGroup.POST("/:aid/jobs", a.PostJobHandler)
@@ -219,6 +228,28 @@ func (a *Application) configureApplication() {
 	appGroup.PUT("/:aid/jobs/:jid/pause", a.PauseJobHandler)
 	appGroup.PUT("/:aid/jobs/:jid/stop", a.StopJobHandler)
 	appGroup.PUT("/:aid/jobs/:jid/resume", a.ResumeJobHandler)
+	appGroup.PUT("/:aid/jobs/:jid/reschedule", a.RescheduleJobHandler)
+	appGroup.PUT("/:aid/jobs/:jid/approve", a.ApproveJobHandler)
+	appGroup.PUT("/:aid/jobs/:jid/reject", a.RejectJobHandler)
+	appGroup.POST("/:aid/jobs/:jid/uplift", a.PostUpliftHandler)
+
+	// Job Groups Routes
+	appGroup.GET("/:aid/jobgroups/:gid", a.GetJobGroupHandler)
+	appGroup.GET("/:aid/jobgroups/:gid/jobs", a.ListJobGroupJobsHandler)
+	appGroup.PUT("/:aid/jobgroups/:gid/pause", a.PauseJobGroupHandler)
+	appGroup.PUT("/:aid/jobgroups/:gid/stop", a.StopJobGroupHandler)
+	appGroup.PUT("/:aid/jobgroups/:gid/resume", a.ResumeJobGroupHandler)
+
+	// Schedules Routes
+	appGroup.POST("/:aid/schedules", a.PostScheduleHandler)
+	appGroup.GET("/:aid/schedules", a.ListSchedulesHandler)
+	appGroup.GET("/:aid/schedules/:sid", a.GetScheduleHandler)
+	appGroup.PUT("/:aid/schedules/:sid/pause", a.PauseScheduleHandler)
+	appGroup.PUT("/:aid/schedules/:sid/resume", a.ResumeScheduleHandler)
+	appGroup.DELETE("/:aid/schedules/:sid", a.DeleteScheduleHandler)
+
+	// Audience Routes
+	appGroup.POST("/:aid/audience/estimate", a.PostAudienceEstimateHandler)
 
 	userGroup := e.Group("/users")
 	// AuthMiddleware MUST be the first middleware
@@ -228,6 +259,7 @@ func (a *Application) configureApplication() {
 	userGroup.Use(NewVersionMiddleware().Serve)
 	userGroup.Use(NewSentryMiddleware(a).Serve)
 	userGroup.Use(NewNewRelicMiddleware(a, a.Logger).Serve)
+	userGroup.Use(NewAuditMiddleware(a).Serve)
 
 	// User Routes
 	userGroup.GET("", a.ListUsersHandler)
@@ -235,6 +267,21 @@ func (a *Application) configureApplication() {
 	userGroup.GET("/:uid", a.GetUserHandler)
 	userGroup.PUT("/:uid", a.UpdateUserHandler)
 	userGroup.DELETE("/:uid", a.DeleteUserHandler)
+	userGroup.GET("/:uid/tokens", a.ListAPITokensHandler)
+	userGroup.POST("/:uid/tokens", a.CreateAPITokenHandler)
+	userGroup.DELETE("/:uid/tokens/:tkid", a.RevokeAPITokenHandler)
+
+	auditGroup := e.Group("/audit")
+	// AuthMiddleware MUST be the first middleware
+	auditGroup.Use(NewUserAuthMiddleware(a).Serve)
+	auditGroup.Use(NewLoggerMiddleware(a.Logger).Serve)
+	auditGroup.Use(NewRecoveryMiddleware(a.OnErrorHandler).Serve)
+	auditGroup.Use(NewVersionMiddleware().Serve)
+	auditGroup.Use(NewSentryMiddleware(a).Serve)
+	auditGroup.Use(NewNewRelicMiddleware(a, a.Logger).Serve)
+
+	// Audit Routes
+	auditGroup.GET("", a.ListAuditLogsHandler)
 
 	a.API = e
 	a.PrometheusExporter = prometheusExporter
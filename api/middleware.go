@@ -27,6 +27,7 @@ import (
 	"fmt"
 	"net/http"
 	"runtime/debug"
+	"strings"
 	"time"
 
 	"github.com/getsentry/raven-go"
@@ -223,6 +224,92 @@ func (l *LoggerMiddleware) Serve(next echo.HandlerFunc) echo.HandlerFunc {
 	}
 }
 
+// NewAuditMiddleware returns the audit middleware
+func NewAuditMiddleware(app *Application) *AuditMiddleware {
+	return &AuditMiddleware{App: app}
+}
+
+// AuditMiddleware writes an audit log for every request that changes apps or users, with the
+// fields of the changed resource that differ before and after the request
+type AuditMiddleware struct {
+	App *Application
+}
+
+// Serve serves the middleware
+func (a *AuditMiddleware) Serve(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		requestID := c.Request().Header.Get(echo.HeaderXRequestID)
+		if requestID == "" {
+			requestID = uuid.NewV4().String()
+		}
+		c.Response().Header().Set(echo.HeaderXRequestID, requestID)
+
+		method := c.Request().Method
+		route := fmt.Sprintf("%s %s", method, c.Path())
+		// dry runs only show what the request would change
+		isDryRun := auditDryRunRoutes[route] && c.QueryParam("dryRun") == "true"
+		if method == http.MethodGet || auditSkippedRoutes[route] || isDryRun {
+			return next(c)
+		}
+		l := a.App.Logger.With(
+			zap.String("source", "auditMiddleware"),
+			zap.String("route", c.Path()),
+			zap.String("requestId", requestID),
+		)
+
+		param, id := auditTarget(c)
+		before, err := a.App.auditSnapshot(param, id)
+		if err != nil {
+			log.E(l, "Failed to retrieve audit snapshot.", func(cm log.CM) {
+				cm.Write(zap.Error(err))
+			})
+		}
+		writer := &auditResponseWriter{ResponseWriter: c.Response().Writer}
+		c.Response().Writer = writer
+		err = next(c)
+		c.Response().Writer = writer.ResponseWriter
+
+		auditLog := &model.AuditLog{
+			Method:    method,
+			Route:     c.Path(),
+			Path:      c.Request().URL.Path,
+			TargetIDs: map[string]string{},
+			Status:    c.Response().Status,
+			RequestID: requestID,
+		}
+		if email, ok := c.Get("user-email").(string); ok {
+			auditLog.Actor = email
+		}
+		for _, name := range c.ParamNames() {
+			auditLog.TargetIDs[name] = c.Param(name)
+		}
+		auditLog.AppID, _ = uuid.FromString(c.Param("aid"))
+
+		var after map[string]interface{}
+		if param != "" {
+			var snapshotErr error
+			after, snapshotErr = a.App.auditSnapshot(param, id)
+			if snapshotErr != nil {
+				log.E(l, "Failed to retrieve audit snapshot.", func(cm log.CM) {
+					cm.Write(zap.Error(snapshotErr))
+				})
+			}
+		} else if auditLog.Status < http.StatusBadRequest {
+			var createdID string
+			after, createdID = auditCreated(writer.body.Bytes())
+			if createdID != "" {
+				auditLog.TargetIDs["id"] = createdID
+				if c.Path() == "/apps" {
+					auditLog.AppID, _ = uuid.FromString(createdID)
+				}
+			}
+		}
+		auditLog.Diff = model.DiffAudit(before, after)
+		a.App.writeAuditLog(c, auditLog)
+		return err
+	}
+}
+
 // NewNewRelicMiddleware returns the logger middleware
 func NewNewRelicMiddleware(app *Application, theLogger zap.Logger) *NewRelicMiddleware {
 	l := &NewRelicMiddleware{App: app, Logger: theLogger}
@@ -257,30 +344,77 @@ func (nr *NewRelicMiddleware) Serve(next echo.HandlerFunc) echo.HandlerFunc {
 	}
 }
 
-// AppAuthMiddleware automatically adds a version header to response
-type AppAuthMiddleware struct {
-	App *Application
-}
-
-// Serve Validate that a user exists
-func (a AppAuthMiddleware) Serve(next echo.HandlerFunc) echo.HandlerFunc {
-	return func(c echo.Context) error {
+// authenticate returns the user of the request and, if the request has an Authorization bearer
+// header, the API token that identified the user, otherwise the user is the one of the
+// x-forwarded-email header. It returns skip if the response was already written
+func authenticate(a *Application, c echo.Context) (*model.User, *model.APIToken, bool, error) {
+	user := &model.User{}
+	bearer := c.Request().Header.Get("Authorization")
+	if !strings.HasPrefix(bearer, "Bearer ") {
 		userEmail := c.Request().Header.Get("x-forwarded-email")
 		if userEmail == "" {
-			return c.JSON(http.StatusUnauthorized, map[string]string{"status": "Unauthorized."})
+			return nil, nil, true, c.JSON(http.StatusUnauthorized, map[string]string{"status": "Unauthorized."})
 		}
 		c.Set("user-email", userEmail)
-		user := &model.User{}
 		err := WithSegment("db-select", c, func() error {
-			return a.App.DB.Model(&user).Column("*").Where("email = ?", userEmail).Select()
+			return a.DB.Model(&user).Column("*").Where("email = ?", userEmail).Select()
 		})
 		if err != nil {
 			if err.Error() == RecordNotFoundString {
-				return c.JSON(http.StatusUnauthorized, map[string]string{"status": "Unauthorized."})
+				return nil, nil, true, c.JSON(http.StatusUnauthorized, map[string]string{"status": "Unauthorized."})
 			}
-			return c.JSON(http.StatusInternalServerError, &Error{Reason: err.Error()})
+			return nil, nil, true, c.JSON(http.StatusInternalServerError, &Error{Reason: err.Error()})
+		}
+		return user, nil, false, nil
+	}
+
+	token := &model.APIToken{}
+	err := WithSegment("db-select", c, func() error {
+		hash := model.HashAPIToken(strings.TrimPrefix(bearer, "Bearer "))
+		return a.DB.Model(token).Column("*").Where("token_hash = ?", hash).Select()
+	})
+	if err == nil && !token.IsActive(time.Now()) {
+		return nil, nil, true, c.JSON(http.StatusUnauthorized, map[string]string{"status": "Unauthorized."})
+	}
+	if err == nil {
+		err = WithSegment("db-select", c, func() error {
+			return a.DB.Model(&user).Column("*").Where("id = ?", token.UserID).Select()
+		})
+	}
+	if err != nil {
+		if err.Error() == RecordNotFoundString {
+			return nil, nil, true, c.JSON(http.StatusUnauthorized, map[string]string{"status": "Unauthorized."})
+		}
+		return nil, nil, true, c.JSON(http.StatusInternalServerError, &Error{Reason: err.Error()})
+	}
+	c.Set("user-email", user.Email)
+	c.Set("api-token", token)
+
+	token.LastUsedAt = time.Now().UnixNano()
+	WithSegment("db-update", c, func() error {
+		_, err := a.DB.Model(token).Set("last_used_at = ?", token.LastUsedAt).Where("id = ?", token.ID).Update()
+		return err
+	})
+	return user, token, false, nil
+}
+
+// AppAuthMiddleware automatically adds a version header to response
+type AppAuthMiddleware struct {
+	App *Application
+}
+
+// Serve Validate that a user exists
+func (a AppAuthMiddleware) Serve(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		user, token, skip, err := authenticate(a.App, c)
+		if err != nil || skip {
+			return err
 		}
 		path := c.Path()
+		role := AppRouteRole(c.Request().Method, path)
+		if token != nil && !token.HasScope(role) {
+			return c.JSON(http.StatusForbidden, map[string]string{"status": "Forbidden."})
+		}
 		if path == "/apps" {
 			return next(c)
 		}
@@ -291,15 +425,54 @@ func (a AppAuthMiddleware) Serve(next echo.HandlerFunc) echo.HandlerFunc {
 		if err != nil {
 			return c.JSON(http.StatusUnprocessableEntity, &Error{Reason: err.Error()})
 		}
-		for _, appID := range user.AllowedApps {
-			if appID == aid {
-				return next(c)
-			}
+		if user.IsAllowedApp(aid) || user.HasAppRole(aid, role) {
+			return next(c)
 		}
 		return c.JSON(http.StatusForbidden, map[string]string{"status": "Forbidden."})
 	}
 }
 
+// appRouteRoles has the role needed by each app route that is not a GET, the GET routes only
+// read and need the viewer role
+var appRouteRoles = map[string]string{
+	"PUT /apps/:aid":                         model.RoleAppAdmin,
+	"DELETE /apps/:aid":                      model.RoleAppAdmin,
+	"POST /apps/:aid/templates":              model.RoleTemplateEditor,
+	"POST /apps/:aid/templates/import":       model.RoleTemplateEditor,
+	"PUT /apps/:aid/templates/:tid":          model.RoleTemplateEditor,
+	"DELETE /apps/:aid/templates/:tid":       model.RoleTemplateEditor,
+	"POST /apps/:aid/templates/:tid/preview": model.RoleTemplateEditor,
+	"PUT /apps/:aid/templates/:tid/rollback": model.RoleTemplateEditor,
+	"POST /apps/:aid/jobs":                   model.RoleJobCreator,
+	"PUT /apps/:aid/jobs/:jid/pause":         model.RoleJobCreator,
+	"PUT /apps/:aid/jobs/:jid/stop":          model.RoleJobCreator,
+	"PUT /apps/:aid/jobs/:jid/resume":        model.RoleJobCreator,
+	"PUT /apps/:aid/jobs/:jid/reschedule":    model.RoleJobCreator,
+	"PUT /apps/:aid/jobs/:jid/approve":       model.RoleApprover,
+	"PUT /apps/:aid/jobs/:jid/reject":        model.RoleApprover,
+	"POST /apps/:aid/jobs/:jid/uplift":       model.RoleJobCreator,
+	"PUT /apps/:aid/jobgroups/:gid/pause":    model.RoleJobCreator,
+	"PUT /apps/:aid/jobgroups/:gid/stop":     model.RoleJobCreator,
+	"PUT /apps/:aid/jobgroups/:gid/resume":   model.RoleJobCreator,
+	"POST /apps/:aid/schedules":              model.RoleJobCreator,
+	"PUT /apps/:aid/schedules/:sid/pause":    model.RoleJobCreator,
+	"PUT /apps/:aid/schedules/:sid/resume":   model.RoleJobCreator,
+	"DELETE /apps/:aid/schedules/:sid":       model.RoleJobCreator,
+	"POST /apps/:aid/audience/estimate":      model.RoleViewer,
+}
+
+// AppRouteRole returns the role a user needs in the app to call the route, routes that are not
+// listed need the viewer role if they only read and the app admin role otherwise
+func AppRouteRole(method, path string) string {
+	if role, ok := appRouteRoles[fmt.Sprintf("%s %s", method, path)]; ok {
+		return role
+	}
+	if method == http.MethodGet {
+		return model.RoleViewer
+	}
+	return model.RoleAppAdmin
+}
+
 // NewAppAuthMiddleware returns a configured auth middleware
 func NewAppAuthMiddleware(app *Application) *AppAuthMiddleware {
 	return &AppAuthMiddleware{
@@ -315,20 +488,12 @@ type UserAuthMiddleware struct {
 // Serve Validate that a user exists
 func (a UserAuthMiddleware) Serve(next echo.HandlerFunc) echo.HandlerFunc {
 	return func(c echo.Context) error {
-		userEmail := c.Request().Header.Get("x-forwarded-email")
-		if userEmail == "" {
-			return c.JSON(http.StatusUnauthorized, map[string]string{"status": "Unauthorized."})
+		user, token, skip, err := authenticate(a.App, c)
+		if err != nil || skip {
+			return err
 		}
-		c.Set("user-email", userEmail)
-		user := &model.User{}
-		err := WithSegment("db-select", c, func() error {
-			return a.App.DB.Model(&user).Column("*").Where("email = ?", userEmail).Select()
-		})
-		if err != nil {
-			if err.Error() == RecordNotFoundString {
-				return c.JSON(http.StatusUnauthorized, map[string]string{"status": "Unauthorized."})
-			}
-			return c.JSON(http.StatusInternalServerError, &Error{Reason: err.Error()})
+		if token != nil && !token.HasScope(model.RoleAppAdmin) {
+			return c.JSON(http.StatusForbidden, map[string]string{"status": "Forbidden."})
 		}
 		path := c.Path()
 		if path == "/users" {
@@ -337,6 +502,10 @@ func (a UserAuthMiddleware) Serve(next echo.HandlerFunc) echo.HandlerFunc {
 		if user.IsAdmin {
 			return next(c)
 		}
+		// users manage their own API tokens
+		if strings.HasPrefix(path, "/users/:uid/tokens") && c.Param("uid") == user.ID.String() {
+			return next(c)
+		}
 		return c.JSON(http.StatusForbidden, map[string]string{"status": "Forbidden."})
 	}
 }
@@ -356,20 +525,12 @@ type UploadAuthMiddleware struct {
 // Serve Validate that a user exists
 func (a UploadAuthMiddleware) Serve(next echo.HandlerFunc) echo.HandlerFunc {
 	return func(c echo.Context) error {
-		userEmail := c.Request().Header.Get("x-forwarded-email")
-		if userEmail == "" {
-			return c.JSON(http.StatusUnauthorized, map[string]string{"status": "Unauthorized."})
+		_, token, skip, err := authenticate(a.App, c)
+		if err != nil || skip {
+			return err
 		}
-		c.Set("user-email", userEmail)
-		user := &model.User{}
-		err := WithSegment("db-select", c, func() error {
-			return a.App.DB.Model(&user).Column("*").Where("email = ?", userEmail).Select()
-		})
-		if err != nil {
-			if err.Error() == RecordNotFoundString {
-				return c.JSON(http.StatusUnauthorized, map[string]string{"status": "Unauthorized."})
-			}
-			return c.JSON(http.StatusInternalServerError, &Error{Reason: err.Error()})
+		if token != nil && !token.HasScope(model.RoleJobCreator) {
+			return c.JSON(http.StatusForbidden, map[string]string{"status": "Forbidden."})
 		}
 		return next(c)
 	}
@@ -0,0 +1,137 @@
+/*
+ * Copyright (c) 2026 TFG Co <backend@tfgco.com>
+ * Author: TFG Co <backend@tfgco.com>
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy of
+ * this software and associated documentation files (the "Software"), to deal in
+ * the Software without restriction, including without limitation the rights to
+ * use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+ * the Software, and to permit persons to whom the Software is furnished to do so,
+ * subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+ * FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+ * COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+ * IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+ * CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+ */
+
+package api_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	uuid "github.com/satori/go.uuid"
+	"github.com/topfreegames/marathon/api"
+	"github.com/topfreegames/marathon/model"
+	. "github.com/topfreegames/marathon/testing"
+	"github.com/uber-go/zap"
+)
+
+var _ = Describe("App Auth Middleware", func() {
+	logger := zap.New(
+		zap.NewJSONEncoder(zap.NoTime()), // drop timestamps in tests
+		zap.FatalLevel,
+	)
+	app := GetDefaultTestApp(logger)
+	var existingApp *model.App
+	var existingTemplate *model.Template
+
+	BeforeEach(func() {
+		app.DB.Exec("DELETE FROM apps;")
+		app.DB.Exec("DELETE FROM users;")
+		app.Worker.RedisClient.FlushAll()
+
+		existingApp = CreateTestApp(app.DB)
+		existingTemplate = CreateTestTemplate(app.DB, existingApp.ID, map[string]interface{}{
+			"locale": "en",
+		})
+		for _, role := range []string{"viewer", "template_editor", "job_creator", "app_admin"} {
+			CreateTestUser(app.DB, map[string]interface{}{
+				"email":       fmt.Sprintf("%s@test.com", role),
+				"isAdmin":     false,
+				"allowedApps": []uuid.UUID{},
+				"appRoles":    map[string][]string{existingApp.ID.String(): {role}},
+			})
+		}
+	})
+
+	Describe("App route roles", func() {
+		It("should require the viewer role to read", func() {
+			Expect(api.AppRouteRole("GET", "/apps/:aid/jobs/:jid")).To(Equal(model.RoleViewer))
+			Expect(api.AppRouteRole("POST", "/apps/:aid/audience/estimate")).To(Equal(model.RoleViewer))
+		})
+
+		It("should require the role of each route that changes the app", func() {
+			Expect(api.AppRouteRole("POST", "/apps/:aid/jobs")).To(Equal(model.RoleJobCreator))
+			Expect(api.AppRouteRole("PUT", "/apps/:aid/templates/:tid")).To(Equal(model.RoleTemplateEditor))
+			Expect(api.AppRouteRole("PUT", "/apps/:aid/jobs/:jid/approve")).To(Equal(model.RoleApprover))
+			Expect(api.AppRouteRole("PUT", "/apps/:aid")).To(Equal(model.RoleAppAdmin))
+		})
+
+		It("should require the app admin role for unknown routes that change the app", func() {
+			Expect(api.AppRouteRole("DELETE", "/apps/:aid/unknown")).To(Equal(model.RoleAppAdmin))
+		})
+	})
+
+	Describe("Roles", func() {
+		It("should let a viewer read the app jobs", func() {
+			CreateTestJob(app.DB, existingApp.ID, existingTemplate.Name)
+			status, body := Get(app, fmt.Sprintf("/apps/%s/jobs", existingApp.ID), "viewer@test.com")
+			Expect(status).To(Equal(http.StatusOK))
+
+			var response []map[string]interface{}
+			err := json.Unmarshal([]byte(body), &response)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(response).To(HaveLen(1))
+		})
+
+		It("should not let a viewer create a job", func() {
+			pl, _ := json.Marshal(GetJobPayload())
+			route := fmt.Sprintf("/apps/%s/jobs?template=%s", existingApp.ID, existingTemplate.Name)
+			status, _ := Post(app, route, string(pl), "viewer@test.com")
+			Expect(status).To(Equal(http.StatusForbidden))
+		})
+
+		It("should let a job creator create a job", func() {
+			pl, _ := json.Marshal(GetJobPayload())
+			route := fmt.Sprintf("/apps/%s/jobs?template=%s", existingApp.ID, existingTemplate.Name)
+			status, _ := Post(app, route, string(pl), "job_creator@test.com")
+			Expect(status).To(Equal(http.StatusCreated))
+		})
+
+		It("should not let a job creator change a template", func() {
+			pl, _ := json.Marshal(GetTemplatePayload())
+			route := fmt.Sprintf("/apps/%s/templates/%s", existingApp.ID, existingTemplate.ID)
+			status, _ := Put(app, route, string(pl), "job_creator@test.com")
+			Expect(status).To(Equal(http.StatusForbidden))
+
+			status, _ = Put(app, route, string(pl), "template_editor@test.com")
+			Expect(status).To(Equal(http.StatusOK))
+		})
+
+		It("should only let an app admin change the app", func() {
+			pl, _ := json.Marshal(GetAppPayload())
+			route := fmt.Sprintf("/apps/%s", existingApp.ID)
+			status, _ := Put(app, route, string(pl), "template_editor@test.com")
+			Expect(status).To(Equal(http.StatusForbidden))
+
+			status, _ = Put(app, route, string(pl), "app_admin@test.com")
+			Expect(status).To(Equal(http.StatusOK))
+		})
+
+		It("should not let a user read an app in which it has no role", func() {
+			otherApp := CreateTestApp(app.DB, map[string]interface{}{"name": "otherapp"})
+			status, _ := Get(app, fmt.Sprintf("/apps/%s/jobs", otherApp.ID), "app_admin@test.com")
+			Expect(status).To(Equal(http.StatusForbidden))
+		})
+	})
+})
@@ -0,0 +1,302 @@
+/*
+ * Copyright (c) 2026 TFG Co <backend@tfgco.com>
+ * Author: TFG Co <backend@tfgco.com>
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy of
+ * this software and associated documentation files (the "Software"), to deal in
+ * the Software without restriction, including without limitation the rights to
+ * use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+ * the Software, and to permit persons to whom the Software is furnished to do so,
+ * subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+ * FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+ * COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+ * IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+ * CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+ */
+
+package api
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	uuid "github.com/satori/go.uuid"
+	"github.com/topfreegames/marathon/log"
+	"github.com/topfreegames/marathon/model"
+	"github.com/uber-go/zap"
+	"gopkg.in/pg.v5/types"
+)
+
+// ListSchedulesHandler is the method called when a get to /apps/:aid/schedules is called
+func (a *Application) ListSchedulesHandler(c echo.Context) error {
+	l := a.Logger.With(
+		zap.String("source", "scheduleHandler"),
+		zap.String("operation", "listSchedules"),
+		zap.String("appId", c.Param("aid")),
+	)
+	aid, err := uuid.FromString(c.Param("aid"))
+	if err != nil {
+		return c.JSON(http.StatusUnprocessableEntity, &Error{Reason: err.Error()})
+	}
+	schedules := []model.Schedule{}
+	err = WithSegment("db-select", c, func() error {
+		return a.DB.Model(&schedules).Column("schedule.*", "App").Where("schedule.app_id = ?", aid).Select()
+	})
+	if err != nil {
+		log.E(l, "Failed to list schedules.", func(cm log.CM) {
+			cm.Write(zap.Error(err))
+		})
+		return c.JSON(http.StatusInternalServerError, &Error{Reason: err.Error()})
+	}
+	log.D(l, "Listed schedules successfully.", func(cm log.CM) {
+		cm.Write(zap.Object("schedules", schedules))
+	})
+	return c.JSON(http.StatusOK, schedules)
+}
+
+// PostScheduleHandler is the method called when a post to /apps/:aid/schedules is called
+func (a *Application) PostScheduleHandler(c echo.Context) error {
+	l := a.Logger.With(
+		zap.String("source", "scheduleHandler"),
+		zap.String("operation", "postSchedule"),
+		zap.String("appId", c.Param("aid")),
+		zap.String("template", c.QueryParam("template")),
+	)
+	aid, err := uuid.FromString(c.Param("aid"))
+	if err != nil {
+		return c.JSON(http.StatusUnprocessableEntity, &Error{Reason: err.Error()})
+	}
+
+	app := &model.App{ID: aid}
+	err = WithSegment("db-select", c, func() error {
+		return a.DB.Select(&app)
+	})
+	if err != nil {
+		if err.Error() == RecordNotFoundString {
+			return c.JSON(http.StatusUnprocessableEntity, &Error{Reason: "App not found with given id."})
+		}
+		log.E(l, "Failed to retrieve app.", func(cm log.CM) {
+			cm.Write(zap.Error(err))
+		})
+		return c.JSON(http.StatusInternalServerError, &Error{Reason: err.Error(), Value: app})
+	}
+
+	templateName := c.QueryParam("template")
+	if templateName == "" {
+		return c.JSON(http.StatusUnprocessableEntity, &Error{Reason: "template name must be specified"})
+	}
+	userEmail := c.Get("user-email").(string)
+	schedule := &model.Schedule{
+		ID:           uuid.NewV4(),
+		AppID:        aid,
+		TemplateName: templateName,
+		CreatedBy:    userEmail,
+		CreatedAt:    time.Now().UnixNano(),
+		UpdatedAt:    time.Now().UnixNano(),
+	}
+
+	err = WithSegment("decodeAndValidate", c, func() error {
+		return decodeAndValidate(c, schedule)
+	})
+	if err != nil {
+		return c.JSON(http.StatusUnprocessableEntity, &Error{Reason: err.Error(), Value: schedule})
+	}
+	schedule.AppID = aid
+	schedule.App = *app
+	schedule.TemplateName = templateName
+	schedule.CreatedBy = userEmail
+	schedule.TotalRuns = 0
+	schedule.LastRunAt = 0
+	schedule.Paused = false
+
+	// the schedule jobs go through the same checks as the jobs created directly
+	job := schedule.NewJob(time.Now())
+	skip, err := a.checkFilters(job, c)
+	if err != nil || skip {
+		return err
+	}
+	schedule.Filters = job.Filters
+	skip, err = a.checkTemplateName(templateName, job, c)
+	if err != nil || skip {
+		return err
+	}
+
+	next, err := schedule.NextRun(time.Now())
+	if err != nil {
+		return c.JSON(http.StatusUnprocessableEntity, &Error{Reason: err.Error(), Value: schedule})
+	}
+	if next.IsZero() {
+		return c.JSON(http.StatusUnprocessableEntity, &Error{Reason: "schedule has no future occurrences", Value: schedule})
+	}
+	schedule.NextRunAt = next.UnixNano()
+
+	err = WithSegment("db-insert", c, func() error {
+		return a.DB.Insert(&schedule)
+	})
+	if err != nil {
+		log.E(l, "Failed to create schedule.", func(cm log.CM) {
+			cm.Write(zap.Error(err))
+		})
+		if strings.Contains(err.Error(), "duplicate key") {
+			return c.JSON(http.StatusConflict, schedule)
+		}
+		if strings.Contains(err.Error(), "violates foreign key constraint") {
+			return c.JSON(http.StatusUnprocessableEntity, &Error{Reason: err.Error(), Value: schedule})
+		}
+		return c.JSON(http.StatusInternalServerError, &Error{Reason: err.Error(), Value: schedule})
+	}
+	log.D(l, "Created schedule successfully.", func(cm log.CM) {
+		cm.Write(zap.Object("schedule", schedule))
+	})
+	return c.JSON(http.StatusCreated, schedule)
+}
+
+// GetScheduleHandler is the method called when a get to /apps/:aid/schedules/:sid is called
+func (a *Application) GetScheduleHandler(c echo.Context) error {
+	l := a.Logger.With(
+		zap.String("source", "scheduleHandler"),
+		zap.String("operation", "getSchedule"),
+		zap.String("appId", c.Param("aid")),
+		zap.String("scheduleId", c.Param("sid")),
+	)
+	aid, err := uuid.FromString(c.Param("aid"))
+	if err != nil {
+		return c.JSON(http.StatusUnprocessableEntity, &Error{Reason: err.Error()})
+	}
+	sid, err := uuid.FromString(c.Param("sid"))
+	if err != nil {
+		return c.JSON(http.StatusUnprocessableEntity, &Error{Reason: err.Error()})
+	}
+	schedule := &model.Schedule{}
+	err = WithSegment("db-select", c, func() error {
+		return a.DB.Model(&schedule).Column("schedule.*", "App").Where("schedule.id = ? AND schedule.app_id = ?", sid, aid).Select()
+	})
+	if err != nil {
+		if err.Error() == RecordNotFoundString {
+			return c.JSON(http.StatusNotFound, map[string]string{})
+		}
+		log.E(l, "Failed to retrieve schedule.", func(cm log.CM) {
+			cm.Write(zap.Error(err))
+		})
+		return c.JSON(http.StatusInternalServerError, &Error{Reason: err.Error()})
+	}
+	log.D(l, "Retrieved schedule successfully.", func(cm log.CM) {
+		cm.Write(zap.Object("schedule", schedule))
+	})
+	return c.JSON(http.StatusOK, schedule)
+}
+
+// PauseScheduleHandler is the method called when a put to /apps/:aid/schedules/:sid/pause is called
+func (a *Application) PauseScheduleHandler(c echo.Context) error {
+	return a.setSchedulePaused(c, true)
+}
+
+// ResumeScheduleHandler is the method called when a put to /apps/:aid/schedules/:sid/resume is called
+func (a *Application) ResumeScheduleHandler(c echo.Context) error {
+	return a.setSchedulePaused(c, false)
+}
+
+func (a *Application) setSchedulePaused(c echo.Context, paused bool) error {
+	l := a.Logger.With(
+		zap.String("source", "scheduleHandler"),
+		zap.String("operation", "setSchedulePaused"),
+		zap.String("appId", c.Param("aid")),
+		zap.String("scheduleId", c.Param("sid")),
+		zap.Bool("paused", paused),
+	)
+	aid, err := uuid.FromString(c.Param("aid"))
+	if err != nil {
+		return c.JSON(http.StatusUnprocessableEntity, &Error{Reason: err.Error()})
+	}
+	sid, err := uuid.FromString(c.Param("sid"))
+	if err != nil {
+		return c.JSON(http.StatusUnprocessableEntity, &Error{Reason: err.Error()})
+	}
+	schedule := &model.Schedule{}
+	err = WithSegment("db-select", c, func() error {
+		return a.DB.Model(&schedule).Where("schedule.id = ? AND schedule.app_id = ?", sid, aid).Select()
+	})
+	if err != nil {
+		if err.Error() == RecordNotFoundString {
+			return c.JSON(http.StatusNotFound, map[string]string{})
+		}
+		log.E(l, "Failed to retrieve schedule.", func(cm log.CM) {
+			cm.Write(zap.Error(err))
+		})
+		return c.JSON(http.StatusInternalServerError, &Error{Reason: err.Error()})
+	}
+
+	schedule.Paused = paused
+	schedule.UpdatedAt = time.Now().UnixNano()
+	if !paused {
+		// occurrences missed while paused are skipped
+		next, err := schedule.NextRun(time.Now())
+		if err != nil {
+			return c.JSON(http.StatusUnprocessableEntity, &Error{Reason: err.Error(), Value: schedule})
+		}
+		schedule.NextRunAt = 0
+		if !next.IsZero() {
+			schedule.NextRunAt = next.UnixNano()
+		}
+	}
+
+	err = WithSegment("db-update", c, func() error {
+		_, err = a.DB.Model(&schedule).Column("paused").Column("next_run_at").Column("updated_at").Returning("*").Update()
+		return err
+	})
+	if err != nil {
+		log.E(l, "Failed to update schedule.", func(cm log.CM) {
+			cm.Write(zap.Error(err))
+		})
+		return c.JSON(http.StatusInternalServerError, &Error{Reason: err.Error(), Value: schedule})
+	}
+	log.D(l, "Updated schedule successfully.", func(cm log.CM) {
+		cm.Write(zap.Object("schedule", schedule))
+	})
+	return c.JSON(http.StatusOK, schedule)
+}
+
+// DeleteScheduleHandler is the method called when a delete to /apps/:aid/schedules/:sid is called
+func (a *Application) DeleteScheduleHandler(c echo.Context) error {
+	l := a.Logger.With(
+		zap.String("source", "scheduleHandler"),
+		zap.String("operation", "deleteSchedule"),
+		zap.String("appId", c.Param("aid")),
+		zap.String("scheduleId", c.Param("sid")),
+	)
+	aid, err := uuid.FromString(c.Param("aid"))
+	if err != nil {
+		return c.JSON(http.StatusUnprocessableEntity, &Error{Reason: err.Error()})
+	}
+	sid, err := uuid.FromString(c.Param("sid"))
+	if err != nil {
+		return c.JSON(http.StatusUnprocessableEntity, &Error{Reason: err.Error()})
+	}
+	schedule := &model.Schedule{}
+	var res *types.Result
+	err = WithSegment("db-delete", c, func() error {
+		res, err = a.DB.Model(&schedule).Where("id = ? AND app_id = ?", sid, aid).Delete()
+		return err
+	})
+	if err != nil {
+		log.E(l, "Failed to delete schedule.", func(cm log.CM) {
+			cm.Write(zap.Error(err))
+		})
+		return c.JSON(http.StatusInternalServerError, &Error{Reason: err.Error(), Value: schedule})
+	}
+	if res.RowsAffected() == 0 {
+		return c.JSON(http.StatusNotFound, map[string]string{})
+	}
+	log.D(l, "Deleted schedule successfully.", func(cm log.CM) {
+		cm.Write(zap.Object("schedule", schedule))
+	})
+	return c.JSON(http.StatusNoContent, "")
+}
@@ -0,0 +1,308 @@
+/*
+ * Copyright (c) 2026 TFG Co <backend@tfgco.com>
+ * Author: TFG Co <backend@tfgco.com>
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy of
+ * this software and associated documentation files (the "Software"), to deal in
+ * the Software without restriction, including without limitation the rights to
+ * use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+ * the Software, and to permit persons to whom the Software is furnished to do so,
+ * subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+ * FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+ * COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+ * IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+ * CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+ */
+
+package api_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	uuid "github.com/satori/go.uuid"
+	"github.com/topfreegames/marathon/model"
+	. "github.com/topfreegames/marathon/testing"
+	"github.com/uber-go/zap"
+)
+
+var _ = Describe("Schedule Handler", func() {
+	logger := zap.New(
+		zap.NewJSONEncoder(zap.NoTime()), // drop timestamps in tests
+		zap.FatalLevel,
+	)
+	app := GetDefaultTestApp(logger)
+	faultyDb := GetFaultyTestDB(app)
+	var existingApp *model.App
+	var existingTemplate *model.Template
+	var baseRoute string
+	var baseRouteWithoutTemplate string
+
+	BeforeEach(func() {
+		app.DB.Exec("DELETE FROM apps;")
+		app.DB.Exec("DELETE FROM templates;")
+		app.DB.Exec("DELETE FROM users;")
+		CreateTestUser(app.DB, map[string]interface{}{"email": "test@test.com", "isAdmin": true})
+		CreateTestUser(app.DB, map[string]interface{}{"email": "success@test.com", "isAdmin": true})
+
+		existingApp = CreateTestApp(app.DB)
+		existingTemplate = CreateTestTemplate(app.DB, existingApp.ID, map[string]interface{}{
+			"locale": "en",
+		})
+		baseRoute = fmt.Sprintf("/apps/%s/schedules?template=%s", existingApp.ID, existingTemplate.Name)
+		baseRouteWithoutTemplate = fmt.Sprintf("/apps/%s/schedules", existingApp.ID)
+	})
+
+	Describe("Get /apps/:id/schedules", func() {
+		Describe("Sucesfully", func() {
+			It("should return 200 and an empty list if there are no schedules", func() {
+				status, body := Get(app, baseRouteWithoutTemplate, "test@test.com")
+				Expect(status).To(Equal(http.StatusOK))
+
+				var response []map[string]interface{}
+				err := json.Unmarshal([]byte(body), &response)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(response).To(HaveLen(0))
+			})
+
+			It("should return 200 and a list of schedules", func() {
+				for i := 0; i < 5; i++ {
+					CreateTestSchedule(app.DB, existingApp.ID, existingTemplate.Name)
+				}
+				status, body := Get(app, baseRouteWithoutTemplate, "test@test.com")
+				Expect(status).To(Equal(http.StatusOK))
+
+				var response []map[string]interface{}
+				err := json.Unmarshal([]byte(body), &response)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(response).To(HaveLen(5))
+				for _, schedule := range response {
+					Expect(schedule["appId"]).To(Equal(existingApp.ID.String()))
+					Expect(schedule["templateName"]).To(Equal(existingTemplate.Name))
+					Expect(schedule["cronExpression"]).To(Equal("0 10 * * *"))
+				}
+			})
+		})
+
+		Describe("Unsucesfully", func() {
+			It("should return 401 if no authenticated user", func() {
+				status, _ := Get(app, baseRouteWithoutTemplate, "")
+				Expect(status).To(Equal(http.StatusUnauthorized))
+			})
+
+			It("should return 500 if some error occured", func() {
+				goodDB := app.DB
+				app.DB = faultyDb
+				status, _ := Get(app, baseRouteWithoutTemplate, "test@test.com")
+				Expect(status).To(Equal(http.StatusInternalServerError))
+				app.DB = goodDB
+			})
+
+			It("should return 422 if app id is not UUID", func() {
+				status, _ := Get(app, "/apps/not-uuid/schedules", "test@test.com")
+				Expect(status).To(Equal(http.StatusUnprocessableEntity))
+			})
+		})
+	})
+
+	Describe("Post /apps/:id/schedules?template=:templateName", func() {
+		Describe("Sucesfully", func() {
+			It("should return 201 and the created schedule with the next occurrence", func() {
+				payload := GetSchedulePayload()
+				pl, _ := json.Marshal(payload)
+				status, body := Post(app, baseRoute, string(pl), "success@test.com")
+				Expect(status).To(Equal(http.StatusCreated))
+
+				var schedule map[string]interface{}
+				err := json.Unmarshal([]byte(body), &schedule)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(schedule["id"]).ToNot(BeNil())
+				Expect(schedule["appId"]).To(Equal(existingApp.ID.String()))
+				Expect(schedule["templateName"]).To(Equal(existingTemplate.Name))
+				Expect(schedule["cronExpression"]).To(Equal(payload["cronExpression"]))
+				Expect(schedule["timezone"]).To(Equal(payload["timezone"]))
+				Expect(schedule["createdBy"]).To(Equal("success@test.com"))
+				Expect(schedule["paused"]).To(BeFalse())
+				Expect(schedule["totalRuns"]).To(BeEquivalentTo(0))
+
+				location, err := time.LoadLocation("America/Sao_Paulo")
+				Expect(err).NotTo(HaveOccurred())
+				nextRunAt := time.Unix(0, int64(schedule["nextRunAt"].(float64))).In(location)
+				Expect(nextRunAt.After(time.Now())).To(BeTrue())
+				Expect(nextRunAt.Hour()).To(Equal(10))
+				Expect(nextRunAt.Minute()).To(Equal(0))
+
+				id, err := uuid.FromString(schedule["id"].(string))
+				Expect(err).NotTo(HaveOccurred())
+				dbSchedule := &model.Schedule{ID: id}
+				err = app.DB.Select(&dbSchedule)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(dbSchedule.NextRunAt).To(Equal(nextRunAt.UnixNano()))
+			})
+
+			It("should return 201 and default the timezone to UTC", func() {
+				payload := GetSchedulePayload()
+				delete(payload, "timezone")
+				pl, _ := json.Marshal(payload)
+				status, body := Post(app, baseRoute, string(pl), "success@test.com")
+				Expect(status).To(Equal(http.StatusCreated))
+
+				var schedule map[string]interface{}
+				err := json.Unmarshal([]byte(body), &schedule)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(schedule["timezone"]).To(Equal("UTC"))
+			})
+		})
+
+		Describe("Unsucesfully", func() {
+			It("should return 401 if no authenticated user", func() {
+				status, _ := Post(app, baseRoute, "", "")
+				Expect(status).To(Equal(http.StatusUnauthorized))
+			})
+
+			It("should return 422 if template is not specified", func() {
+				pl, _ := json.Marshal(GetSchedulePayload())
+				status, _ := Post(app, baseRouteWithoutTemplate, string(pl), "test@test.com")
+				Expect(status).To(Equal(http.StatusUnprocessableEntity))
+			})
+
+			It("should return 422 if template with given name does not exist", func() {
+				pl, _ := json.Marshal(GetSchedulePayload())
+				status, _ := Post(app, fmt.Sprintf("%s?template=not-a-template", baseRouteWithoutTemplate), string(pl), "test@test.com")
+				Expect(status).To(Equal(http.StatusUnprocessableEntity))
+			})
+
+			It("should return 422 if invalid cron expression", func() {
+				payload := GetSchedulePayload(map[string]interface{}{"cronExpression": "every day"})
+				pl, _ := json.Marshal(payload)
+				status, body := Post(app, baseRoute, string(pl), "test@test.com")
+				Expect(status).To(Equal(http.StatusUnprocessableEntity))
+
+				var response map[string]interface{}
+				err := json.Unmarshal([]byte(body), &response)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(response["reason"]).To(Equal("invalid cronExpression"))
+			})
+
+			It("should return 422 if invalid timezone", func() {
+				payload := GetSchedulePayload(map[string]interface{}{"timezone": "Mars/Olympus"})
+				pl, _ := json.Marshal(payload)
+				status, body := Post(app, baseRoute, string(pl), "test@test.com")
+				Expect(status).To(Equal(http.StatusUnprocessableEntity))
+
+				var response map[string]interface{}
+				err := json.Unmarshal([]byte(body), &response)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(response["reason"]).To(Equal("invalid timezone"))
+			})
+
+			It("should return 422 if endsAt is in the past", func() {
+				payload := GetSchedulePayload(map[string]interface{}{"endsAt": time.Now().Add(-time.Hour).UnixNano()})
+				pl, _ := json.Marshal(payload)
+				status, _ := Post(app, baseRoute, string(pl), "test@test.com")
+				Expect(status).To(Equal(http.StatusUnprocessableEntity))
+			})
+
+			It("should return 422 if the schedule has no future occurrences", func() {
+				payload := GetSchedulePayload(map[string]interface{}{
+					"cronExpression": "0 10 1 1 *",
+					"endsAt":         time.Now().Add(time.Minute).UnixNano(),
+				})
+				pl, _ := json.Marshal(payload)
+				status, _ := Post(app, baseRoute, string(pl), "test@test.com")
+				Expect(status).To(Equal(http.StatusUnprocessableEntity))
+			})
+
+			It("should return 422 if both csvPath and filters are provided", func() {
+				payload := GetSchedulePayload(map[string]interface{}{"csvPath": "bucket/somecsv"})
+				pl, _ := json.Marshal(payload)
+				status, _ := Post(app, baseRoute, string(pl), "test@test.com")
+				Expect(status).To(Equal(http.StatusUnprocessableEntity))
+			})
+		})
+	})
+
+	Describe("Get /apps/:id/schedules/:sid", func() {
+		It("should return 200 and the requested schedule", func() {
+			existingSchedule := CreateTestSchedule(app.DB, existingApp.ID, existingTemplate.Name)
+			status, body := Get(app, fmt.Sprintf("%s/%s", baseRouteWithoutTemplate, existingSchedule.ID), "test@test.com")
+			Expect(status).To(Equal(http.StatusOK))
+
+			var schedule map[string]interface{}
+			err := json.Unmarshal([]byte(body), &schedule)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(schedule["id"]).To(Equal(existingSchedule.ID.String()))
+			Expect(schedule["nextRunAt"]).To(Equal(float64(existingSchedule.NextRunAt)))
+		})
+
+		It("should return 404 if the schedule does not exist", func() {
+			status, _ := Get(app, fmt.Sprintf("%s/%s", baseRouteWithoutTemplate, uuid.NewV4()), "test@test.com")
+			Expect(status).To(Equal(http.StatusNotFound))
+		})
+	})
+
+	Describe("Put /apps/:id/schedules/:sid/pause and resume", func() {
+		It("should pause the schedule", func() {
+			existingSchedule := CreateTestSchedule(app.DB, existingApp.ID, existingTemplate.Name)
+			status, body := Put(app, fmt.Sprintf("%s/%s/pause", baseRouteWithoutTemplate, existingSchedule.ID), "", "test@test.com")
+			Expect(status).To(Equal(http.StatusOK))
+
+			var schedule map[string]interface{}
+			err := json.Unmarshal([]byte(body), &schedule)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(schedule["paused"]).To(BeTrue())
+
+			dbSchedule := &model.Schedule{ID: existingSchedule.ID}
+			err = app.DB.Select(&dbSchedule)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(dbSchedule.Paused).To(BeTrue())
+		})
+
+		It("should resume the schedule skipping the occurrences missed while paused", func() {
+			existingSchedule := CreateTestSchedule(app.DB, existingApp.ID, existingTemplate.Name, map[string]interface{}{
+				"paused":    true,
+				"nextRunAt": time.Now().Add(-48 * time.Hour).UnixNano(),
+			})
+			status, body := Put(app, fmt.Sprintf("%s/%s/resume", baseRouteWithoutTemplate, existingSchedule.ID), "", "test@test.com")
+			Expect(status).To(Equal(http.StatusOK))
+
+			var schedule map[string]interface{}
+			err := json.Unmarshal([]byte(body), &schedule)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(schedule["paused"]).To(BeFalse())
+			Expect(int64(schedule["nextRunAt"].(float64))).To(BeNumerically(">", time.Now().UnixNano()))
+		})
+
+		It("should return 404 if the schedule does not exist", func() {
+			status, _ := Put(app, fmt.Sprintf("%s/%s/pause", baseRouteWithoutTemplate, uuid.NewV4()), "", "test@test.com")
+			Expect(status).To(Equal(http.StatusNotFound))
+		})
+	})
+
+	Describe("Delete /apps/:id/schedules/:sid", func() {
+		It("should return 204 and delete the schedule", func() {
+			existingSchedule := CreateTestSchedule(app.DB, existingApp.ID, existingTemplate.Name)
+			status, _ := Delete(app, fmt.Sprintf("%s/%s", baseRouteWithoutTemplate, existingSchedule.ID), "test@test.com")
+			Expect(status).To(Equal(http.StatusNoContent))
+
+			dbSchedule := &model.Schedule{ID: existingSchedule.ID}
+			err := app.DB.Select(&dbSchedule)
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("should return 404 if the schedule does not exist", func() {
+			status, _ := Delete(app, fmt.Sprintf("%s/%s", baseRouteWithoutTemplate, uuid.NewV4()), "test@test.com")
+			Expect(status).To(Equal(http.StatusNotFound))
+		})
+	})
+})
@@ -0,0 +1,229 @@
+/*
+ * Copyright (c) 2026 TFG Co <backend@tfgco.com>
+ * Author: TFG Co <backend@tfgco.com>
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy of
+ * this software and associated documentation files (the "Software"), to deal in
+ * the Software without restriction, including without limitation the rights to
+ * use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+ * the Software, and to permit persons to whom the Software is furnished to do so,
+ * subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+ * FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+ * COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+ * IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+ * CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+ */
+package api
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	uuid "github.com/satori/go.uuid"
+	"github.com/topfreegames/marathon/log"
+	"github.com/topfreegames/marathon/model"
+	"github.com/topfreegames/marathon/worker"
+	"github.com/uber-go/zap"
+	pg "gopkg.in/pg.v5"
+)
+
+// TemplateImport is the response of the template import route, with the locales that were, or
+// would be in a dry run, created, updated and left unchanged
+type TemplateImport struct {
+	Name      string                                   `json:"name"`
+	DryRun    bool                                     `json:"dryRun"`
+	Created   []string                                 `json:"created"`
+	Updated   map[string]map[string]*model.AuditChange `json:"updated"`
+	Unchanged []string                                 `json:"unchanged"`
+	Templates []*model.Template                        `json:"templates,omitempty"`
+}
+
+// decodeTemplateDocument decodes the document of the request body, as a csv if it is its content type
+func decodeTemplateDocument(c echo.Context, document *model.TemplateDocument) error {
+	if !strings.HasPrefix(c.Request().Header.Get(echo.HeaderContentType), "text/csv") {
+		return decodeAndValidate(c, document)
+	}
+	defer c.Request().Body.Close()
+	decoded, err := model.ReadTemplateDocumentCSV(c.Request().Body)
+	if err != nil {
+		return err
+	}
+	*document = *decoded
+	return document.Validate(c)
+}
+
+// ImportTemplatesHandler is the method called when a post to /apps/:aid/templates/import is called,
+// it creates or updates a template for each locale of the document
+func (a *Application) ImportTemplatesHandler(c echo.Context) error {
+	l := a.Logger.With(
+		zap.String("source", "templateDocumentHandler"),
+		zap.String("operation", "importTemplates"),
+		zap.String("appId", c.Param("aid")),
+	)
+	aid, err := uuid.FromString(c.Param("aid"))
+	if err != nil {
+		return c.JSON(http.StatusUnprocessableEntity, &Error{Reason: err.Error()})
+	}
+	document := &model.TemplateDocument{}
+	err = WithSegment("decodeAndValidate", c, func() error {
+		err := decodeTemplateDocument(c, document)
+		if err != nil {
+			return err
+		}
+		for _, t := range document.Templates() {
+			if err := worker.ValidateTemplate(*t, t.Services(), nil, nil); err != nil {
+				return fmt.Errorf("locale %s: %s", t.Locale, err.Error())
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return c.JSON(http.StatusUnprocessableEntity, &Error{Reason: err.Error(), Value: document})
+	}
+
+	email := c.Get("user-email").(string)
+	result := &TemplateImport{
+		Name:      document.Name,
+		DryRun:    c.QueryParam("dryRun") == "true",
+		Created:   []string{},
+		Updated:   map[string]map[string]*model.AuditChange{},
+		Unchanged: []string{},
+	}
+	err = WithSegment("db-upsert", c, func() error {
+		return withTransaction(a.DB, func(tx *pg.Tx) error {
+			existing := []model.Template{}
+			err := tx.Model(&existing).Where("app_id = ? AND name = ?", aid, document.Name).Select()
+			if err != nil {
+				return err
+			}
+			byLocale := map[string]*model.Template{}
+			for i := range existing {
+				byLocale[existing[i].Locale] = &existing[i]
+			}
+			for _, t := range document.Templates() {
+				current := byLocale[t.Locale]
+				if current == nil {
+					result.Created = append(result.Created, t.Locale)
+					if result.DryRun {
+						continue
+					}
+					t.ID = uuid.NewV4()
+					t.AppID = aid
+					t.CreatedBy = email
+					t.Version = 1
+					t.CreatedAt = time.Now().UnixNano()
+					t.UpdatedAt = t.CreatedAt
+					if err := tx.Insert(t); err != nil {
+						return err
+					}
+					if err := tx.Insert(model.NewTemplateVersion(t, email)); err != nil {
+						return err
+					}
+					result.Templates = append(result.Templates, t)
+					continue
+				}
+				diff := model.NewTemplateVersion(current, "").Diff(model.NewTemplateVersion(t, ""))
+				if len(diff) == 0 {
+					result.Unchanged = append(result.Unchanged, t.Locale)
+					continue
+				}
+				result.Updated[t.Locale] = diff
+				if result.DryRun {
+					continue
+				}
+				_, err := tx.QueryOne(t,
+					"UPDATE templates SET category = ?, service = ?, defaults = ?, body = ?, push_options = ?, updated_at = ? WHERE id = ? RETURNING *",
+					t.Category, t.Service, t.Defaults, t.Body, t.PushOptions, time.Now().UnixNano(), current.ID,
+				)
+				if err != nil {
+					return err
+				}
+				if err := a.createTemplateVersion(tx, t, email); err != nil {
+					return err
+				}
+				result.Templates = append(result.Templates, t)
+			}
+			return nil
+		})
+	})
+	if err != nil {
+		if strings.Contains(err.Error(), "duplicate key") {
+			return c.JSON(http.StatusConflict, &Error{Reason: err.Error(), Value: document})
+		}
+		if strings.Contains(err.Error(), "violates foreign key constraint") {
+			return c.JSON(http.StatusUnprocessableEntity, &Error{Reason: err.Error(), Value: document})
+		}
+		log.E(l, "Failed to import templates.", func(cm log.CM) {
+			cm.Write(zap.Error(err))
+		})
+		return c.JSON(http.StatusInternalServerError, &Error{Reason: err.Error(), Value: document})
+	}
+	if !result.DryRun {
+		log.I(l, "Imported templates successfully.", func(cm log.CM) {
+			cm.Write(
+				zap.String("name", result.Name),
+				zap.Int("created", len(result.Created)),
+				zap.Int("updated", len(result.Updated)),
+			)
+		})
+	}
+	return c.JSON(http.StatusOK, result)
+}
+
+// ExportTemplatesHandler is the method called when a get to /apps/:aid/templates/export is called,
+// it returns every locale of a template name in the format of the import route
+func (a *Application) ExportTemplatesHandler(c echo.Context) error {
+	l := a.Logger.With(
+		zap.String("source", "templateDocumentHandler"),
+		zap.String("operation", "exportTemplates"),
+		zap.String("appId", c.Param("aid")),
+	)
+	aid, err := uuid.FromString(c.Param("aid"))
+	if err != nil {
+		return c.JSON(http.StatusUnprocessableEntity, &Error{Reason: err.Error()})
+	}
+	name := c.QueryParam("name")
+	if name == "" {
+		return c.JSON(http.StatusUnprocessableEntity, &Error{Reason: model.InvalidField("name").Error()})
+	}
+	format := c.QueryParam("format")
+	if format != "" && format != "json" && format != "csv" {
+		return c.JSON(http.StatusUnprocessableEntity, &Error{Reason: model.InvalidField("format").Error()})
+	}
+	templates := []model.Template{}
+	err = WithSegment("db-select", c, func() error {
+		return a.DB.Model(&templates).Where("app_id = ? AND name = ?", aid, name).Select()
+	})
+	if err != nil {
+		log.E(l, "Failed to export templates.", func(cm log.CM) {
+			cm.Write(zap.Error(err))
+		})
+		return c.JSON(http.StatusInternalServerError, &Error{Reason: err.Error()})
+	}
+	if len(templates) == 0 {
+		return c.JSON(http.StatusNotFound, map[string]string{})
+	}
+	document := model.NewTemplateDocument(templates)
+	if format != "csv" {
+		return c.JSON(http.StatusOK, document)
+	}
+	var csv bytes.Buffer
+	if err := document.WriteCSV(&csv); err != nil {
+		log.E(l, "Failed to write templates csv.", func(cm log.CM) {
+			cm.Write(zap.Error(err))
+		})
+		return c.JSON(http.StatusInternalServerError, &Error{Reason: err.Error()})
+	}
+	c.Response().Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", name+".csv"))
+	return c.Blob(http.StatusOK, "text/csv", csv.Bytes())
+}
@@ -0,0 +1,310 @@
+/*
+ * Copyright (c) 2026 TFG Co <backend@tfgco.com>
+ * Author: TFG Co <backend@tfgco.com>
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy of
+ * this software and associated documentation files (the "Software"), to deal in
+ * the Software without restriction, including without limitation the rights to
+ * use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+ * the Software, and to permit persons to whom the Software is furnished to do so,
+ * subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+ * FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+ * COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+ * IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+ * CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+ */
+package api_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/topfreegames/marathon/model"
+	. "github.com/topfreegames/marathon/testing"
+	"github.com/uber-go/zap"
+)
+
+var _ = Describe("Template Document Handler", func() {
+	logger := zap.New(
+		zap.NewJSONEncoder(zap.NoTime()), // drop timestamps in tests
+		zap.FatalLevel,
+	)
+	app := GetDefaultTestApp(logger)
+	var existingApp *model.App
+	var existingTemplate *model.Template
+	var importRoute string
+	var exportRoute string
+
+	csvHeaders := map[string]string{"x-forwarded-email": "test@test.com", "Content-Type": "text/csv"}
+
+	BeforeEach(func() {
+		app.DB.Exec("DELETE FROM apps;")
+		app.DB.Exec("DELETE FROM templates;")
+		app.DB.Exec("DELETE FROM users;")
+		CreateTestUser(app.DB, map[string]interface{}{"email": "test@test.com", "isAdmin": true})
+		existingApp = CreateTestApp(app.DB)
+		existingTemplate = CreateTestTemplate(app.DB, existingApp.ID, map[string]interface{}{
+			"name":     "reward",
+			"locale":   "en",
+			"defaults": map[string]interface{}{"reward": "gold"},
+			"body":     map[string]interface{}{"alert": "You won {{reward}}"},
+		})
+		importRoute = fmt.Sprintf("/apps/%s/templates/import", existingApp.ID)
+		exportRoute = fmt.Sprintf("/apps/%s/templates/export?name=reward", existingApp.ID)
+	})
+
+	Describe("Post /apps/:aid/templates/import", func() {
+		document := func() map[string]interface{} {
+			return map[string]interface{}{
+				"name": "reward",
+				"locales": []map[string]interface{}{
+					{
+						"locale":   "en",
+						"defaults": map[string]interface{}{"reward": "gold"},
+						"body":     map[string]interface{}{"alert": "You won {{reward}}!"},
+					},
+					{
+						"locale":   "pt",
+						"defaults": map[string]interface{}{"reward": "ouro"},
+						"body":     map[string]interface{}{"alert": "Você ganhou {{reward}}!"},
+					},
+				},
+			}
+		}
+
+		It("should return 200 and the changes without changing the templates in a dry run", func() {
+			pl, _ := json.Marshal(document())
+			status, body := Post(app, fmt.Sprintf("%s?dryRun=true", importRoute), string(pl), "test@test.com")
+			Expect(status).To(Equal(http.StatusOK))
+
+			var result map[string]interface{}
+			err := json.Unmarshal([]byte(body), &result)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result["dryRun"]).To(BeTrue())
+			Expect(result["created"]).To(Equal([]interface{}{"pt"}))
+			Expect(result["updated"]).To(Equal(map[string]interface{}{
+				"en": map[string]interface{}{
+					"body.alert": map[string]interface{}{"before": "You won {{reward}}", "after": "You won {{reward}}!"},
+				},
+			}))
+			Expect(result["unchanged"]).To(BeEmpty())
+
+			templates := []model.Template{}
+			err = app.DB.Model(&templates).Where("app_id = ?", existingApp.ID).Select()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(templates).To(HaveLen(1))
+			Expect(templates[0].Body["alert"]).To(Equal("You won {{reward}}"))
+			Expect(templates[0].Version).To(Equal(1))
+		})
+
+		It("should return 200 and create and update the locales of the document", func() {
+			pl, _ := json.Marshal(document())
+			status, body := Post(app, importRoute, string(pl), "test@test.com")
+			Expect(status).To(Equal(http.StatusOK))
+
+			var result map[string]interface{}
+			err := json.Unmarshal([]byte(body), &result)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result["dryRun"]).To(BeFalse())
+			Expect(result["created"]).To(Equal([]interface{}{"pt"}))
+			Expect(result["updated"]).To(HaveKey("en"))
+			Expect(result["templates"]).To(HaveLen(2))
+
+			updated := &model.Template{ID: existingTemplate.ID}
+			err = app.DB.Select(updated)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(updated.Body["alert"]).To(Equal("You won {{reward}}!"))
+			Expect(updated.Version).To(Equal(2))
+
+			created := &model.Template{}
+			err = app.DB.Model(created).Where("app_id = ? AND name = ? AND locale = ?", existingApp.ID, "reward", "pt").Select()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(created.Body["alert"]).To(Equal("Você ganhou {{reward}}!"))
+			Expect(created.Version).To(Equal(1))
+			Expect(created.CreatedBy).To(Equal("test@test.com"))
+
+			versions := []model.TemplateVersion{}
+			err = app.DB.Model(&versions).Where("app_id = ?", existingApp.ID).Select()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(versions).To(HaveLen(3))
+
+			status, body = Post(app, importRoute, string(pl), "test@test.com")
+			Expect(status).To(Equal(http.StatusOK))
+			err = json.Unmarshal([]byte(body), &result)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result["created"]).To(BeEmpty())
+			Expect(result["updated"]).To(BeEmpty())
+			Expect(result["unchanged"]).To(ConsistOf("en", "pt"))
+		})
+
+		It("should keep the locales that are not in the document", func() {
+			CreateTestTemplate(app.DB, existingApp.ID, map[string]interface{}{"name": "reward", "locale": "fr"})
+			pl, _ := json.Marshal(document())
+			status, _ := Post(app, importRoute, string(pl), "test@test.com")
+			Expect(status).To(Equal(http.StatusOK))
+
+			count, err := app.DB.Model(&model.Template{}).Where("app_id = ? AND name = ?", existingApp.ID, "reward").Count()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(count).To(Equal(3))
+		})
+
+		It("should return 200 and import a csv document", func() {
+			csv := strings.Join([]string{
+				"name,locale,category,service,body.alert.title,body.badge:json,defaults.reward",
+				"reward,en,,apns,You won {{reward}}!,1,gold",
+				"reward,pt,,apns,Você ganhou {{reward}}!,1,ouro",
+			}, "\n")
+			status, body := RequestWithHeaders(app, "POST", importRoute, csv, csvHeaders)
+			Expect(status).To(Equal(http.StatusOK), body)
+
+			created := &model.Template{}
+			err := app.DB.Model(created).Where("app_id = ? AND name = ? AND locale = ?", existingApp.ID, "reward", "pt").Select()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(created.Service).To(Equal("apns"))
+			Expect(created.Body).To(Equal(map[string]interface{}{
+				"alert": map[string]interface{}{"title": "Você ganhou {{reward}}!"},
+				"badge": float64(1),
+			}))
+			Expect(created.Defaults).To(Equal(map[string]interface{}{"reward": "ouro"}))
+		})
+
+		It("should return 422 if a locale is duplicated", func() {
+			payload := document()
+			locales := payload["locales"].([]map[string]interface{})
+			payload["locales"] = append(locales, locales[0])
+			pl, _ := json.Marshal(payload)
+			status, body := Post(app, importRoute, string(pl), "test@test.com")
+			Expect(status).To(Equal(http.StatusUnprocessableEntity))
+
+			var response map[string]interface{}
+			err := json.Unmarshal([]byte(body), &response)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(response["reason"]).To(Equal("invalid locales: en is duplicated"))
+		})
+
+		It("should return 422 and import nothing if a locale is invalid", func() {
+			payload := document()
+			payload["locales"].([]map[string]interface{})[1]["body"] = map[string]interface{}{"alert": "{{#if vip}}"}
+			pl, _ := json.Marshal(payload)
+			status, body := Post(app, importRoute, string(pl), "test@test.com")
+			Expect(status).To(Equal(http.StatusUnprocessableEntity))
+
+			var response map[string]interface{}
+			err := json.Unmarshal([]byte(body), &response)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(response["reason"]).To(Equal("locale pt: invalid template: if vip is not closed"))
+
+			count, err := app.DB.Model(&model.Template{}).Where("app_id = ?", existingApp.ID).Count()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(count).To(Equal(1))
+		})
+
+		It("should return 422 if the csv has an unknown column", func() {
+			csv := "name,locale,title\nreward,en,hello"
+			status, body := RequestWithHeaders(app, "POST", importRoute, csv, csvHeaders)
+			Expect(status).To(Equal(http.StatusUnprocessableEntity))
+
+			var response map[string]interface{}
+			err := json.Unmarshal([]byte(body), &response)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(response["reason"]).To(Equal("invalid csv: unknown column title"))
+		})
+
+		It("should return 422 if the csv rows have different names", func() {
+			csv := "name,locale,body.alert\nreward,en,hello\nbonus,pt,olá"
+			status, body := RequestWithHeaders(app, "POST", importRoute, csv, csvHeaders)
+			Expect(status).To(Equal(http.StatusUnprocessableEntity))
+
+			var response map[string]interface{}
+			err := json.Unmarshal([]byte(body), &response)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(response["reason"]).To(Equal("invalid csv: every row must have the same name"))
+		})
+	})
+
+	Describe("Get /apps/:aid/templates/export", func() {
+		BeforeEach(func() {
+			CreateTestTemplate(app.DB, existingApp.ID, map[string]interface{}{
+				"name":     "reward",
+				"locale":   "pt",
+				"service":  "apns",
+				"defaults": map[string]interface{}{"reward": "ouro"},
+				"body": map[string]interface{}{
+					"alert": map[string]interface{}{"title": "Você ganhou, {{reward}}", "body": ""},
+					"badge": 1,
+				},
+			})
+		})
+
+		It("should return 200 and every locale of the template name", func() {
+			status, body := Get(app, exportRoute, "test@test.com")
+			Expect(status).To(Equal(http.StatusOK))
+
+			var document map[string]interface{}
+			err := json.Unmarshal([]byte(body), &document)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(document["name"]).To(Equal("reward"))
+			locales := document["locales"].([]interface{})
+			Expect(locales).To(HaveLen(2))
+			Expect(locales[0].(map[string]interface{})["locale"]).To(Equal("en"))
+			Expect(locales[0].(map[string]interface{})["body"]).To(Equal(map[string]interface{}{"alert": "You won {{reward}}"}))
+			Expect(locales[1].(map[string]interface{})["locale"]).To(Equal("pt"))
+		})
+
+		It("should return 200 and a csv with a row by locale", func() {
+			status, body := Get(app, fmt.Sprintf("%s&format=csv", exportRoute), "test@test.com")
+			Expect(status).To(Equal(http.StatusOK))
+			Expect(strings.Split(strings.TrimSpace(body), "\n")).To(Equal([]string{
+				"name,locale,category,service,body.alert,body.alert.body:json,body.alert.title,body.badge:json,defaults.reward",
+				"reward,en,,,You won {{reward}},,,,gold",
+				`reward,pt,,apns,,"""""","Você ganhou, {{reward}}",1,ouro`,
+			}))
+		})
+
+		It("should import its own export without changes", func() {
+			for _, format := range []string{"json", "csv"} {
+				status, exported := Get(app, fmt.Sprintf("%s&format=%s", exportRoute, format), "test@test.com")
+				Expect(status).To(Equal(http.StatusOK))
+
+				headers := map[string]string{"x-forwarded-email": "test@test.com"}
+				if format == "csv" {
+					headers = csvHeaders
+				}
+				status, body := RequestWithHeaders(app, "POST", importRoute, exported, headers)
+				Expect(status).To(Equal(http.StatusOK), body)
+
+				var result map[string]interface{}
+				err := json.Unmarshal([]byte(body), &result)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(result["created"]).To(BeEmpty())
+				Expect(result["updated"]).To(BeEmpty())
+				Expect(result["unchanged"]).To(ConsistOf("en", "pt"))
+			}
+		})
+
+		It("should return 404 if there are no templates with the name", func() {
+			status, _ := Get(app, fmt.Sprintf("/apps/%s/templates/export?name=other", existingApp.ID), "test@test.com")
+			Expect(status).To(Equal(http.StatusNotFound))
+		})
+
+		It("should return 422 if the format is invalid", func() {
+			status, body := Get(app, fmt.Sprintf("%s&format=xml", exportRoute), "test@test.com")
+			Expect(status).To(Equal(http.StatusUnprocessableEntity))
+
+			var response map[string]interface{}
+			err := json.Unmarshal([]byte(body), &response)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(response["reason"]).To(Equal("invalid format"))
+		})
+	})
+})
@@ -23,16 +23,20 @@
 package api
 
 import (
+	"encoding/json"
+	"fmt"
 	"net/http"
 	"strings"
 	"time"
 
+	pg "gopkg.in/pg.v5"
 	"gopkg.in/pg.v5/types"
 
 	"github.com/labstack/echo/v4"
 	"github.com/satori/go.uuid"
 	"github.com/topfreegames/marathon/log"
 	"github.com/topfreegames/marathon/model"
+	"github.com/topfreegames/marathon/worker"
 	"github.com/uber-go/zap"
 )
 
@@ -78,20 +82,38 @@ func (a *Application) PostTemplateHandler(c echo.Context) error {
 	if c.QueryParam("multiple") == "true" {
 		var templates []*model.Template
 		err = WithSegment("decodeAndValidate", c, func() error {
-			return decodeAndValidateTemplatesArray(c, &templates)
+			err := decodeAndValidateTemplatesArray(c, &templates)
+			if err != nil {
+				return err
+			}
+			for _, t := range templates {
+				if err := worker.ValidateTemplate(*t, t.Services(), nil, nil); err != nil {
+					return err
+				}
+			}
+			return nil
 		})
 		if err != nil {
 			return c.JSON(http.StatusUnprocessableEntity, &Error{Reason: err.Error()})
 		}
-		for _, t := range templates {
+		versions := make([]*model.TemplateVersion, len(templates))
+		for i, t := range templates {
 			t.ID = uuid.NewV4()
 			t.AppID = aid
 			t.CreatedBy = email
+			t.Version = 1
 			t.CreatedAt = time.Now().UnixNano()
 			t.UpdatedAt = time.Now().UnixNano()
+			versions[i] = model.NewTemplateVersion(t, email)
 		}
 		err = WithSegment("db-insert", c, func() error {
-			return a.DB.Insert(&templates)
+			return withTransaction(a.DB, func(tx *pg.Tx) error {
+				err := tx.Insert(&templates)
+				if err != nil {
+					return err
+				}
+				return tx.Insert(&versions)
+			})
 		})
 		if err != nil {
 			if strings.Contains(err.Error(), "duplicate key") {
@@ -115,13 +137,24 @@ func (a *Application) PostTemplateHandler(c echo.Context) error {
 		UpdatedAt: time.Now().UnixNano(),
 	}
 	err = WithSegment("decodeAndValidate", c, func() error {
-		return decodeAndValidate(c, template)
+		err := decodeAndValidate(c, template)
+		if err != nil {
+			return err
+		}
+		return worker.ValidateTemplate(*template, template.Services(), nil, nil)
 	})
 	if err != nil {
 		return c.JSON(http.StatusUnprocessableEntity, &Error{Reason: err.Error(), Value: template})
 	}
+	template.Version = 1
 	err = WithSegment("db-insert", c, func() error {
-		return a.DB.Insert(&template)
+		return withTransaction(a.DB, func(tx *pg.Tx) error {
+			err := tx.Insert(template)
+			if err != nil {
+				return err
+			}
+			return tx.Insert(model.NewTemplateVersion(template, email))
+		})
 	})
 	if err != nil {
 		if strings.Contains(err.Error(), "duplicate key") {
@@ -194,7 +227,11 @@ func (a *Application) PutTemplateHandler(c echo.Context) error {
 		UpdatedAt: time.Now().UnixNano(),
 	}
 	err = WithSegment("decodeAndValidate", c, func() error {
-		return decodeAndValidate(c, template)
+		err := decodeAndValidate(c, template)
+		if err != nil {
+			return err
+		}
+		return worker.ValidateTemplate(*template, template.Services(), nil, nil)
 	})
 	if err != nil {
 		return c.JSON(http.StatusUnprocessableEntity, &Error{Reason: err.Error(), Value: template})
@@ -203,12 +240,17 @@ func (a *Application) PutTemplateHandler(c echo.Context) error {
 	template.AppID = aid
 	var values *types.Result
 	err = WithSegment("db-update", c, func() error {
-		updating := a.DB.Model(&template).Column("name").Column("locale").Column("body").Column("updated_at")
-		if template.Defaults != nil && len(template.Defaults) > 0 {
-			updating = updating.Column("defaults")
-		}
-		values, err = updating.Returning("*").Update()
-		return err
+		return withTransaction(a.DB, func(tx *pg.Tx) error {
+			updating := tx.Model(&template).Column("name").Column("locale").Column("category").Column("service").Column("body").Column("push_options").Column("updated_at")
+			if template.Defaults != nil && len(template.Defaults) > 0 {
+				updating = updating.Column("defaults")
+			}
+			values, err = updating.Returning("*").Update()
+			if err != nil || values.RowsAffected() == 0 {
+				return err
+			}
+			return a.createTemplateVersion(tx, template, email)
+		})
 	})
 	if err != nil {
 		if strings.Contains(err.Error(), "duplicate key") {
@@ -264,3 +306,81 @@ func (a *Application) DeleteTemplateHandler(c echo.Context) error {
 	})
 	return c.JSON(http.StatusNoContent, "")
 }
+
+// TemplatePreviewResponse is the response of the template preview route
+type TemplatePreviewResponse struct {
+	Messages map[string]json.RawMessage `json:"messages"`
+	Sent     map[string]int             `json:"sent,omitempty"`
+}
+
+// PreviewTemplateHandler is the method called when a post to /apps/:aid/templates/:tid/preview is called
+func (a *Application) PreviewTemplateHandler(c echo.Context) error {
+	l := a.Logger.With(
+		zap.String("source", "templateHandler"),
+		zap.String("operation", "previewTemplate"),
+		zap.String("appId", c.Param("aid")),
+		zap.String("templateId", c.Param("tid")),
+	)
+	aid, err := uuid.FromString(c.Param("aid"))
+	if err != nil {
+		return c.JSON(http.StatusUnprocessableEntity, &Error{Reason: err.Error()})
+	}
+	tid, err := uuid.FromString(c.Param("tid"))
+	if err != nil {
+		return c.JSON(http.StatusUnprocessableEntity, &Error{Reason: err.Error()})
+	}
+	template := &model.Template{ID: tid, AppID: aid}
+	err = WithSegment("db-select", c, func() error {
+		return a.DB.Model(&template).Column("template.*", "App").Where("template.id = ?", template.ID).Where("template.app_id = ?", aid).Select()
+	})
+	if err != nil {
+		if err.Error() == RecordNotFoundString {
+			return c.JSON(http.StatusNotFound, template)
+		}
+		log.E(l, "Failed to retrieve template.", func(cm log.CM) {
+			cm.Write(zap.Error(err))
+		})
+		return c.JSON(http.StatusInternalServerError, &Error{Reason: err.Error(), Value: template})
+	}
+
+	preview := &model.TemplatePreview{}
+	err = WithSegment("decodeAndValidate", c, func() error {
+		return decodeAndValidate(c, preview)
+	})
+	if err != nil {
+		return c.JSON(http.StatusUnprocessableEntity, &Error{Reason: err.Error(), Value: preview})
+	}
+
+	res := &TemplatePreviewResponse{Messages: map[string]json.RawMessage{}}
+	for _, service := range preview.Services() {
+		pushMetadata := worker.NewTemplatePreviewMetadata(template.Name, preview)
+		msg, err := worker.BuildPushMessage(service, "", *template, preview, pushMetadata)
+		if err != nil {
+			return c.JSON(http.StatusUnprocessableEntity, &Error{Reason: fmt.Sprintf("failed to render template: %s", err.Error()), Value: preview})
+		}
+		res.Messages[service] = json.RawMessage(msg)
+	}
+
+	if preview.IsTestSend() {
+		userEmail := c.Get("user-email").(string)
+		res.Sent = map[string]int{}
+		for _, service := range preview.Services() {
+			var sent int
+			err = WithSegment("send-preview", c, func() error {
+				sent, err = a.Worker.SendTemplatePreview(&template.App, service, *template, preview)
+				return err
+			})
+			res.Sent[service] = sent
+			if err != nil {
+				log.E(l, "Failed to send template preview.", func(cm log.CM) {
+					cm.Write(zap.String("service", service), zap.Error(err))
+				})
+				return c.JSON(http.StatusInternalServerError, &Error{Reason: err.Error(), Value: preview})
+			}
+		}
+		log.I(l, "Sent template preview successfully.", func(cm log.CM) {
+			cm.Write(zap.String("sentBy", userEmail), zap.Object("sent", res.Sent))
+		})
+	}
+	return c.JSON(http.StatusOK, res)
+}
@@ -32,6 +32,7 @@ import (
 	. "github.com/onsi/gomega"
 	uuid "github.com/satori/go.uuid"
 	"github.com/topfreegames/marathon/api"
+	"github.com/topfreegames/marathon/messages"
 	"github.com/topfreegames/marathon/model"
 	. "github.com/topfreegames/marathon/testing"
 	"github.com/uber-go/zap"
@@ -365,6 +366,19 @@ var _ = Describe("Template Handler", func() {
 				Expect(response["reason"]).To(Equal("invalid body"))
 			})
 
+			It("should return 422 if invalid category", func() {
+				payload := GetTemplatePayload()
+				payload["category"] = "Promo Pushes"
+				pl, _ := json.Marshal(payload)
+				status, body := Post(app, baseRoute, string(pl), "test@test.com")
+				Expect(status).To(Equal(http.StatusUnprocessableEntity))
+
+				var response map[string]interface{}
+				err := json.Unmarshal([]byte(body), &response)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(response["reason"]).To(Equal("invalid category"))
+			})
+
 			It("should return 422 if invalid name", func() {
 				payload := GetTemplatePayload()
 				payload["name"] = strings.Repeat("a", 256)
@@ -429,6 +443,72 @@ var _ = Describe("Template Handler", func() {
 				Expect(err).NotTo(HaveOccurred())
 				Expect(response["reason"]).To(ContainSubstring("cannot unmarshal string into Go struct"))
 			})
+
+			It("should return 422 if invalid template syntax", func() {
+				payload := GetTemplatePayload()
+				payload["body"] = map[string]interface{}{"alert": "{{#if vip}}hello"}
+				pl, _ := json.Marshal(payload)
+				status, body := Post(app, baseRoute, string(pl), "test@test.com")
+				Expect(status).To(Equal(http.StatusUnprocessableEntity))
+
+				var response map[string]interface{}
+				err := json.Unmarshal([]byte(body), &response)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(response["reason"]).To(Equal("invalid template: if vip is not closed"))
+			})
+
+			It("should return 422 if invalid push options", func() {
+				payload := GetTemplatePayload()
+				payload["pushOptions"] = map[string]interface{}{"mediaUrl": "http://cdn.example.com/reward.png"}
+				pl, _ := json.Marshal(payload)
+				status, body := Post(app, baseRoute, string(pl), "test@test.com")
+				Expect(status).To(Equal(http.StatusUnprocessableEntity))
+
+				var response map[string]interface{}
+				err := json.Unmarshal([]byte(body), &response)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(response["reason"]).To(Equal("invalid pushOptions.mediaUrl"))
+			})
+
+			It("should return 422 if invalid service", func() {
+				payload := GetTemplatePayload()
+				payload["service"] = "email"
+				pl, _ := json.Marshal(payload)
+				status, body := Post(app, baseRoute, string(pl), "test@test.com")
+				Expect(status).To(Equal(http.StatusUnprocessableEntity))
+
+				var response map[string]interface{}
+				err := json.Unmarshal([]byte(body), &response)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(response["reason"]).To(Equal("invalid service"))
+			})
+
+			It("should return 422 if an apns template is not a valid aps dictionary", func() {
+				payload := GetTemplatePayload()
+				payload["service"] = "apns"
+				payload["body"] = map[string]interface{}{"alert": "hello", "badge": "{{count}}"}
+				pl, _ := json.Marshal(payload)
+				status, body := Post(app, baseRoute, string(pl), "test@test.com")
+				Expect(status).To(Equal(http.StatusUnprocessableEntity))
+
+				var response map[string]interface{}
+				err := json.Unmarshal([]byte(body), &response)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(response["reason"]).To(Equal("invalid template: invalid aps badge"))
+			})
+
+			It("should return 422 if the template payload is over the limit", func() {
+				payload := GetTemplatePayload()
+				payload["body"] = map[string]interface{}{"alert": strings.Repeat("a", 5000)}
+				pl, _ := json.Marshal(payload)
+				status, body := Post(app, baseRoute, string(pl), "test@test.com")
+				Expect(status).To(Equal(http.StatusUnprocessableEntity))
+
+				var response map[string]interface{}
+				err := json.Unmarshal([]byte(body), &response)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(response["reason"]).To(HavePrefix("invalid template: apns payload has"))
+			})
 		})
 	})
 
@@ -819,4 +899,250 @@ var _ = Describe("Template Handler", func() {
 			})
 		})
 	})
+
+	Describe("Post /apps/:id/templates/:tid/preview", func() {
+		var existingTemplate *model.Template
+		var previewRoute string
+		var fakeKafka *FakeKafkaProducer
+
+		BeforeEach(func() {
+			existingTemplate = CreateTestTemplate(app.DB, existingApp.ID, map[string]interface{}{
+				"locale": "en",
+				"body": map[string]interface{}{
+					"alert": "{{user_name}} just liked your {{object_name}}!",
+				},
+				"defaults": map[string]interface{}{
+					"user_name":   "Someone",
+					"object_name": "village",
+				},
+			})
+			previewRoute = fmt.Sprintf("%s/%s/preview", baseRoute, existingTemplate.ID)
+			fakeKafka = NewFakeKafkaProducer()
+			app.Worker.Kafka = fakeKafka
+		})
+
+		Describe("Sucesfully", func() {
+			It("should return 200 and the messages of both services", func() {
+				pl, _ := json.Marshal(map[string]interface{}{
+					"context":   map[string]interface{}{"user_name": "Camila"},
+					"metadata":  map[string]interface{}{"meta": "data"},
+					"expiresAt": 1500000000000000000,
+				})
+				status, body := Post(app, previewRoute, string(pl), "test@test.com")
+				Expect(status).To(Equal(http.StatusOK))
+
+				var response map[string]map[string]interface{}
+				err := json.Unmarshal([]byte(body), &response)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(response).NotTo(HaveKey("sent"))
+				messages := response["messages"]
+				Expect(messages).To(HaveLen(2))
+
+				apns := messages["apns"].(map[string]interface{})
+				Expect(apns["push_expiry"]).To(BeEquivalentTo(1500000000))
+				payload := apns["Payload"].(map[string]interface{})
+				Expect(payload["aps"]).To(Equal(map[string]interface{}{"alert": "Camila just liked your village!"}))
+				Expect(payload["m"]).To(Equal(map[string]interface{}{"meta": "data"}))
+				Expect(payload["templateName"]).To(Equal(existingTemplate.Name))
+				Expect(apns["metadata"].(map[string]interface{})["pushType"]).To(Equal("test"))
+
+				gcm := messages["gcm"].(map[string]interface{})
+				Expect(gcm["time_to_live"]).To(BeEquivalentTo(1500000000))
+				Expect(gcm["data"]).To(Equal(map[string]interface{}{
+					"alert":        "Camila just liked your village!",
+					"templateName": existingTemplate.Name,
+					"m":            map[string]interface{}{"meta": "data"},
+				}))
+
+				Expect(fakeKafka.APNSMessages).To(BeEmpty())
+				Expect(fakeKafka.GCMMessages).To(BeEmpty())
+			})
+
+			It("should return 200 and the messages with the template push options", func() {
+				optionsTemplate := CreateTestTemplate(app.DB, existingApp.ID, map[string]interface{}{
+					"locale": "en",
+					"body":   map[string]interface{}{"alert": "hi"},
+					"pushOptions": &messages.PushOptions{
+						MediaURL:    "https://cdn.example.com/reward.png",
+						ThreadID:    "rewards",
+						Priority:    "high",
+						CollapseKey: "daily-reward",
+						ChannelID:   "rewards",
+					},
+				})
+				route := fmt.Sprintf("%s/%s/preview", baseRoute, optionsTemplate.ID)
+				status, body := Post(app, route, "{}", "test@test.com")
+				Expect(status).To(Equal(http.StatusOK))
+
+				var response map[string]map[string]interface{}
+				err := json.Unmarshal([]byte(body), &response)
+				Expect(err).NotTo(HaveOccurred())
+
+				apns := response["messages"]["apns"].(map[string]interface{})
+				Expect(apns["collapse_id"]).To(Equal("daily-reward"))
+				payload := apns["Payload"].(map[string]interface{})
+				Expect(payload["media-url"]).To(Equal("https://cdn.example.com/reward.png"))
+				Expect(payload["aps"]).To(Equal(map[string]interface{}{
+					"alert":           "hi",
+					"mutable-content": float64(1),
+					"thread-id":       "rewards",
+				}))
+
+				gcm := response["messages"]["gcm"].(map[string]interface{})
+				Expect(gcm["priority"]).To(Equal("high"))
+				Expect(gcm["collapse_key"]).To(Equal("daily-reward"))
+				Expect(gcm).NotTo(HaveKey("notification"))
+				Expect(gcm["data"]).To(Equal(map[string]interface{}{
+					"alert":              "hi",
+					"templateName":       optionsTemplate.Name,
+					"image":              "https://cdn.example.com/reward.png",
+					"android_channel_id": "rewards",
+				}))
+			})
+
+			It("should return 200 and only the message of the given service", func() {
+				pl, _ := json.Marshal(map[string]interface{}{"service": "gcm"})
+				status, body := Post(app, previewRoute, string(pl), "test@test.com")
+				Expect(status).To(Equal(http.StatusOK))
+
+				var response map[string]map[string]interface{}
+				err := json.Unmarshal([]byte(body), &response)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(response["messages"]).To(HaveLen(1))
+				gcm := response["messages"]["gcm"].(map[string]interface{})
+				Expect(gcm["data"].(map[string]interface{})["alert"]).To(Equal("Someone just liked your village!"))
+			})
+
+			It("should escape context values with quotes", func() {
+				pl, _ := json.Marshal(map[string]interface{}{
+					"service": "apns",
+					"context": map[string]interface{}{"user_name": "\"quoted\""},
+				})
+				status, body := Post(app, previewRoute, string(pl), "test@test.com")
+				Expect(status).To(Equal(http.StatusOK))
+
+				var response map[string]map[string]interface{}
+				err := json.Unmarshal([]byte(body), &response)
+				Expect(err).NotTo(HaveOccurred())
+				apns := response["messages"]["apns"].(map[string]interface{})
+				Expect(apns["Payload"].(map[string]interface{})["aps"]).To(Equal(map[string]interface{}{"alert": "\"quoted\" just liked your village!"}))
+			})
+
+			It("should send the message to the given tokens", func() {
+				pl, _ := json.Marshal(map[string]interface{}{
+					"service": "apns",
+					"tokens":  []string{"token1", "token2"},
+				})
+				status, body := Post(app, previewRoute, string(pl), "test@test.com")
+				Expect(status).To(Equal(http.StatusOK))
+
+				var response map[string]map[string]interface{}
+				err := json.Unmarshal([]byte(body), &response)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(response["sent"]).To(Equal(map[string]interface{}{"apns": float64(2)}))
+
+				Expect(fakeKafka.APNSMessages).To(HaveLen(2))
+				Expect(fakeKafka.GCMMessages).To(BeEmpty())
+				var msg map[string]interface{}
+				err = json.Unmarshal([]byte(fakeKafka.APNSMessages[1]), &msg)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(msg["DeviceToken"]).To(Equal("token2"))
+				Expect(msg["Payload"].(map[string]interface{})["aps"]).To(Equal(map[string]interface{}{"alert": "Someone just liked your village!"}))
+				Expect(msg["metadata"].(map[string]interface{})["pushType"]).To(Equal("test"))
+			})
+
+			It("should send the message to the tokens of the given users", func() {
+				pl, _ := json.Marshal(map[string]interface{}{
+					"userIds": []string{"9e558649-9c23-469d-a11c-59b05813e3d5", "57be9009-e616-42c6-9cfe-505508ede2d0"},
+				})
+				status, body := Post(app, previewRoute, string(pl), "test@test.com")
+				Expect(status).To(Equal(http.StatusOK))
+
+				var response map[string]map[string]interface{}
+				err := json.Unmarshal([]byte(body), &response)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(response["sent"]).To(Equal(map[string]interface{}{"apns": float64(2), "gcm": float64(1)}))
+				Expect(fakeKafka.APNSMessages).To(HaveLen(2))
+				Expect(fakeKafka.GCMMessages).To(HaveLen(1))
+
+				var msg map[string]interface{}
+				err = json.Unmarshal([]byte(fakeKafka.GCMMessages[0]), &msg)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(msg["to"]).To(Equal("1235"))
+				Expect(msg["metadata"].(map[string]interface{})["userId"]).To(Equal("57be9009-e616-42c6-9cfe-505508ede2d0"))
+			})
+		})
+
+		Describe("Unsucesfully", func() {
+			It("should return 401 if no authenticated user", func() {
+				status, _ := Post(app, previewRoute, "{}", "")
+				Expect(status).To(Equal(http.StatusUnauthorized))
+			})
+
+			It("should return 404 if the template does not exist", func() {
+				route := fmt.Sprintf("%s/%s/preview", baseRoute, uuid.NewV4())
+				status, _ := Post(app, route, "{}", "test@test.com")
+				Expect(status).To(Equal(http.StatusNotFound))
+			})
+
+			It("should return 404 if the template is from another app", func() {
+				anotherApp := CreateTestApp(app.DB)
+				route := fmt.Sprintf("/apps/%s/templates/%s/preview", anotherApp.ID, existingTemplate.ID)
+				status, _ := Post(app, route, "{}", "test@test.com")
+				Expect(status).To(Equal(http.StatusNotFound))
+			})
+
+			It("should return 422 if template id is not UUID", func() {
+				status, body := Post(app, fmt.Sprintf("%s/not-uuid/preview", baseRoute), "{}", "test@test.com")
+				Expect(status).To(Equal(http.StatusUnprocessableEntity))
+
+				var response map[string]interface{}
+				err := json.Unmarshal([]byte(body), &response)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(response["reason"]).To(ContainSubstring("uuid: incorrect UUID length: not-uuid"))
+			})
+
+			It("should return 422 if tokens are given without a service", func() {
+				pl, _ := json.Marshal(map[string]interface{}{
+					"tokens": []string{"token1"},
+				})
+				status, body := Post(app, previewRoute, string(pl), "test@test.com")
+				Expect(status).To(Equal(http.StatusUnprocessableEntity))
+
+				var response map[string]interface{}
+				err := json.Unmarshal([]byte(body), &response)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(response["reason"]).To(Equal("invalid service: tokens can only be sent to a single service"))
+				Expect(fakeKafka.APNSMessages).To(BeEmpty())
+			})
+
+			It("should return 422 if there are too many recipients", func() {
+				tokens := make([]string, model.MaxTemplatePreviewRecipients+1)
+				for i := range tokens {
+					tokens[i] = fmt.Sprintf("token%d", i)
+				}
+				pl, _ := json.Marshal(map[string]interface{}{
+					"service": "gcm",
+					"tokens":  tokens,
+				})
+				status, _ := Post(app, previewRoute, string(pl), "test@test.com")
+				Expect(status).To(Equal(http.StatusUnprocessableEntity))
+				Expect(fakeKafka.GCMMessages).To(BeEmpty())
+			})
+
+			It("should return 422 if the template is not valid", func() {
+				invalidTemplate := CreateTestTemplate(app.DB, existingApp.ID, map[string]interface{}{
+					"body": map[string]interface{}{"alert": "{{#if vip}}unclosed"},
+				})
+				route := fmt.Sprintf("%s/%s/preview", baseRoute, invalidTemplate.ID)
+				status, body := Post(app, route, "{}", "test@test.com")
+				Expect(status).To(Equal(http.StatusUnprocessableEntity))
+
+				var response map[string]interface{}
+				err := json.Unmarshal([]byte(body), &response)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(response["reason"]).To(Equal("failed to render template: invalid template: if vip is not closed"))
+			})
+		})
+	})
 })
@@ -0,0 +1,199 @@
+/*
+ * Copyright (c) 2026 TFG Co <backend@tfgco.com>
+ * Author: TFG Co <backend@tfgco.com>
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy of
+ * this software and associated documentation files (the "Software"), to deal in
+ * the Software without restriction, including without limitation the rights to
+ * use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+ * the Software, and to permit persons to whom the Software is furnished to do so,
+ * subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+ * FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+ * COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+ * IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+ * CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+ */
+
+package api
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	uuid "github.com/satori/go.uuid"
+	"github.com/topfreegames/marathon/log"
+	"github.com/topfreegames/marathon/model"
+	"github.com/uber-go/zap"
+	pg "gopkg.in/pg.v5"
+)
+
+// TemplateVersionsDiff is the response of the template versions diff route
+type TemplateVersionsDiff struct {
+	From int                           `json:"from"`
+	To   int                           `json:"to"`
+	Diff map[string]*model.AuditChange `json:"diff"`
+}
+
+// createTemplateVersion increments the version of the updated template and creates it
+func (a *Application) createTemplateVersion(tx *pg.Tx, template *model.Template, createdBy string) error {
+	_, err := tx.Model(template).Set("version = version + 1").Where("id = ?", template.ID).Returning("version").Update()
+	if err != nil {
+		return err
+	}
+	return tx.Insert(model.NewTemplateVersion(template, createdBy))
+}
+
+// ListTemplateVersionsHandler is the method called when a get to /apps/:aid/templates/:tid/versions is called
+func (a *Application) ListTemplateVersionsHandler(c echo.Context) error {
+	l := a.Logger.With(
+		zap.String("source", "templateVersionHandler"),
+		zap.String("operation", "listTemplateVersions"),
+		zap.String("appId", c.Param("aid")),
+		zap.String("templateId", c.Param("tid")),
+	)
+	aid, err := uuid.FromString(c.Param("aid"))
+	if err != nil {
+		return c.JSON(http.StatusUnprocessableEntity, &Error{Reason: err.Error()})
+	}
+	tid, err := uuid.FromString(c.Param("tid"))
+	if err != nil {
+		return c.JSON(http.StatusUnprocessableEntity, &Error{Reason: err.Error()})
+	}
+	versions := []model.TemplateVersion{}
+	err = WithSegment("db-select", c, func() error {
+		return a.DB.Model(&versions).Where("template_id = ? AND app_id = ?", tid, aid).Order("version DESC").Select()
+	})
+	if err != nil {
+		log.E(l, "Failed to list template versions.", func(cm log.CM) {
+			cm.Write(zap.Error(err))
+		})
+		return c.JSON(http.StatusInternalServerError, &Error{Reason: err.Error()})
+	}
+	if len(versions) == 0 {
+		return c.JSON(http.StatusNotFound, map[string]string{})
+	}
+	return c.JSON(http.StatusOK, versions)
+}
+
+// DiffTemplateVersionsHandler is the method called when a get to /apps/:aid/templates/:tid/versions/diff is called
+func (a *Application) DiffTemplateVersionsHandler(c echo.Context) error {
+	l := a.Logger.With(
+		zap.String("source", "templateVersionHandler"),
+		zap.String("operation", "diffTemplateVersions"),
+		zap.String("appId", c.Param("aid")),
+		zap.String("templateId", c.Param("tid")),
+	)
+	aid, err := uuid.FromString(c.Param("aid"))
+	if err != nil {
+		return c.JSON(http.StatusUnprocessableEntity, &Error{Reason: err.Error()})
+	}
+	tid, err := uuid.FromString(c.Param("tid"))
+	if err != nil {
+		return c.JSON(http.StatusUnprocessableEntity, &Error{Reason: err.Error()})
+	}
+	from, err := strconv.Atoi(c.QueryParam("from"))
+	if err != nil {
+		return c.JSON(http.StatusUnprocessableEntity, &Error{Reason: model.InvalidField("from").Error()})
+	}
+	to, err := strconv.Atoi(c.QueryParam("to"))
+	if err != nil {
+		return c.JSON(http.StatusUnprocessableEntity, &Error{Reason: model.InvalidField("to").Error()})
+	}
+	versions := []model.TemplateVersion{}
+	err = WithSegment("db-select", c, func() error {
+		return a.DB.Model(&versions).Where(
+			"template_id = ? AND app_id = ? AND version IN (?)",
+			tid, aid, pg.In([]int{from, to}),
+		).Select()
+	})
+	if err != nil {
+		log.E(l, "Failed to retrieve template versions.", func(cm log.CM) {
+			cm.Write(zap.Error(err))
+		})
+		return c.JSON(http.StatusInternalServerError, &Error{Reason: err.Error()})
+	}
+	byVersion := map[int]*model.TemplateVersion{}
+	for i := range versions {
+		byVersion[versions[i].Version] = &versions[i]
+	}
+	if byVersion[from] == nil || byVersion[to] == nil {
+		return c.JSON(http.StatusNotFound, map[string]string{})
+	}
+	return c.JSON(http.StatusOK, &TemplateVersionsDiff{
+		From: from,
+		To:   to,
+		Diff: byVersion[from].Diff(byVersion[to]),
+	})
+}
+
+// RollbackTemplateHandler is the method called when a put to /apps/:aid/templates/:tid/rollback is called,
+// it creates a new version with the content of the given one
+func (a *Application) RollbackTemplateHandler(c echo.Context) error {
+	l := a.Logger.With(
+		zap.String("source", "templateVersionHandler"),
+		zap.String("operation", "rollbackTemplate"),
+		zap.String("appId", c.Param("aid")),
+		zap.String("templateId", c.Param("tid")),
+	)
+	aid, err := uuid.FromString(c.Param("aid"))
+	if err != nil {
+		return c.JSON(http.StatusUnprocessableEntity, &Error{Reason: err.Error()})
+	}
+	tid, err := uuid.FromString(c.Param("tid"))
+	if err != nil {
+		return c.JSON(http.StatusUnprocessableEntity, &Error{Reason: err.Error()})
+	}
+	rollback := &model.TemplateRollback{}
+	err = WithSegment("decodeAndValidate", c, func() error {
+		return decodeAndValidate(c, rollback)
+	})
+	if err != nil {
+		return c.JSON(http.StatusUnprocessableEntity, &Error{Reason: err.Error(), Value: rollback})
+	}
+
+	email := c.Get("user-email").(string)
+	template := &model.Template{ID: tid, AppID: aid}
+	err = WithSegment("db-update", c, func() error {
+		return withTransaction(a.DB, func(tx *pg.Tx) error {
+			version := &model.TemplateVersion{}
+			err := tx.Model(version).
+				Where("template_id = ? AND app_id = ? AND version = ?", tid, aid, rollback.Version).
+				Select()
+			if err != nil {
+				return err
+			}
+			if version.Defaults == nil {
+				version.Defaults = map[string]interface{}{}
+			}
+			_, err = tx.QueryOne(template,
+				"UPDATE templates SET category = ?, service = ?, defaults = ?, body = ?, push_options = ?, updated_at = ? WHERE id = ? AND app_id = ? RETURNING *",
+				version.Category, version.Service, version.Defaults, version.Body, version.PushOptions, time.Now().UnixNano(), tid, aid,
+			)
+			if err != nil {
+				return err
+			}
+			return a.createTemplateVersion(tx, template, email)
+		})
+	})
+	if err != nil {
+		if err.Error() == RecordNotFoundString {
+			return c.JSON(http.StatusNotFound, map[string]string{})
+		}
+		log.E(l, "Failed to roll template back.", func(cm log.CM) {
+			cm.Write(zap.Error(err))
+		})
+		return c.JSON(http.StatusInternalServerError, &Error{Reason: err.Error(), Value: rollback})
+	}
+	log.I(l, "Rolled template back successfully.", func(cm log.CM) {
+		cm.Write(zap.Int("version", rollback.Version), zap.Int("newVersion", template.Version))
+	})
+	return c.JSON(http.StatusOK, template)
+}
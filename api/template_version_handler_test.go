@@ -0,0 +1,217 @@
+/*
+ * Copyright (c) 2026 TFG Co <backend@tfgco.com>
+ * Author: TFG Co <backend@tfgco.com>
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy of
+ * this software and associated documentation files (the "Software"), to deal in
+ * the Software without restriction, including without limitation the rights to
+ * use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+ * the Software, and to permit persons to whom the Software is furnished to do so,
+ * subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+ * FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+ * COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+ * IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+ * CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+ */
+
+package api_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	uuid "github.com/satori/go.uuid"
+	"github.com/topfreegames/marathon/model"
+	. "github.com/topfreegames/marathon/testing"
+	"github.com/uber-go/zap"
+)
+
+var _ = Describe("Template Version Handler", func() {
+	logger := zap.New(
+		zap.NewJSONEncoder(zap.NoTime()), // drop timestamps in tests
+		zap.FatalLevel,
+	)
+	app := GetDefaultTestApp(logger)
+	var existingApp *model.App
+	var existingTemplate *model.Template
+	var baseRoute string
+
+	updateTemplate := func(body map[string]interface{}) {
+		payload := GetTemplatePayload(map[string]interface{}{
+			"name":   existingTemplate.Name,
+			"locale": existingTemplate.Locale,
+			"body":   body,
+		})
+		pl, _ := json.Marshal(payload)
+		status, _ := Put(app, baseRoute, string(pl), "test@test.com")
+		Expect(status).To(Equal(http.StatusOK))
+	}
+
+	BeforeEach(func() {
+		app.DB.Exec("DELETE FROM apps;")
+		app.DB.Exec("DELETE FROM templates;")
+		app.DB.Exec("DELETE FROM users;")
+		CreateTestUser(app.DB, map[string]interface{}{"email": "test@test.com", "isAdmin": true})
+		existingApp = CreateTestApp(app.DB)
+		existingTemplate = CreateTestTemplate(app.DB, existingApp.ID, map[string]interface{}{
+			"locale": "en",
+			"body":   map[string]interface{}{"alert": "first"},
+		})
+		baseRoute = fmt.Sprintf("/apps/%s/templates/%s", existingApp.ID, existingTemplate.ID)
+	})
+
+	Describe("Post /apps/:aid/templates", func() {
+		It("should create the first version of the template", func() {
+			pl, _ := json.Marshal(GetTemplatePayload())
+			status, body := Post(app, fmt.Sprintf("/apps/%s/templates", existingApp.ID), string(pl), "test@test.com")
+			Expect(status).To(Equal(http.StatusCreated))
+
+			var template map[string]interface{}
+			err := json.Unmarshal([]byte(body), &template)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(template["version"]).To(BeEquivalentTo(1))
+
+			status, body = Get(app, fmt.Sprintf("/apps/%s/templates/%s/versions", existingApp.ID, template["id"]), "test@test.com")
+			Expect(status).To(Equal(http.StatusOK))
+			var versions []map[string]interface{}
+			err = json.Unmarshal([]byte(body), &versions)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(versions).To(HaveLen(1))
+			Expect(versions[0]["body"]).To(Equal(template["body"]))
+		})
+	})
+
+	Describe("Get /apps/:aid/templates/:tid/versions", func() {
+		It("should return 200 and a version for each update, the latest first", func() {
+			updateTemplate(map[string]interface{}{"alert": "second"})
+
+			status, body := Get(app, fmt.Sprintf("%s/versions", baseRoute), "test@test.com")
+			Expect(status).To(Equal(http.StatusOK))
+
+			var versions []map[string]interface{}
+			err := json.Unmarshal([]byte(body), &versions)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(versions).To(HaveLen(2))
+			Expect(versions[0]["version"]).To(BeEquivalentTo(2))
+			Expect(versions[0]["body"]).To(Equal(map[string]interface{}{"alert": "second"}))
+			Expect(versions[0]["createdBy"]).To(Equal("test@test.com"))
+			Expect(versions[1]["version"]).To(BeEquivalentTo(1))
+			Expect(versions[1]["body"]).To(Equal(map[string]interface{}{"alert": "first"}))
+
+			dbTemplate := &model.Template{ID: existingTemplate.ID}
+			err = app.DB.Select(dbTemplate)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(dbTemplate.Version).To(Equal(2))
+		})
+
+		It("should return 404 if the template does not exist", func() {
+			status, _ := Get(app, fmt.Sprintf("/apps/%s/templates/%s/versions", existingApp.ID, uuid.NewV4()), "test@test.com")
+			Expect(status).To(Equal(http.StatusNotFound))
+		})
+	})
+
+	Describe("Get /apps/:aid/templates/:tid/versions/diff", func() {
+		It("should return 200 and the fields that changed", func() {
+			updateTemplate(map[string]interface{}{"alert": "second", "sound": "bell"})
+
+			status, body := Get(app, fmt.Sprintf("%s/versions/diff?from=1&to=2", baseRoute), "test@test.com")
+			Expect(status).To(Equal(http.StatusOK))
+
+			var diff map[string]interface{}
+			err := json.Unmarshal([]byte(body), &diff)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(diff["from"]).To(BeEquivalentTo(1))
+			Expect(diff["to"]).To(BeEquivalentTo(2))
+			changes := diff["diff"].(map[string]interface{})
+			Expect(changes["body.alert"]).To(Equal(map[string]interface{}{"before": "first", "after": "second"}))
+			Expect(changes["body.sound"]).To(Equal(map[string]interface{}{"before": nil, "after": "bell"}))
+			Expect(changes).NotTo(HaveKey("name"))
+		})
+
+		It("should return 404 if a version does not exist", func() {
+			status, _ := Get(app, fmt.Sprintf("%s/versions/diff?from=1&to=5", baseRoute), "test@test.com")
+			Expect(status).To(Equal(http.StatusNotFound))
+		})
+
+		It("should return 422 if a version is not given", func() {
+			status, body := Get(app, fmt.Sprintf("%s/versions/diff?from=1", baseRoute), "test@test.com")
+			Expect(status).To(Equal(http.StatusUnprocessableEntity))
+
+			var response map[string]interface{}
+			err := json.Unmarshal([]byte(body), &response)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(response["reason"]).To(Equal("invalid to"))
+		})
+	})
+
+	Describe("Put /apps/:aid/templates/:tid/rollback", func() {
+		It("should return 200 and create a version with the content of the given one", func() {
+			updateTemplate(map[string]interface{}{"alert": "second"})
+
+			status, body := Put(app, fmt.Sprintf("%s/rollback", baseRoute), `{"version": 1}`, "test@test.com")
+			Expect(status).To(Equal(http.StatusOK))
+
+			var template map[string]interface{}
+			err := json.Unmarshal([]byte(body), &template)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(template["version"]).To(BeEquivalentTo(3))
+			Expect(template["body"]).To(Equal(map[string]interface{}{"alert": "first"}))
+
+			versions := []model.TemplateVersion{}
+			err = app.DB.Model(&versions).Where("template_id = ?", existingTemplate.ID).Order("version").Select()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(versions).To(HaveLen(3))
+			Expect(versions[1].Body).To(Equal(map[string]interface{}{"alert": "second"}))
+			Expect(versions[2].Body).To(Equal(map[string]interface{}{"alert": "first"}))
+		})
+
+		It("should return 404 if the version does not exist", func() {
+			status, _ := Put(app, fmt.Sprintf("%s/rollback", baseRoute), `{"version": 7}`, "test@test.com")
+			Expect(status).To(Equal(http.StatusNotFound))
+		})
+
+		It("should return 422 if the version is invalid", func() {
+			status, body := Put(app, fmt.Sprintf("%s/rollback", baseRoute), `{"version": 0}`, "test@test.com")
+			Expect(status).To(Equal(http.StatusUnprocessableEntity))
+
+			var response map[string]interface{}
+			err := json.Unmarshal([]byte(body), &response)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(response["reason"]).To(Equal("invalid version"))
+		})
+	})
+
+	Describe("Job template versions", func() {
+		It("should render the templates of the job as they were when it was created", func() {
+			pl, _ := json.Marshal(GetJobPayload())
+			status, body := Post(app, fmt.Sprintf("/apps/%s/jobs?template=%s", existingApp.ID, existingTemplate.Name), string(pl), "test@test.com")
+			Expect(status).To(Equal(http.StatusCreated))
+
+			var response map[string]interface{}
+			err := json.Unmarshal([]byte(body), &response)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(response["templateVersions"]).To(Equal(map[string]interface{}{
+				existingTemplate.ID.String(): float64(1),
+			}))
+
+			updateTemplate(map[string]interface{}{"alert": "second"})
+
+			job := &model.Job{ID: uuid.FromStringOrNil(response["id"].(string))}
+			err = job.GetJobInfoAndApp(app.DB)
+			Expect(err).NotTo(HaveOccurred())
+			templates, err := job.GetJobTemplatesByNameAndLocale(app.DB)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(templates[existingTemplate.Name]["en"].Body).To(Equal(map[string]interface{}{"alert": "first"}))
+			Expect(templates[existingTemplate.Name]["en"].Version).To(Equal(1))
+		})
+	})
+})
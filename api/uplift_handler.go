@@ -0,0 +1,117 @@
+/*
+ * Copyright (c) 2026 TFG Co <backend@tfgco.com>
+ * Author: TFG Co <backend@tfgco.com>
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy of
+ * this software and associated documentation files (the "Software"), to deal in
+ * the Software without restriction, including without limitation the rights to
+ * use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+ * the Software, and to permit persons to whom the Software is furnished to do so,
+ * subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+ * FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+ * COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+ * IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+ * CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+ */
+
+package api
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	uuid "github.com/satori/go.uuid"
+	"github.com/topfreegames/marathon/log"
+	"github.com/topfreegames/marathon/model"
+	"github.com/uber-go/zap"
+)
+
+// PostUpliftHandler is the method called when a post to /apps/:aid/jobs/:jid/uplift is called
+func (a *Application) PostUpliftHandler(c echo.Context) error {
+	l := a.Logger.With(
+		zap.String("source", "upliftHandler"),
+		zap.String("operation", "postUplift"),
+		zap.String("appId", c.Param("aid")),
+		zap.String("jobId", c.Param("jid")),
+	)
+	aid, err := uuid.FromString(c.Param("aid"))
+	if err != nil {
+		return c.JSON(http.StatusUnprocessableEntity, &Error{Reason: err.Error()})
+	}
+	jid, err := uuid.FromString(c.Param("jid"))
+	if err != nil {
+		return c.JSON(http.StatusUnprocessableEntity, &Error{Reason: err.Error()})
+	}
+	userEmail := c.Get("user-email").(string)
+	upliftRequest := &model.UpliftRequest{}
+	err = WithSegment("decodeAndValidate", c, func() error {
+		return decodeAndValidate(c, upliftRequest)
+	})
+	if err != nil {
+		return c.JSON(http.StatusUnprocessableEntity, &Error{Reason: err.Error(), Value: upliftRequest})
+	}
+
+	job := &model.Job{}
+	err = WithSegment("db-select", c, func() error {
+		return a.DB.Model(&job).Column("job.*", "App").Where("job.id = ? AND job.app_id = ?", jid, aid).Select()
+	})
+	if err != nil {
+		if err.Error() == RecordNotFoundString {
+			return c.JSON(http.StatusNotFound, map[string]string{})
+		}
+		log.E(l, "Failed to retrieve job.", func(cm log.CM) {
+			cm.Write(zap.Error(err))
+		})
+		return c.JSON(http.StatusInternalServerError, &Error{Reason: err.Error(), Value: job})
+	}
+	if job.ControlGroup == 0 {
+		return c.JSON(http.StatusUnprocessableEntity, &Error{Reason: "job has no control group", Value: upliftRequest})
+	}
+	if job.ControlGroupCSVPath == "" || job.TreatedCSVPath == "" {
+		return c.JSON(http.StatusConflict, &Error{Reason: "job is not completed", Value: upliftRequest})
+	}
+	if job.UpliftReport != nil && job.UpliftReport.Status == model.UpliftReportPending {
+		return c.JSON(http.StatusConflict, &Error{Reason: "uplift report is already pending", Value: upliftRequest})
+	}
+
+	job.UpliftReport = &model.UpliftReport{
+		Status:     model.UpliftReportPending,
+		EventsPath: upliftRequest.EventsPath,
+		Event:      upliftRequest.Event,
+		CreatedBy:  userEmail,
+		CreatedAt:  time.Now().UnixNano(),
+	}
+	err = WithSegment("db-update", c, func() error {
+		_, err = a.DB.Model(&job).Column("uplift_report").Update()
+		return err
+	})
+	if err != nil {
+		log.E(l, "Failed to update job uplift report.", func(cm log.CM) {
+			cm.Write(zap.Error(err))
+		})
+		return c.JSON(http.StatusInternalServerError, &Error{Reason: err.Error(), Value: job})
+	}
+
+	err = WithSegment("create-uplift-worker", c, func() error {
+		_, err = a.Worker.CreateUpliftJob(job.ID.String())
+		return err
+	})
+	if err != nil {
+		log.E(l, "Failed to create uplift worker.", func(cm log.CM) {
+			cm.Write(zap.Error(err))
+		})
+		return c.JSON(http.StatusInternalServerError, &Error{Reason: err.Error(), Value: job})
+	}
+
+	log.D(l, "Created uplift report successfully.", func(cm log.CM) {
+		cm.Write(zap.Object("upliftReport", job.UpliftReport))
+	})
+	return c.JSON(http.StatusAccepted, job.UpliftReport)
+}
@@ -0,0 +1,154 @@
+/*
+ * Copyright (c) 2026 TFG Co <backend@tfgco.com>
+ * Author: TFG Co <backend@tfgco.com>
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy of
+ * this software and associated documentation files (the "Software"), to deal in
+ * the Software without restriction, including without limitation the rights to
+ * use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+ * the Software, and to permit persons to whom the Software is furnished to do so,
+ * subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+ * FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+ * COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+ * IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+ * CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+ */
+
+package api_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	uuid "github.com/satori/go.uuid"
+	"github.com/topfreegames/marathon/model"
+	. "github.com/topfreegames/marathon/testing"
+	"github.com/uber-go/zap"
+)
+
+var _ = Describe("Uplift Handler", func() {
+	logger := zap.New(
+		zap.NewJSONEncoder(zap.NoTime()), // drop timestamps in tests
+		zap.FatalLevel,
+	)
+	app := GetDefaultTestApp(logger)
+	var existingApp *model.App
+	var existingTemplate *model.Template
+	var job *model.Job
+	var baseRoute string
+
+	BeforeEach(func() {
+		app.DB.Exec("DELETE FROM apps;")
+		app.DB.Exec("DELETE FROM users;")
+		CreateTestUser(app.DB, map[string]interface{}{"email": "test@test.com", "isAdmin": true})
+		app.Worker.RedisClient.FlushAll()
+
+		existingApp = CreateTestApp(app.DB)
+		existingTemplate = CreateTestTemplate(app.DB, existingApp.ID)
+		job = CreateTestJob(app.DB, existingApp.ID, existingTemplate.Name, map[string]interface{}{
+			"controlGroup":        0.1,
+			"controlGroupCsvPath": "bucket/control/job.csv",
+			"treatedCsvPath":      "bucket/control/job-treated.csv",
+		})
+		baseRoute = fmt.Sprintf("/apps/%s/jobs/%s/uplift", existingApp.ID, job.ID)
+	})
+
+	Describe("Post /apps/:aid/jobs/:jid/uplift", func() {
+		Describe("Sucesfully", func() {
+			It("should return 202 and create the uplift worker", func() {
+				pl, _ := json.Marshal(map[string]interface{}{
+					"eventsPath": "bucket/folder/events.csv",
+					"event":      "purchase",
+				})
+				status, body := Post(app, baseRoute, string(pl), "test@test.com")
+				Expect(status).To(Equal(http.StatusAccepted))
+
+				var response map[string]interface{}
+				err := json.Unmarshal([]byte(body), &response)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(response["status"]).To(Equal(model.UpliftReportPending))
+				Expect(response["eventsPath"]).To(Equal("bucket/folder/events.csv"))
+				Expect(response["event"]).To(Equal("purchase"))
+				Expect(response["createdBy"]).To(Equal("test@test.com"))
+
+				dbJob := &model.Job{ID: job.ID}
+				err = app.DB.Select(&dbJob)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(dbJob.UpliftReport.Status).To(Equal(model.UpliftReportPending))
+
+				res, err := app.Worker.RedisClient.LPop("queue:uplift_worker").Result()
+				Expect(err).NotTo(HaveOccurred())
+				msg := map[string]interface{}{}
+				err = json.Unmarshal([]byte(res), &msg)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(msg["args"]).To(Equal([]interface{}{job.ID.String()}))
+			})
+		})
+
+		Describe("Unsucesfully", func() {
+			It("should return 404 if the job does not exist", func() {
+				pl, _ := json.Marshal(map[string]interface{}{"eventsPath": "bucket/folder/events.csv"})
+				status, _ := Post(app, fmt.Sprintf("/apps/%s/jobs/%s/uplift", existingApp.ID, uuid.NewV4()), string(pl), "test@test.com")
+				Expect(status).To(Equal(http.StatusNotFound))
+			})
+
+			It("should return 422 if eventsPath is missing", func() {
+				status, body := Post(app, baseRoute, "{}", "test@test.com")
+				Expect(status).To(Equal(http.StatusUnprocessableEntity))
+
+				var response map[string]interface{}
+				err := json.Unmarshal([]byte(body), &response)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(response["reason"]).To(Equal("invalid eventsPath"))
+			})
+
+			It("should return 422 if the job has no control group", func() {
+				otherJob := CreateTestJob(app.DB, existingApp.ID, existingTemplate.Name)
+				pl, _ := json.Marshal(map[string]interface{}{"eventsPath": "bucket/folder/events.csv"})
+				status, body := Post(app, fmt.Sprintf("/apps/%s/jobs/%s/uplift", existingApp.ID, otherJob.ID), string(pl), "test@test.com")
+				Expect(status).To(Equal(http.StatusUnprocessableEntity))
+
+				var response map[string]interface{}
+				err := json.Unmarshal([]byte(body), &response)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(response["reason"]).To(Equal("job has no control group"))
+			})
+
+			It("should return 409 if the job is not completed", func() {
+				otherJob := CreateTestJob(app.DB, existingApp.ID, existingTemplate.Name, map[string]interface{}{
+					"controlGroup": 0.1,
+				})
+				pl, _ := json.Marshal(map[string]interface{}{"eventsPath": "bucket/folder/events.csv"})
+				status, body := Post(app, fmt.Sprintf("/apps/%s/jobs/%s/uplift", existingApp.ID, otherJob.ID), string(pl), "test@test.com")
+				Expect(status).To(Equal(http.StatusConflict))
+
+				var response map[string]interface{}
+				err := json.Unmarshal([]byte(body), &response)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(response["reason"]).To(Equal("job is not completed"))
+			})
+
+			It("should return 409 if the uplift report is pending", func() {
+				pl, _ := json.Marshal(map[string]interface{}{"eventsPath": "bucket/folder/events.csv"})
+				status, _ := Post(app, baseRoute, string(pl), "test@test.com")
+				Expect(status).To(Equal(http.StatusAccepted))
+				status, body := Post(app, baseRoute, string(pl), "test@test.com")
+				Expect(status).To(Equal(http.StatusConflict))
+
+				var response map[string]interface{}
+				err := json.Unmarshal([]byte(body), &response)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(response["reason"]).To(Equal("uplift report is already pending"))
+			})
+		})
+	})
+})
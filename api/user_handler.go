@@ -148,7 +148,7 @@ func (a *Application) UpdateUserHandler(c echo.Context) error {
 	}
 	user.ID = id
 	err = WithSegment("db-update", c, func() error {
-		_, err = a.DB.Model(&user).Column("is_admin").Column("allowed_apps").Column("updated_at").Returning("*").Update()
+		_, err = a.DB.Model(&user).Column("is_admin").Column("is_approver").Column("allowed_apps").Column("app_roles").Column("updated_at").Returning("*").Update()
 		return err
 	})
 	// FIXME: Ugly fix to remove duplicate elements returned by update
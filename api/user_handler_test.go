@@ -45,7 +45,7 @@ var _ = Describe("User Handler", func() {
 	faultyDb := GetFaultyTestDB(app)
 	var testUser *model.User
 	BeforeEach(func() {
-		app.DB.Exec("TRUNCATE TABLE users;")
+		app.DB.Exec("TRUNCATE TABLE users CASCADE;")
 		testUser = CreateTestUser(app.DB, map[string]interface{}{"email": "test@test.com", "isAdmin": true})
 	})
 
@@ -187,6 +187,19 @@ var _ = Describe("User Handler", func() {
 				Expect(err).NotTo(HaveOccurred())
 				Expect(response["reason"]).To(Equal("invalid email"))
 			})
+
+			It("should return 422 if invalid app role", func() {
+				payload := GetUserPayload()
+				payload["appRoles"] = map[string][]string{uuid.NewV4().String(): {"viewer", "owner"}}
+				pl, _ := json.Marshal(payload)
+				status, body := Post(app, "/users", string(pl), "test@test.com")
+				Expect(status).To(Equal(http.StatusUnprocessableEntity))
+
+				var response map[string]interface{}
+				err := json.Unmarshal([]byte(body), &response)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(response["reason"]).To(Equal("invalid appRoles"))
+			})
 		})
 	})
 
@@ -49,6 +49,9 @@ var workerCmd = &cobra.Command{
 		logger.Debug("configuring workers...")
 		w := worker.NewWorker(logger, cfgFile)
 
+		logger.Debug("starting scheduler...")
+		go worker.NewScheduler(w).Start()
+
 		logger.Debug("starting worker...")
 		w.Start()
 	},
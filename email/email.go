@@ -138,6 +138,45 @@ This action is irreversible and this job's push notifications will no longer be
 	return sendgridClient.SendgridSendEmail(job.CreatedBy, subject, message, skipBlacklist)
 }
 
+//SendApprovedJobEmail builds an approved job email message and sends it with sendgrid
+func SendApprovedJobEmail(sendgridClient *extensions.SendgridClient, job *model.Job, appName, approvedBy string) error {
+	subject := "Push job approved"
+	platform := getPlatformFromService(job.Service)
+
+	message := fmt.Sprintf(`
+Hello, your push job was approved and will be sent.
+
+ApprovedBy: %s
+
+App: %s
+Template: %s
+Platform: %s
+JobID: %s
+CreatedBy: %s
+`, approvedBy, appName, job.TemplateName, platform, job.ID, job.CreatedBy)
+	return sendgridClient.SendgridSendEmail(job.CreatedBy, subject, message, false)
+}
+
+//SendRejectedJobEmail builds a rejected job email message and sends it with sendgrid
+func SendRejectedJobEmail(sendgridClient *extensions.SendgridClient, job *model.Job, appName, rejectedBy, reason string) error {
+	subject := "Push job rejected"
+	platform := getPlatformFromService(job.Service)
+
+	message := fmt.Sprintf(`
+Hello, your push job was rejected and will not be sent.
+
+RejectedBy: %s
+Reason: %s
+
+App: %s
+Template: %s
+Platform: %s
+JobID: %s
+CreatedBy: %s
+`, rejectedBy, reason, appName, job.TemplateName, platform, job.ID, job.CreatedBy)
+	return sendgridClient.SendgridSendEmail(job.CreatedBy, subject, message, false)
+}
+
 //SendCircuitBreakJobEmail builds a circuit break job email message and sends it with sendgrid
 func SendCircuitBreakJobEmail(sendgridClient *extensions.SendgridClient, job *model.Job, appName string, expireAt int64) error {
 	subject := "Push job entered circuit break state"
@@ -124,7 +124,7 @@ func (c *KafkaProducer) Close() {
 }
 
 //SendAPNSPush notification to Kafka
-func (c *KafkaProducer) SendAPNSPush(topic, deviceToken string, payload, messageMetadata map[string]interface{}, pushMetadata map[string]interface{}, pushExpiry int64, templateName string) error {
+func (c *KafkaProducer) SendAPNSPush(topic, deviceToken string, payload, messageMetadata map[string]interface{}, pushMetadata map[string]interface{}, pushExpiry int64, templateName string, options *messages.PushOptions) error {
 	msg := messages.NewAPNSMessage(
 		deviceToken,
 		pushExpiry,
@@ -133,6 +133,7 @@ func (c *KafkaProducer) SendAPNSPush(topic, deviceToken string, payload, message
 		pushMetadata,
 		templateName,
 	)
+	msg.SetOptions(options)
 
 	if val, ok := pushMetadata["dryRun"]; ok {
 		if dryRun, _ := val.(bool); dryRun {
@@ -149,7 +150,7 @@ func (c *KafkaProducer) SendAPNSPush(topic, deviceToken string, payload, message
 }
 
 //SendGCMPush notification to Kafka
-func (c *KafkaProducer) SendGCMPush(topic, deviceToken string, payload, messageMetadata map[string]interface{}, pushMetadata map[string]interface{}, pushExpiry int64, templateName string) error {
+func (c *KafkaProducer) SendGCMPush(topic, deviceToken string, payload, messageMetadata map[string]interface{}, pushMetadata map[string]interface{}, pushExpiry int64, templateName string, options *messages.PushOptions) error {
 	msg := messages.NewGCMMessage(
 		deviceToken,
 		payload,
@@ -158,6 +159,7 @@ func (c *KafkaProducer) SendGCMPush(topic, deviceToken string, payload, messageM
 		pushExpiry,
 		templateName,
 	)
+	msg.SetOptions(options)
 
 	if val, ok := pushMetadata["dryRun"]; ok {
 		if dryRun, _ := val.(bool); dryRun {
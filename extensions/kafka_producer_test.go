@@ -126,7 +126,7 @@ var _ = Describe("Kafka Extension", func() {
 			payload := map[string]interface{}{"x": 1}
 			meta := map[string]interface{}{"a": 1}
 			expiry := time.Now().Unix()
-			kafka.SendGCMPush("consumer", "device-token", payload, meta, nil, expiry, "template")
+			kafka.SendGCMPush("consumer", "device-token", payload, meta, nil, expiry, "template", nil)
 			msg, err := getNextMessageFrom(testConsumer)
 			Expect(err).NotTo(HaveOccurred())
 			Expect(msg).NotTo(BeNil())
@@ -150,7 +150,7 @@ var _ = Describe("Kafka Extension", func() {
 			payload := map[string]interface{}{"x": 1}
 			meta := map[string]interface{}{"a": 1}
 			expiry := time.Now().Unix()
-			kafka.SendAPNSPush("consumer", "device-token", payload, meta, nil, expiry, "template")
+			kafka.SendAPNSPush("consumer", "device-token", payload, meta, nil, expiry, "template", nil)
 
 			msg, err := getNextMessageFrom(testConsumer)
 			Expect(err).NotTo(HaveOccurred())
@@ -33,6 +33,7 @@ import (
 	"github.com/spf13/viper"
 	"github.com/topfreegames/marathon/extensions"
 	"github.com/topfreegames/marathon/log"
+	"github.com/topfreegames/marathon/model"
 	"github.com/uber-go/zap"
 )
 
@@ -49,10 +50,12 @@ type Handler struct {
 	Config            *viper.Viper
 	pendingMessagesWG *sync.WaitGroup
 	FeedbackCache     map[string]map[string]int
-	FlushInterval     time.Duration
-	MarathonDB        *extensions.PGClient
-	Logger            zap.Logger
-	run               bool
+	// VariantFeedbackCache has the feedbacks by job and variant of the jobs with many templates
+	VariantFeedbackCache map[string]map[string]map[string]int
+	FlushInterval        time.Duration
+	MarathonDB           *extensions.PGClient
+	Logger               zap.Logger
+	run                  bool
 }
 
 // Message is a struct that will decode a apns or gcm feedback message
@@ -71,10 +74,11 @@ type Message struct {
 // NewHandler creates a new instance of feedback.Handler
 func NewHandler(config *viper.Viper, logger zap.Logger, pendingMessagesWG *sync.WaitGroup, DBOrNil ...*extensions.PGClient) (*Handler, error) {
 	h := &Handler{
-		Config:            config,
-		Logger:            logger,
-		pendingMessagesWG: pendingMessagesWG,
-		FeedbackCache:     map[string]map[string]int{},
+		Config:               config,
+		Logger:               logger,
+		pendingMessagesWG:    pendingMessagesWG,
+		FeedbackCache:        map[string]map[string]int{},
+		VariantFeedbackCache: map[string]map[string]map[string]int{},
 	}
 	if len(DBOrNil) > 0 {
 		h.configure(DBOrNil[0])
@@ -138,6 +142,18 @@ func (h *Handler) handleErrorMessage(jobID string, err string) {
 	feedbackCacheMutex.Unlock()
 }
 
+func (h *Handler) handleVariantMessage(jobID, variant, key string) {
+	feedbackCacheMutex.Lock()
+	if _, ok := h.VariantFeedbackCache[jobID]; !ok {
+		h.VariantFeedbackCache[jobID] = map[string]map[string]int{}
+	}
+	if _, ok := h.VariantFeedbackCache[jobID][variant]; !ok {
+		h.VariantFeedbackCache[jobID][variant] = map[string]int{}
+	}
+	h.VariantFeedbackCache[jobID][variant][key]++
+	feedbackCacheMutex.Unlock()
+}
+
 func (h *Handler) handleMessage(msg []byte) {
 	defer func() {
 		if h.pendingMessagesWG != nil {
@@ -171,16 +187,25 @@ func (h *Handler) handleMessage(msg []byte) {
 		return
 	}
 
+	jobID := message.Metadata["jobId"].(string)
+	key := ""
 	if len(message.Error) == 0 && (message.Err == nil || len(message.Err) == 0) {
-		h.handleSuccessMessage(message.Metadata["jobId"].(string))
+		key = "ack"
+		h.handleSuccessMessage(jobID)
 	} else {
 		if service == APNS {
-			h.handleErrorMessage(message.Metadata["jobId"].(string), message.Err["Key"].(string))
+			key = message.Err["Key"].(string)
+			h.handleErrorMessage(jobID, key)
 		} else if service == GCM {
-			h.handleErrorMessage(message.Metadata["jobId"].(string), message.Error)
+			key = message.Error
+			h.handleErrorMessage(jobID, key)
 		}
 	}
 
+	if variant, ok := message.Metadata["variant"].(string); ok && len(variant) > 0 && len(key) > 0 {
+		h.handleVariantMessage(jobID, variant, key)
+	}
+
 }
 
 func (h *Handler) generatePGIncrJSON(jobID string, values map[string]int) string {
@@ -216,6 +241,14 @@ func (h *Handler) flushFeedbacks() {
 			}
 			delete(h.FeedbackCache, k)
 		}
+		for k, v := range h.VariantFeedbackCache {
+			query, params := model.VariantFeedbacksIncrQuery(k, v)
+			_, err := h.MarathonDB.DB.ExecOne(query, params...)
+			if err != nil {
+				h.Logger.Error("error updating variant feedbacks", zap.Error(err))
+			}
+			delete(h.VariantFeedbackCache, k)
+		}
 		feedbackCacheMutex.Unlock()
 	}
 }
@@ -123,6 +123,18 @@ var _ = Describe("Feedback Handler", func() {
 			}))
 		})
 
+		It("should handle a message of a variant", func() {
+			m := fmt.Sprintf("{\"from\":\"F8DIN2OFA0X3IOV897KUVPWU9CR2GNGUIOODWUFFVMJTFGQB45CY0ZEKXV758JOY0Z46P2CUCVL9HMNI3UGE5YXZYDM1AM0DX5ENIEGESOOLOV23YCKXG39ODFJXCU3UZFIW5ZCWLSEGGM1MY7SSGT07\",\"message_id\":\"422fc070-bf0e-4005-86e9-6aafaee9f3dd\",\"message_type\":\"ack\",\"error\": null,\"category\":\"\",\"metadata\":{\"jobId\":\"%s\",\"variant\":\"tpl1\"}}", jobID.String())
+			handler.handleMessage([]byte(m))
+			handler.handleMessage([]byte(m))
+			Expect(handler.FeedbackCache[jobID.String()]).To(BeEquivalentTo(map[string]int{
+				"ack": 2,
+			}))
+			Expect(handler.VariantFeedbackCache[jobID.String()]).To(BeEquivalentTo(map[string]map[string]int{
+				"tpl1": {"ack": 2},
+			}))
+		})
+
 		It("should do nothing if message has no metadata", func() {
 			Expect(len(handler.FeedbackCache)).To(Equal(0))
 			m := "{\"from\":\"F8DIN2OFA0X3IOV897KUVPWU9CR2GNGUIOODWUFFVMJTFGQB45CY0ZEKXV758JOY0Z46P2CUCVL9HMNI3UGE5YXZYDM1AM0DX5ENIEGESOOLOV23YCKXG39ODFJXCU3UZFIW5ZCWLSEGGM1MY7SSGT07\",\"message_id\":\"422fc070-bf0e-4005-86e9-6aafaee9f3dd\",\"message_type\":\"ack\",\"error\": null,\"category\":\"\"}"
@@ -171,6 +183,24 @@ var _ = Describe("Feedback Handler", func() {
 				return len(mockPG.ExecOnes)
 			}).Should(Equal(1))
 		})
+		It("should flush the variant feedbacks", func() {
+			mockPG := testing.NewPGMock(0, 0, nil)
+			mockDB, err := extensions.NewPGClient("db", config, logger, mockPG)
+			Expect(err).NotTo(HaveOccurred())
+			h, err := NewHandler(config, logger, nil, mockDB)
+			Expect(err).NotTo(HaveOccurred())
+			m := fmt.Sprintf("{\"from\":\"F8DIN2OFA0X3IOV897KUVPWU9CR2GNGUIOODWUFFVMJTFGQB45CY0ZEKXV758JOY0Z46P2CUCVL9HMNI3UGE5YXZYDM1AM0DX5ENIEGESOOLOV23YCKXG39ODFJXCU3UZFIW5ZCWLSEGGM1MY7SSGT07\",\"message_id\":\"422fc070-bf0e-4005-86e9-6aafaee9f3dd\",\"message_type\":\"ack\",\"error\": null,\"category\":\"\",\"metadata\":{\"jobId\":\"%s\",\"variant\":\"tpl1\"}}", jobID.String())
+			h.handleMessage([]byte(m))
+			Expect(len(h.VariantFeedbackCache)).To(Equal(1))
+			h.FlushInterval = time.Duration(10) * time.Millisecond
+			go h.flushFeedbacks()
+			Eventually(func() int {
+				return len(h.VariantFeedbackCache)
+			}).Should(Equal(0))
+			Eventually(func() int {
+				return len(mockPG.ExecOnes)
+			}).Should(Equal(2))
+		})
 	})
 
 	Describe("HandleMessages", func() {
@@ -19,6 +19,7 @@ require (
 	github.com/onsi/ginkgo v1.14.2
 	github.com/onsi/gomega v1.10.4
 	github.com/pressly/goose v0.0.0-20161106184528-d6e8fe029271
+	github.com/robfig/cron/v3 v3.0.1
 	github.com/satori/go.uuid v1.2.0
 	github.com/sendgrid/sendgrid-go v3.4.1+incompatible
 	github.com/sirupsen/logrus v1.6.0
@@ -27,7 +28,6 @@ require (
 	github.com/topfreegames/extensions v8.2.1+incompatible
 	github.com/topfreegames/go-extensions-http v1.0.0
 	github.com/uber-go/zap v0.0.0-20160809182253-d11d2851fcab
-	github.com/valyala/fasttemplate v1.2.1
 	gopkg.in/pg.v5 v5.3.3
 	gopkg.in/redis.v5 v5.2.9
 )
@@ -86,6 +86,7 @@ require (
 	github.com/topfreegames/go-extensions-tracing v1.0.0 // indirect
 	github.com/uber-go/atomic v1.3.1 // indirect
 	github.com/valyala/bytebufferpool v1.0.0 // indirect
+	github.com/valyala/fasttemplate v1.2.1 // indirect
 	go.opentelemetry.io/otel v0.15.0 // indirect
 	go.uber.org/atomic v1.10.0 // indirect
 	golang.org/x/crypto v0.0.0-20220722155217-630584e8d5aa // indirect
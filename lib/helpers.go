@@ -68,7 +68,11 @@ func (m *Marathon) sendTo(
 		}
 	}
 	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("x-forwarded-email", m.userEmail)
+	if m.apiToken != "" {
+		req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", m.apiToken))
+	} else {
+		req.Header.Set("x-forwarded-email", m.userEmail)
+	}
 	if ctx == nil {
 		ctx = context.Background()
 	}
@@ -34,6 +34,7 @@ type Marathon struct {
 	httpClient *http.Client
 	url        string
 	userEmail  string
+	apiToken   string
 	appID      string
 }
 
@@ -43,6 +44,7 @@ type Config struct {
 	Timeout   time.Duration
 	URL       string
 	UserEmail string
+	APIToken  string // if set, used instead of UserEmail to authenticate
 	AppID     string
 }
 
@@ -56,6 +58,7 @@ func NewMarathon(config *Config) *Marathon {
 		httpClient: getHTTPClient(config),
 		url:        config.URL,
 		userEmail:  config.UserEmail,
+		apiToken:   config.APIToken,
 		appID:      config.AppID,
 	}
 
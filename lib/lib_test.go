@@ -29,6 +29,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"net/http"
 	"time"
 
 	"github.com/jarcoal/httpmock"
@@ -143,4 +144,28 @@ var _ = Describe("Lib", func() {
 			Expect(jobs).To(Equal(response))
 		})
 	})
+
+	Describe("Authenticating", func() {
+		It("should send the API token instead of the user email", func() {
+			m = lib.NewMarathon(&lib.Config{
+				Timeout:   config.Timeout,
+				URL:       config.URL,
+				UserEmail: config.UserEmail,
+				APIToken:  "mrt_token",
+				AppID:     config.AppID,
+			})
+			url := fmt.Sprintf("http://marathon/apps/%s/jobs", appID)
+			httpmock.RegisterResponder(
+				"GET", url,
+				func(req *http.Request) (*http.Response, error) {
+					Expect(req.Header.Get("Authorization")).To(Equal("Bearer mrt_token"))
+					Expect(req.Header.Get("x-forwarded-email")).To(BeEmpty())
+					return httpmock.NewStringResponse(200, "[]"), nil
+				})
+
+			_, err := m.ListJobs(ctx, template)
+
+			Expect(err).To(BeNil())
+		})
+	})
 })
@@ -33,6 +33,7 @@ type APNSMessage struct {
 	DeviceToken string                 `json:"DeviceToken"`
 	Payload     APNSPayloadContent     `json:"Payload"`
 	PushExpiry  int64                  `json:"push_expiry"`
+	CollapseID  string                 `json:"collapse_id,omitempty"`
 	Metadata    map[string]interface{} `json:"metadata"`
 }
 
@@ -40,6 +41,7 @@ type APNSMessage struct {
 type APNSPayloadContent struct {
 	Aps          map[string]interface{} `json:"aps"`
 	M            map[string]interface{} `json:"m,omitempty"`
+	MediaURL     string                 `json:"media-url,omitempty"`
 	TemplateName string                 `json:"templateName"`
 }
 
@@ -69,6 +71,30 @@ func NewAPNSMessage(deviceToken string, pushExpiry int64, aps, messageMetadata m
 	return msg
 }
 
+// SetOptions sets the push options that apns supports, the media url is sent next to the aps
+// dictionary with mutable-content, so a notification service extension can download it, and the
+// collapse key is sent as the apns-collapse-id
+func (m *APNSMessage) SetOptions(options *PushOptions) {
+	if options == nil {
+		return
+	}
+	aps := m.Payload.Aps
+	if options.MediaURL != "" {
+		aps["mutable-content"] = 1
+		m.Payload.MediaURL = options.MediaURL
+	}
+	if options.ActionCategory != "" {
+		aps["category"] = options.ActionCategory
+	}
+	if options.ThreadID != "" {
+		aps["thread-id"] = options.ThreadID
+	}
+	if options.InterruptionLevel != "" {
+		aps["interruption-level"] = options.InterruptionLevel
+	}
+	m.CollapseID = options.CollapseKey
+}
+
 //ToJSON returns the serialized message
 func (m *APNSMessage) ToJSON() (string, error) {
 	b, err := json.Marshal(m)
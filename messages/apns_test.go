@@ -62,4 +62,41 @@ var _ = Describe("APNS Message", func() {
 			Expect(msg.Metadata).To(BeEquivalentTo(empty))
 		})
 	})
+
+	Describe("Setting push options", func() {
+		It("should set the options apns supports", func() {
+			msg := messages.NewAPNSMessage("deviceToken", 357, map[string]interface{}{"alert": "hi"}, nil, nil, "tplname")
+			msg.SetOptions(&messages.PushOptions{
+				MediaURL:          "https://cdn.example.com/reward.png",
+				ActionCategory:    "REWARD",
+				ThreadID:          "rewards",
+				InterruptionLevel: "time-sensitive",
+				Priority:          "high",
+				CollapseKey:       "daily-reward",
+				ChannelID:         "rewards",
+				AnalyticsLabel:    "reward",
+			})
+
+			Expect(msg.Payload.Aps).To(Equal(map[string]interface{}{
+				"alert":              "hi",
+				"mutable-content":    1,
+				"category":           "REWARD",
+				"thread-id":          "rewards",
+				"interruption-level": "time-sensitive",
+			}))
+			Expect(msg.Payload.MediaURL).To(Equal("https://cdn.example.com/reward.png"))
+			Expect(msg.CollapseID).To(Equal("daily-reward"))
+		})
+
+		It("should not change the message if options are nil", func() {
+			msg := messages.NewAPNSMessage("deviceToken", 357, map[string]interface{}{"alert": "hi"}, nil, nil, "tplname")
+			msg.SetOptions(nil)
+
+			msgStr, err := msg.ToJSON()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(msgStr).NotTo(ContainSubstring("media-url"))
+			Expect(msgStr).NotTo(ContainSubstring("collapse_id"))
+			Expect(msg.Payload.Aps).To(Equal(map[string]interface{}{"alert": "hi"}))
+		})
+	})
 })
@@ -32,6 +32,9 @@ import (
 type GCMMessage struct {
 	To                     string                 `json:"to"`
 	Data                   map[string]interface{} `json:"data"`
+	Priority               string                 `json:"priority,omitempty"`
+	CollapseKey            string                 `json:"collapse_key,omitempty"`
+	FCMOptions             *GCMFCMOptions         `json:"fcm_options,omitempty"`
 	TimeToLive             int64                  `json:"time_to_live,omitempty"`
 	DelayWhileIdle         bool                   `json:"delay_while_idle,omitempty"`
 	DeliveryReceiptRequest bool                   `json:"delivery_receipt_requested,omitempty"`
@@ -40,6 +43,11 @@ type GCMMessage struct {
 	Metadata               map[string]interface{} `json:"metadata"`
 }
 
+// GCMFCMOptions are the fcm options of a gcm message
+type GCMFCMOptions struct {
+	AnalyticsLabel string `json:"analytics_label,omitempty"`
+}
+
 // NewGCMMessage builds a new GCM Message
 func NewGCMMessage(to string, data, messageMetadata map[string]interface{}, pushMetadata map[string]interface{}, timeToLive int64, templateName string) *GCMMessage {
 	if data == nil {
@@ -52,19 +60,38 @@ func NewGCMMessage(to string, data, messageMetadata map[string]interface{}, push
 	}
 
 	msg := &GCMMessage{
-		To:                     to,
-		Data:                   data,
-		TimeToLive:             timeToLive,
-		DryRun:                 false,
-		DeliveryReceiptRequest: false,
-		DelayWhileIdle:         false,
-		MessageID:              "",
-		Metadata:               pushMetadata,
+		To:         to,
+		Data:       data,
+		TimeToLive: timeToLive,
+		Metadata:   pushMetadata,
 	}
 
 	return msg
 }
 
+// SetOptions sets the push options that gcm supports, the media url, action category and channel
+// are sent in the data, named as the fields of the fcm notification block, so the message stays a
+// data message that is handled by the app
+func (m *GCMMessage) SetOptions(options *PushOptions) {
+	if options == nil {
+		return
+	}
+	if options.MediaURL != "" {
+		m.Data["image"] = options.MediaURL
+	}
+	if options.ActionCategory != "" {
+		m.Data["click_action"] = options.ActionCategory
+	}
+	if options.ChannelID != "" {
+		m.Data["android_channel_id"] = options.ChannelID
+	}
+	m.Priority = options.Priority
+	m.CollapseKey = options.CollapseKey
+	if options.AnalyticsLabel != "" {
+		m.FCMOptions = &GCMFCMOptions{AnalyticsLabel: options.AnalyticsLabel}
+	}
+}
+
 //ToJSON returns the serialized message
 func (m *GCMMessage) ToJSON() (string, error) {
 	b, err := json.Marshal(m)
@@ -109,4 +109,81 @@ var _ = Describe("GCM Message", func() {
 			Expect(msgStr).NotTo(ContainSubstring("time_to_live"))
 		})
 	})
+
+	Describe("Setting push options", func() {
+		It("should set the options gcm supports", func() {
+			msg := messages.NewGCMMessage("to", map[string]interface{}{"alert": "hi"}, nil, nil, 357, "my-template")
+			msg.SetOptions(&messages.PushOptions{
+				MediaURL:          "https://cdn.example.com/reward.png",
+				ActionCategory:    "REWARD",
+				ThreadID:          "rewards",
+				InterruptionLevel: "time-sensitive",
+				Priority:          "high",
+				CollapseKey:       "daily-reward",
+				ChannelID:         "rewards",
+				AnalyticsLabel:    "reward",
+			})
+
+			Expect(msg.Priority).To(Equal("high"))
+			Expect(msg.CollapseKey).To(Equal("daily-reward"))
+			Expect(msg.FCMOptions).To(Equal(&messages.GCMFCMOptions{AnalyticsLabel: "reward"}))
+			Expect(msg.Data).To(Equal(map[string]interface{}{
+				"alert":              "hi",
+				"templateName":       "my-template",
+				"image":              "https://cdn.example.com/reward.png",
+				"click_action":       "REWARD",
+				"android_channel_id": "rewards",
+			}))
+		})
+
+		It("should keep a data message when the media url, category and channel are set", func() {
+			msg := messages.NewGCMMessage("to", map[string]interface{}{"alert": "hi"}, nil, nil, 357, "my-template")
+			msg.SetOptions(&messages.PushOptions{
+				MediaURL:       "https://cdn.example.com/reward.png",
+				ActionCategory: "REWARD",
+				ChannelID:      "rewards",
+				Priority:       "high",
+				CollapseKey:    "daily-reward",
+				AnalyticsLabel: "reward",
+			})
+
+			msgStr, err := msg.ToJSON()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(msgStr).To(MatchJSON(`{
+				"to": "to",
+				"data": {
+					"alert": "hi",
+					"templateName": "my-template",
+					"image": "https://cdn.example.com/reward.png",
+					"click_action": "REWARD",
+					"android_channel_id": "rewards"
+				},
+				"priority": "high",
+				"collapse_key": "daily-reward",
+				"fcm_options": {"analytics_label": "reward"},
+				"time_to_live": 357,
+				"dry_run": false,
+				"message_id": "",
+				"metadata": null
+			}`))
+		})
+
+		It("should only set the options that are given", func() {
+			msg := messages.NewGCMMessage("to", nil, nil, nil, 357, "my-template")
+			msg.SetOptions(&messages.PushOptions{Priority: "normal", CollapseKey: "daily-reward"})
+
+			msgStr, err := msg.ToJSON()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(msgStr).To(MatchJSON(`{
+				"to": "to",
+				"data": {"templateName": "my-template"},
+				"priority": "normal",
+				"collapse_key": "daily-reward",
+				"time_to_live": 357,
+				"dry_run": false,
+				"message_id": "",
+				"metadata": null
+			}`))
+		})
+	})
 })
@@ -0,0 +1,69 @@
+/*
+ * Copyright (c) 2026 TFG Co <backend@tfgco.com>
+ * Author: TFG Co <backend@tfgco.com>
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy of
+ * this software and associated documentation files (the "Software"), to deal in
+ * the Software without restriction, including without limitation the rights to
+ * use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+ * the Software, and to permit persons to whom the Software is furnished to do so,
+ * subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+ * FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+ * COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+ * IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+ * CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+ */
+package messages
+
+// PushOptions are the delivery options of a push that are not in its template body, they are
+// mapped to the fields of each service message that support them
+type PushOptions struct {
+	MediaURL          string `json:"mediaUrl,omitempty"`
+	ActionCategory    string `json:"actionCategory,omitempty"`
+	ThreadID          string `json:"threadId,omitempty"`
+	InterruptionLevel string `json:"interruptionLevel,omitempty"`
+	Priority          string `json:"priority,omitempty"`
+	CollapseKey       string `json:"collapseKey,omitempty"`
+	ChannelID         string `json:"channelId,omitempty"`
+	AnalyticsLabel    string `json:"analyticsLabel,omitempty"`
+}
+
+// Merge returns the options with the fields that are set in override replaced, either of them
+// can be nil
+func (o *PushOptions) Merge(override *PushOptions) *PushOptions {
+	if o == nil && override == nil {
+		return nil
+	}
+	merged := PushOptions{}
+	if o != nil {
+		merged = *o
+	}
+	if override == nil {
+		return &merged
+	}
+	fields := []struct {
+		value    *string
+		override string
+	}{
+		{&merged.MediaURL, override.MediaURL},
+		{&merged.ActionCategory, override.ActionCategory},
+		{&merged.ThreadID, override.ThreadID},
+		{&merged.InterruptionLevel, override.InterruptionLevel},
+		{&merged.Priority, override.Priority},
+		{&merged.CollapseKey, override.CollapseKey},
+		{&merged.ChannelID, override.ChannelID},
+		{&merged.AnalyticsLabel, override.AnalyticsLabel},
+	}
+	for _, field := range fields {
+		if field.override != "" {
+			*field.value = field.override
+		}
+	}
+	return &merged
+}
@@ -0,0 +1,52 @@
+/*
+ * Copyright (c) 2026 TFG Co <backend@tfgco.com>
+ * Author: TFG Co <backend@tfgco.com>
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy of
+ * this software and associated documentation files (the "Software"), to deal in
+ * the Software without restriction, including without limitation the rights to
+ * use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+ * the Software, and to permit persons to whom the Software is furnished to do so,
+ * subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+ * FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+ * COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+ * IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+ * CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+ */
+package messages_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/topfreegames/marathon/messages"
+)
+
+var _ = Describe("Push Options", func() {
+	Describe("Merging options", func() {
+		It("should replace the fields that are set in the override", func() {
+			options := &messages.PushOptions{Priority: "high", ThreadID: "rewards", CollapseKey: "daily"}
+			merged := options.Merge(&messages.PushOptions{Priority: "normal", ChannelID: "promos"})
+
+			Expect(merged).To(Equal(&messages.PushOptions{
+				Priority:    "normal",
+				ThreadID:    "rewards",
+				CollapseKey: "daily",
+				ChannelID:   "promos",
+			}))
+			Expect(options.Priority).To(Equal("high"))
+		})
+
+		It("should return the options that are not nil", func() {
+			var options *messages.PushOptions
+			Expect(options.Merge(nil)).To(BeNil())
+			Expect(options.Merge(&messages.PushOptions{ThreadID: "a"})).To(Equal(&messages.PushOptions{ThreadID: "a"}))
+			Expect((&messages.PushOptions{ThreadID: "a"}).Merge(nil)).To(Equal(&messages.PushOptions{ThreadID: "a"}))
+		})
+	})
+})
@@ -0,0 +1,44 @@
+-- +goose Up
+-- SQL in section 'Up' is executed when this migration is applied
+
+CREATE TABLE "schedules" (
+  "id" uuid DEFAULT uuid_generate_v4(),
+  "cron_expression" text NOT NULL,
+  "timezone" text NOT NULL DEFAULT 'UTC',
+  "ends_at" bigint NOT NULL DEFAULT 0,
+  "max_runs" integer NOT NULL DEFAULT 0,
+  "total_runs" integer NOT NULL DEFAULT 0,
+  "next_run_at" bigint NOT NULL DEFAULT 0,
+  "last_run_at" bigint NOT NULL DEFAULT 0,
+  "paused" boolean NOT NULL DEFAULT false,
+  "template_name" text NOT NULL,
+  "service" text NOT NULL,
+  "context" JSONB NOT NULL DEFAULT '{}'::JSONB,
+  "filters" JSONB NOT NULL DEFAULT '{}'::JSONB,
+  "metadata" JSONB NOT NULL DEFAULT '{}'::JSONB,
+  "csv_path" text,
+  "control_group" real NOT NULL DEFAULT 0,
+  "expires_in" bigint NOT NULL DEFAULT 0,
+  "created_by" text NOT NULL,
+  "app_id" uuid NOT NULL,
+  "created_at" bigint,
+  "updated_at" bigint,
+  PRIMARY KEY ("id")
+);
+
+CREATE INDEX schedules_next_run_at ON "schedules"(next_run_at) WHERE paused = false;
+
+ALTER TABLE "schedules"
+ADD CONSTRAINT schedules_app_id_apps_id_foreign
+FOREIGN KEY (app_id)
+REFERENCES apps(id)
+ON DELETE CASCADE
+ON UPDATE CASCADE;
+
+ALTER TABLE "jobs" ADD COLUMN schedule_id uuid;
+
+-- +goose Down
+-- SQL section 'Down' is executed when this migration is rolled back
+
+ALTER TABLE "jobs" DROP COLUMN schedule_id;
+DROP TABLE "schedules";
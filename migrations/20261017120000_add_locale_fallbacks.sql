@@ -0,0 +1,11 @@
+-- +goose Up
+-- SQL in section 'Up' is executed when this migration is applied
+
+ALTER TABLE "apps" ADD COLUMN locale_fallbacks JSONB DEFAULT '{}'::JSONB;
+ALTER TABLE "jobs" ADD COLUMN locale_stats JSONB NOT NULL DEFAULT '{}'::JSONB;
+
+-- +goose Down
+-- SQL section 'Down' is executed when this migration is rolled back
+
+ALTER TABLE "jobs" DROP COLUMN locale_stats;
+ALTER TABLE "apps" DROP COLUMN locale_fallbacks;
@@ -0,0 +1,13 @@
+-- +goose Up
+-- SQL in section 'Up' is executed when this migration is applied
+
+ALTER TABLE "jobs" ADD COLUMN template_weights JSONB NOT NULL DEFAULT '{}'::JSONB;
+ALTER TABLE "jobs" ADD COLUMN variant_feedbacks JSONB NOT NULL DEFAULT '{}'::JSONB;
+ALTER TABLE "jobs" ADD COLUMN variant_csv_paths JSONB NOT NULL DEFAULT '{}'::JSONB;
+
+-- +goose Down
+-- SQL section 'Down' is executed when this migration is rolled back
+
+ALTER TABLE "jobs" DROP COLUMN variant_csv_paths;
+ALTER TABLE "jobs" DROP COLUMN variant_feedbacks;
+ALTER TABLE "jobs" DROP COLUMN template_weights;
@@ -0,0 +1,11 @@
+-- +goose Up
+-- SQL in section 'Up' is executed when this migration is applied
+
+ALTER TABLE "jobs" ADD COLUMN treated_csv_path TEXT;
+ALTER TABLE "jobs" ADD COLUMN uplift_report JSONB;
+
+-- +goose Down
+-- SQL section 'Down' is executed when this migration is rolled back
+
+ALTER TABLE "jobs" DROP COLUMN uplift_report;
+ALTER TABLE "jobs" DROP COLUMN treated_csv_path;
@@ -0,0 +1,17 @@
+-- +goose Up
+-- SQL in section 'Up' is executed when this migration is applied
+
+ALTER TABLE "apps" ADD COLUMN holdout_percentage DOUBLE PRECISION NOT NULL DEFAULT 0;
+ALTER TABLE "apps" ADD COLUMN holdout_salt TEXT NOT NULL DEFAULT '';
+ALTER TABLE "jobs" ADD COLUMN use_holdout BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE "jobs" ADD COLUMN holdout_users INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE "schedules" ADD COLUMN use_holdout BOOLEAN NOT NULL DEFAULT FALSE;
+
+-- +goose Down
+-- SQL section 'Down' is executed when this migration is rolled back
+
+ALTER TABLE "schedules" DROP COLUMN use_holdout;
+ALTER TABLE "jobs" DROP COLUMN holdout_users;
+ALTER TABLE "jobs" DROP COLUMN use_holdout;
+ALTER TABLE "apps" DROP COLUMN holdout_salt;
+ALTER TABLE "apps" DROP COLUMN holdout_percentage;
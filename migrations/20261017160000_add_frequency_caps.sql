@@ -0,0 +1,13 @@
+-- +goose Up
+-- SQL in section 'Up' is executed when this migration is applied
+
+ALTER TABLE "apps" ADD COLUMN frequency_caps JSONB;
+ALTER TABLE "templates" ADD COLUMN category TEXT;
+ALTER TABLE "jobs" ADD COLUMN capped_users INTEGER NOT NULL DEFAULT 0;
+
+-- +goose Down
+-- SQL section 'Down' is executed when this migration is rolled back
+
+ALTER TABLE "jobs" DROP COLUMN capped_users;
+ALTER TABLE "templates" DROP COLUMN category;
+ALTER TABLE "apps" DROP COLUMN frequency_caps;
@@ -0,0 +1,11 @@
+-- +goose Up
+-- SQL in section 'Up' is executed when this migration is applied
+
+ALTER TABLE "jobs" ADD COLUMN max_pushes_per_second INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE "jobs" ADD COLUMN spread_over BIGINT NOT NULL DEFAULT 0;
+
+-- +goose Down
+-- SQL section 'Down' is executed when this migration is rolled back
+
+ALTER TABLE "jobs" DROP COLUMN spread_over;
+ALTER TABLE "jobs" DROP COLUMN max_pushes_per_second;
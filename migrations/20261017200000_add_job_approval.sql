@@ -0,0 +1,11 @@
+-- +goose Up
+-- SQL in section 'Up' is executed when this migration is applied
+
+ALTER TABLE "apps" ADD COLUMN requires_approval BOOLEAN NOT NULL DEFAULT false;
+ALTER TABLE "users" ADD COLUMN is_approver BOOLEAN NOT NULL DEFAULT false;
+
+-- +goose Down
+-- SQL section 'Down' is executed when this migration is rolled back
+
+ALTER TABLE "users" DROP COLUMN is_approver;
+ALTER TABLE "apps" DROP COLUMN requires_approval;
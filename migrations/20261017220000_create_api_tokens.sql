@@ -0,0 +1,30 @@
+-- +goose Up
+-- SQL in section 'Up' is executed when this migration is applied
+
+CREATE TABLE "api_tokens" (
+  "id" uuid DEFAULT uuid_generate_v4(),
+  "user_id" uuid NOT NULL,
+  "name" text NOT NULL,
+  "token_hash" text NOT NULL,
+  "scopes" text[],
+  "expires_at" bigint,
+  "last_used_at" bigint,
+  "revoked_at" bigint,
+  "created_by" text NOT NULL,
+  "created_at" bigint,
+  PRIMARY KEY ("id")
+);
+
+CREATE UNIQUE INDEX uix_api_tokens_token_hash ON "api_tokens"(token_hash);
+
+ALTER TABLE "api_tokens"
+ADD CONSTRAINT api_tokens_user_id_users_id_foreign
+FOREIGN KEY (user_id)
+REFERENCES users(id)
+ON DELETE CASCADE
+ON UPDATE CASCADE;
+
+-- +goose Down
+-- SQL section 'Down' is executed when this migration is rolled back
+
+DROP TABLE "api_tokens";
@@ -0,0 +1,30 @@
+-- +goose Up
+-- SQL in section 'Up' is executed when this migration is applied
+
+CREATE TABLE "audit_logs" (
+  "id" uuid DEFAULT uuid_generate_v4(),
+  "actor" text NOT NULL,
+  "method" text NOT NULL,
+  "route" text NOT NULL,
+  "path" text NOT NULL,
+  "app_id" uuid,
+  "target_ids" JSONB NOT NULL DEFAULT '{}'::JSONB,
+  "status" integer NOT NULL,
+  "request_id" text NOT NULL,
+  "diff" JSONB NOT NULL DEFAULT '{}'::JSONB,
+  "created_at" bigint NOT NULL,
+  PRIMARY KEY ("id")
+);
+
+CREATE INDEX idx_audit_logs_app_id_created_at ON "audit_logs"(app_id, created_at);
+CREATE INDEX idx_audit_logs_actor_created_at ON "audit_logs"(actor, created_at);
+CREATE INDEX idx_audit_logs_created_at ON "audit_logs"(created_at);
+
+-- audit logs are append-only
+CREATE RULE audit_logs_no_update AS ON UPDATE TO "audit_logs" DO INSTEAD NOTHING;
+CREATE RULE audit_logs_no_delete AS ON DELETE TO "audit_logs" DO INSTEAD NOTHING;
+
+-- +goose Down
+-- SQL section 'Down' is executed when this migration is rolled back
+
+DROP TABLE "audit_logs";
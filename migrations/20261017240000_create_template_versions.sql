@@ -0,0 +1,35 @@
+-- +goose Up
+-- SQL in section 'Up' is executed when this migration is applied
+
+ALTER TABLE "templates" ADD COLUMN version integer NOT NULL DEFAULT 1;
+
+-- versions are kept after their template is deleted, jobs pinned to them still render them
+CREATE TABLE "template_versions" (
+  "id" uuid DEFAULT uuid_generate_v4(),
+  "template_id" uuid NOT NULL,
+  "app_id" uuid NOT NULL,
+  "version" integer NOT NULL,
+  "name" text NOT NULL,
+  "locale" text NOT NULL,
+  "category" text,
+  "defaults" JSONB NOT NULL DEFAULT '{}'::JSONB,
+  "body" JSONB NOT NULL DEFAULT '{}'::JSONB,
+  "created_by" text NOT NULL,
+  "created_at" bigint,
+  PRIMARY KEY ("id")
+);
+
+CREATE UNIQUE INDEX uix_template_versions_template_id_version ON "template_versions"(template_id, version);
+
+INSERT INTO "template_versions" (template_id, app_id, version, name, locale, category, defaults, body, created_by, created_at)
+SELECT id, app_id, version, name, locale, category, defaults, body, created_by, coalesce(updated_at, created_at)
+FROM "templates";
+
+ALTER TABLE "jobs" ADD COLUMN template_versions JSONB;
+
+-- +goose Down
+-- SQL section 'Down' is executed when this migration is rolled back
+
+ALTER TABLE "jobs" DROP COLUMN template_versions;
+DROP TABLE "template_versions";
+ALTER TABLE "templates" DROP COLUMN version;
@@ -0,0 +1,11 @@
+-- +goose Up
+-- SQL in section 'Up' is executed when this migration is applied
+
+ALTER TABLE "templates" ADD COLUMN service TEXT;
+ALTER TABLE "template_versions" ADD COLUMN service TEXT;
+
+-- +goose Down
+-- SQL section 'Down' is executed when this migration is rolled back
+
+ALTER TABLE "template_versions" DROP COLUMN service;
+ALTER TABLE "templates" DROP COLUMN service;
@@ -0,0 +1,13 @@
+-- +goose Up
+-- SQL in section 'Up' is executed when this migration is applied
+
+ALTER TABLE "templates" ADD COLUMN push_options JSONB;
+ALTER TABLE "template_versions" ADD COLUMN push_options JSONB;
+ALTER TABLE "jobs" ADD COLUMN push_options JSONB;
+
+-- +goose Down
+-- SQL section 'Down' is executed when this migration is rolled back
+
+ALTER TABLE "jobs" DROP COLUMN push_options;
+ALTER TABLE "template_versions" DROP COLUMN push_options;
+ALTER TABLE "templates" DROP COLUMN push_options;
@@ -0,0 +1,110 @@
+/*
+ * Copyright (c) 2026 TFG Co <backend@tfgco.com>
+ * Author: TFG Co <backend@tfgco.com>
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy of
+ * this software and associated documentation files (the "Software"), to deal in
+ * the Software without restriction, including without limitation the rights to
+ * use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+ * the Software, and to permit persons to whom the Software is furnished to do so,
+ * subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+ * FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+ * COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+ * IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+ * CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+ */
+
+package model
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"time"
+
+	"github.com/asaskevich/govalidator"
+	"github.com/labstack/echo/v4"
+	"github.com/satori/go.uuid"
+)
+
+// APITokenPrefix is the prefix of every API token, it makes leaked tokens easy to find
+const APITokenPrefix = "mrt_"
+
+// APIToken is a token that authenticates the requests of a user, its scopes are the roles it
+// can use, so a token never does more than its user and its scopes allow
+type APIToken struct {
+	tableName struct{} `sql:"api_tokens,alias:api_token"`
+
+	ID         uuid.UUID `sql:",pk" json:"id"`
+	UserID     uuid.UUID `sql:",notnull" json:"userId"`
+	Name       string    `json:"name"`
+	TokenHash  string    `json:"-"`
+	Token      string    `sql:"-" json:"token,omitempty"`
+	Scopes     []string  `pg:",array" json:"scopes"`
+	ExpiresAt  int64     `json:"expiresAt"`
+	LastUsedAt int64     `json:"lastUsedAt"`
+	RevokedAt  int64     `json:"revokedAt"`
+	CreatedBy  string    `json:"createdBy"`
+	CreatedAt  int64     `json:"createdAt"`
+}
+
+// Validate implementation of the InputValidation interface
+func (t *APIToken) Validate(c echo.Context) error {
+	valid := govalidator.StringLength(t.Name, "1", "255")
+	if !valid {
+		return InvalidField("name")
+	}
+
+	valid = len(t.Scopes) > 0
+	for _, scope := range t.Scopes {
+		valid = valid && roles[scope]
+	}
+	if !valid {
+		return InvalidField("scopes")
+	}
+
+	valid = t.ExpiresAt == 0 || time.Now().UnixNano() < t.ExpiresAt
+	if !valid {
+		return InvalidField("expiresAt")
+	}
+	return nil
+}
+
+// GenerateToken sets a new random token, only its hash is stored so the token is only known
+// when it is created
+func (t *APIToken) GenerateToken() error {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return err
+	}
+	t.Token = APITokenPrefix + hex.EncodeToString(b)
+	t.TokenHash = HashAPIToken(t.Token)
+	return nil
+}
+
+// HashAPIToken returns the hash stored for the token
+func HashAPIToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// IsActive returns true if the token was not revoked and has not expired
+func (t *APIToken) IsActive(now time.Time) bool {
+	return t.RevokedAt == 0 && (t.ExpiresAt == 0 || now.UnixNano() < t.ExpiresAt)
+}
+
+// HasScope returns true if one of the token scopes grants the role
+func (t *APIToken) HasScope(role string) bool {
+	for _, scope := range t.Scopes {
+		if grantsRole(scope, role) {
+			return true
+		}
+	}
+	return false
+}
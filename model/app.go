@@ -30,12 +30,18 @@ import (
 
 // App is the app model struct
 type App struct {
-	ID        uuid.UUID `sql:",pk" json:"id"`
-	Name      string    `json:"name"`
-	BundleID  string    `json:"bundleId"`
-	CreatedBy string    `json:"createdBy"`
-	CreatedAt int64     `json:"createdAt"`
-	UpdatedAt int64     `json:"updatedAt"`
+	ID                uuid.UUID           `sql:",pk" json:"id"`
+	Name              string              `json:"name"`
+	BundleID          string              `json:"bundleId"`
+	LocaleFallbacks   map[string][]string `json:"localeFallbacks"`
+	HoldoutPercentage float64             `sql:",notnull" json:"holdoutPercentage"`
+	HoldoutSalt       string              `json:"holdoutSalt"`
+	FrequencyCaps     []FrequencyCap      `json:"frequencyCaps"`
+	QuietHours        *QuietHours         `json:"quietHours"`
+	RequiresApproval  bool                `sql:",notnull" json:"requiresApproval"`
+	CreatedBy         string              `json:"createdBy"`
+	CreatedAt         int64               `json:"createdAt"`
+	UpdatedAt         int64               `json:"updatedAt"`
 }
 
 // Validate implementation of the InputValidation interface
@@ -48,6 +54,22 @@ func (a *App) Validate(c echo.Context) error {
 	if !valid {
 		return InvalidField("bundleId")
 	}
+	valid = validateLocaleFallbacks(a.LocaleFallbacks)
+	if !valid {
+		return InvalidField("localeFallbacks")
+	}
+	valid = a.HoldoutPercentage >= 0 && a.HoldoutPercentage < 1
+	if !valid {
+		return InvalidField("holdoutPercentage")
+	}
+	valid = validateFrequencyCaps(a.FrequencyCaps)
+	if !valid {
+		return InvalidField("frequencyCaps")
+	}
+	valid = validateQuietHours(a.QuietHours)
+	if !valid {
+		return InvalidField("quietHours")
+	}
 	valid = govalidator.IsEmail(a.CreatedBy)
 	if !valid {
 		return InvalidField("createdBy")
@@ -0,0 +1,83 @@
+/*
+ * Copyright (c) 2026 TFG Co <backend@tfgco.com>
+ * Author: TFG Co <backend@tfgco.com>
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy of
+ * this software and associated documentation files (the "Software"), to deal in
+ * the Software without restriction, including without limitation the rights to
+ * use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+ * the Software, and to permit persons to whom the Software is furnished to do so,
+ * subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+ * FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+ * COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+ * IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+ * CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+ */
+
+package model
+
+import (
+	"github.com/asaskevich/govalidator"
+	"github.com/labstack/echo/v4"
+)
+
+// AudienceEstimate is the payload used to estimate how many tokens a job would reach
+type AudienceEstimate struct {
+	Service      string                 `json:"service"`
+	Filters      map[string]interface{} `json:"filters"`
+	CSVPath      string                 `json:"csvPath"`
+	ControlGroup float64                `json:"controlGroup"`
+}
+
+// Validate implementation of the InputValidation interface
+func (e *AudienceEstimate) Validate(c echo.Context) error {
+	valid := e.Service == "" || govalidator.StringMatches(e.Service, "^(apns|gcm)$")
+	if !valid {
+		return InvalidField("service")
+	}
+
+	valid = e.ControlGroup >= 0 && e.ControlGroup < 1
+	if !valid {
+		return InvalidField("controlGroup")
+	}
+
+	valid = !(len(e.Filters) != 0 && !govalidator.IsNull(e.CSVPath))
+	if !valid {
+		return InvalidField("filters or csvPath must exist, not both")
+	}
+
+	if !govalidator.IsNull(e.CSVPath) && govalidator.Contains(e.CSVPath, "s3://") {
+		return InvalidField("csvPath: cannot contain s3 protocol, just the bucket path")
+	}
+	return nil
+}
+
+// Services returns the services that should be estimated, both if none was given
+func (e *AudienceEstimate) Services() []string {
+	if e.Service != "" {
+		return []string{e.Service}
+	}
+	return []string{"apns", "gcm"}
+}
+
+// Job returns a job with the estimate filters or csv, it is never saved
+func (e *AudienceEstimate) Job(app *App, service string) *Job {
+	filters := map[string]interface{}{}
+	for k, v := range e.Filters {
+		filters[k] = v
+	}
+	return &Job{
+		App:          *app,
+		AppID:        app.ID,
+		Service:      service,
+		Filters:      filters,
+		CSVPath:      e.CSVPath,
+		ControlGroup: e.ControlGroup,
+	}
+}
@@ -0,0 +1,69 @@
+/*
+ * Copyright (c) 2026 TFG Co <backend@tfgco.com>
+ * Author: TFG Co <backend@tfgco.com>
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy of
+ * this software and associated documentation files (the "Software"), to deal in
+ * the Software without restriction, including without limitation the rights to
+ * use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+ * the Software, and to permit persons to whom the Software is furnished to do so,
+ * subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+ * FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+ * COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+ * IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+ * CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+ */
+
+package model
+
+import (
+	"reflect"
+
+	"github.com/satori/go.uuid"
+)
+
+// AuditLog is an entry of the append-only log of the API calls that change apps and users
+type AuditLog struct {
+	tableName struct{} `sql:"audit_logs,alias:audit_log"`
+
+	ID        uuid.UUID               `sql:",pk" json:"id"`
+	Actor     string                  `json:"actor"`
+	Method    string                  `json:"method"`
+	Route     string                  `json:"route"`
+	Path      string                  `json:"path"`
+	AppID     uuid.UUID               `json:"appId"`
+	TargetIDs map[string]string       `json:"targetIds"`
+	Status    int                     `json:"status"`
+	RequestID string                  `json:"requestId"`
+	Diff      map[string]*AuditChange `json:"diff"`
+	CreatedAt int64                   `json:"createdAt"`
+}
+
+// AuditChange is the value of a field before and after an API call, a nil value means the
+// field did not exist, e.g. before a creation or after a deletion
+type AuditChange struct {
+	Before interface{} `json:"before"`
+	After  interface{} `json:"after"`
+}
+
+// DiffAudit returns the fields that differ between the before and after snapshots
+func DiffAudit(before, after map[string]interface{}) map[string]*AuditChange {
+	diff := map[string]*AuditChange{}
+	for field, value := range before {
+		if !reflect.DeepEqual(value, after[field]) {
+			diff[field] = &AuditChange{Before: value, After: after[field]}
+		}
+	}
+	for field, value := range after {
+		if _, ok := before[field]; !ok {
+			diff[field] = &AuditChange{After: value}
+		}
+	}
+	return diff
+}
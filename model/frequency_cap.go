@@ -0,0 +1,59 @@
+/*
+ * Copyright (c) 2026 TFG Co <backend@tfgco.com>
+ * Author: TFG Co <backend@tfgco.com>
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy of
+ * this software and associated documentation files (the "Software"), to deal in
+ * the Software without restriction, including without limitation the rights to
+ * use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+ * the Software, and to permit persons to whom the Software is furnished to do so,
+ * subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+ * FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+ * COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+ * IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+ * CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+ */
+
+package model
+
+import (
+	"github.com/asaskevich/govalidator"
+)
+
+// FrequencyCap limits the pushes a user receives from the app in a rolling window, a cap
+// with a category only counts the pushes of the templates of that category
+type FrequencyCap struct {
+	Category  string `json:"category"`
+	MaxPushes int    `json:"maxPushes"`
+	Window    int64  `json:"window"`
+}
+
+// FrequencyCapsFor returns the caps of the app that count the pushes of the template category
+func (a *App) FrequencyCapsFor(category string) []FrequencyCap {
+	caps := []FrequencyCap{}
+	for _, frequencyCap := range a.FrequencyCaps {
+		if frequencyCap.Category == "" || frequencyCap.Category == category {
+			caps = append(caps, frequencyCap)
+		}
+	}
+	return caps
+}
+
+func validateCategory(category string) bool {
+	return govalidator.StringMatches(category, "^[a-z0-9_-]{0,255}$")
+}
+
+func validateFrequencyCaps(caps []FrequencyCap) bool {
+	for _, frequencyCap := range caps {
+		if frequencyCap.MaxPushes <= 0 || frequencyCap.Window <= 0 || !validateCategory(frequencyCap.Category) {
+			return false
+		}
+	}
+	return true
+}
@@ -41,11 +41,52 @@ func (j *Job) GetJobInfoAndApp(db interfaces.DB) error {
 	return db.Model(j).Column("job.*", "App").Where("job.id = ?", j.ID).Select()
 }
 
-// GetJobTemplatesByNameAndLocale ...
+// PinTemplateVersions sets the current versions of the job templates, so the job is sent with
+// them even if the templates change
+func (j *Job) PinTemplateVersions(db interfaces.DB) error {
+	var templates []Template
+	err := db.Model(&templates).Column("id", "version").Where(
+		"app_id = ? AND name IN (?)",
+		j.AppID,
+		pg.In(strings.Split(j.TemplateName, ",")),
+	).Select()
+	if err != nil {
+		return err
+	}
+	j.TemplateVersions = map[string]int{}
+	for _, tpl := range templates {
+		j.TemplateVersions[tpl.ID.String()] = tpl.Version
+	}
+	return nil
+}
+
+func (j *Job) getPinnedTemplates(db interfaces.DB) ([]Template, error) {
+	ids := make([]string, 0, len(j.TemplateVersions))
+	for id := range j.TemplateVersions {
+		ids = append(ids, id)
+	}
+	var versions []TemplateVersion
+	err := db.Model(&versions).Where("template_id IN (?)", pg.In(ids)).Select()
+	if err != nil {
+		return nil, err
+	}
+	var templates []Template
+	for _, version := range versions {
+		if j.TemplateVersions[version.TemplateID.String()] == version.Version {
+			templates = append(templates, version.Template())
+		}
+	}
+	return templates, nil
+}
+
+// GetJobTemplatesByNameAndLocale returns the job templates, the pinned versions if the job has
+// them
 func (j *Job) GetJobTemplatesByNameAndLocale(db interfaces.DB) (map[string]map[string]Template, error) {
 	var templates []Template
 	var err error
-	if len(strings.Split(j.TemplateName, ",")) > 1 {
+	if len(j.TemplateVersions) > 0 {
+		templates, err = j.getPinnedTemplates(db)
+	} else if len(strings.Split(j.TemplateName, ",")) > 1 {
 		err = db.Model(&templates).Where(
 			"app_id = ? AND name IN (?)",
 			j.App.ID,
@@ -63,11 +104,12 @@ func (j *Job) GetJobTemplatesByNameAndLocale(db interfaces.DB) (map[string]map[s
 	}
 	templateByLocale := make(map[string]map[string]Template)
 	for _, tpl := range templates {
+		locale := NormalizeLocale(tpl.Locale)
 		if templateByLocale[tpl.Name] != nil {
-			templateByLocale[tpl.Name][tpl.Locale] = tpl
+			templateByLocale[tpl.Name][locale] = tpl
 		} else {
 			templateByLocale[tpl.Name] = map[string]Template{
-				tpl.Locale: tpl,
+				locale: tpl,
 			}
 		}
 	}
@@ -0,0 +1,45 @@
+/*
+ * Copyright (c) 2026 TFG Co <backend@tfgco.com>
+ * Author: TFG Co <backend@tfgco.com>
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy of
+ * this software and associated documentation files (the "Software"), to deal in
+ * the Software without restriction, including without limitation the rights to
+ * use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+ * the Software, and to permit persons to whom the Software is furnished to do so,
+ * subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+ * FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+ * COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+ * IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+ * CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+ */
+
+package model
+
+import (
+	"hash/fnv"
+)
+
+// HoldoutBuckets is the number of buckets the users of an app are hashed into, the holdout
+// group has the first buckets
+const HoldoutBuckets = 10000
+
+// HoldoutBucket returns the bucket of the user, it only changes if the app holdout salt changes
+func (a *App) HoldoutBucket(userID string) int {
+	h := fnv.New32a()
+	h.Write([]byte(a.HoldoutSalt))
+	h.Write([]byte(":"))
+	h.Write([]byte(userID))
+	return int(h.Sum32() % HoldoutBuckets)
+}
+
+// InHoldout returns true if the user is in the holdout group of the app
+func (a *App) InHoldout(userID string) bool {
+	return a.HoldoutBucket(userID) < int(a.HoldoutPercentage*HoldoutBuckets)
+}
@@ -30,39 +30,65 @@ import (
 	"github.com/labstack/echo/v4"
 	"github.com/satori/go.uuid"
 	"github.com/topfreegames/marathon/interfaces"
+	"github.com/topfreegames/marathon/messages"
+)
+
+// Job priorities, the batches of each priority are sent by their own worker queues
+const (
+	PriorityHigh   = "high"
+	PriorityNormal = "normal"
+	PriorityLow    = "low"
 )
 
 // Job is the job model struct
 type Job struct {
-	ID                  uuid.UUID              `sql:",pk" json:"id"`
-	TotalBatches        int                    `json:"totalBatches"`
-	CompletedBatches    int                    `json:"completedBatches"`
-	ControlGroup        float64                `json:"controlGroup"`
-	TotalUsers          int                    `json:"totalUsers"`
-	TotalTokens         int                    `json:"totalTokens"`
-	CompletedTokens     int                    `json:"completedTokens"`
-	DBPageSize          int                    `json:"dbPageSize"`
-	Localized           bool                   `json:"localized"`
-	CompletedAt         int64                  `json:"completedAt"`
-	ExpiresAt           int64                  `json:"expiresAt"`
-	StartsAt            int64                  `json:"startsAt"`
-	Context             map[string]interface{} `json:"context"`
-	Service             string                 `json:"service"`
-	Filters             map[string]interface{} `json:"filters"`
-	Metadata            map[string]interface{} `json:"metadata"`
-	CSVPath             string                 `json:"csvPath"`
-	ControlGroupCSVPath string                 `json:"controlGroupCsvPath"`
-	CreatedBy           string                 `json:"createdBy"`
-	App                 App                    `json:"app"`
-	AppID               uuid.UUID              `json:"appId"`
-	JobGroupID          uuid.UUID              `json:"jobGroupId" sql:",null"`
-	TemplateName        string                 `json:"templateName"`
-	PastTimeStrategy    string                 `json:"pastTimeStrategy"`
-	Status              string                 `json:"status"`
-	Feedbacks           map[string]interface{} `json:"feedbacks"`
-	CreatedAt           int64                  `json:"createdAt"`
-	UpdatedAt           int64                  `json:"updatedAt"`
-	StatusEvents        []*Status              `json:"statusEvents"`
+	ID                  uuid.UUID                 `sql:",pk" json:"id"`
+	TotalBatches        int                       `json:"totalBatches"`
+	CompletedBatches    int                       `json:"completedBatches"`
+	ControlGroup        float64                   `json:"controlGroup"`
+	UseHoldout          bool                      `json:"useHoldout"`
+	HoldoutUsers        int                       `json:"holdoutUsers"`
+	CappedUsers         int                       `json:"cappedUsers"`
+	QuietHours          *QuietHours               `json:"quietHours"`
+	PushOptions         *messages.PushOptions     `json:"pushOptions"`
+	DeferredUsers       int                       `json:"deferredUsers"`
+	MaxPushesPerSecond  int                       `json:"maxPushesPerSecond"`
+	SpreadOver          int64                     `json:"spreadOver"`
+	Priority            string                    `json:"priority"`
+	Throttle            *JobThrottle              `sql:"-" json:"throttle,omitempty"`
+	TotalUsers          int                       `json:"totalUsers"`
+	TotalTokens         int                       `json:"totalTokens"`
+	CompletedTokens     int                       `json:"completedTokens"`
+	DBPageSize          int                       `json:"dbPageSize"`
+	Localized           bool                      `json:"localized"`
+	CompletedAt         int64                     `json:"completedAt"`
+	ExpiresAt           int64                     `json:"expiresAt"`
+	StartsAt            int64                     `json:"startsAt"`
+	Context             map[string]interface{}    `json:"context"`
+	Service             string                    `json:"service"`
+	Filters             map[string]interface{}    `json:"filters"`
+	Metadata            map[string]interface{}    `json:"metadata"`
+	CSVPath             string                    `json:"csvPath"`
+	ControlGroupCSVPath string                    `json:"controlGroupCsvPath"`
+	TreatedCSVPath      string                    `json:"treatedCsvPath"`
+	CreatedBy           string                    `json:"createdBy"`
+	App                 App                       `json:"app"`
+	AppID               uuid.UUID                 `json:"appId"`
+	JobGroupID          uuid.UUID                 `json:"jobGroupId" sql:",null"`
+	ScheduleID          uuid.UUID                 `json:"scheduleId" sql:",null"`
+	TemplateName        string                    `json:"templateName"`
+	PastTimeStrategy    string                    `json:"pastTimeStrategy"`
+	Status              string                    `json:"status"`
+	Feedbacks           map[string]interface{}    `json:"feedbacks"`
+	LocaleStats         map[string]int            `json:"localeStats"`
+	TemplateWeights     map[string]int            `json:"templateWeights"`
+	TemplateVersions    map[string]int            `json:"templateVersions"`
+	VariantFeedbacks    map[string]map[string]int `json:"variantFeedbacks"`
+	VariantCSVPaths     map[string]string         `json:"variantCsvPaths"`
+	UpliftReport        *UpliftReport             `json:"upliftReport"`
+	CreatedAt           int64                     `json:"createdAt"`
+	UpdatedAt           int64                     `json:"updatedAt"`
+	StatusEvents        []*Status                 `json:"statusEvents"`
 }
 
 // Validate implementation of the InputValidation interface
@@ -97,12 +123,78 @@ func (j *Job) Validate(c echo.Context) error {
 		return InvalidField("filters or csvPath must exist, not both")
 	}
 
+	valid = j.ValidTemplateWeights()
+	if !valid {
+		return InvalidField("templateWeights")
+	}
+
+	valid = validateQuietHours(j.QuietHours)
+	if !valid {
+		return InvalidField("quietHours")
+	}
+
+	if err := validatePushOptions(j.PushOptions); err != nil {
+		return err
+	}
+
+	valid = j.MaxPushesPerSecond >= 0
+	if !valid {
+		return InvalidField("maxPushesPerSecond")
+	}
+
+	valid = j.SpreadOver >= 0 && (j.SpreadOver == 0 || j.MaxPushesPerSecond == 0)
+	if !valid {
+		return InvalidField("spreadOver")
+	}
+
+	valid = govalidator.StringMatches(j.Priority, "^(high|normal|low)?$")
+	if !valid {
+		return InvalidField("priority")
+	}
+
 	if !govalidator.IsNull(j.CSVPath) && govalidator.Contains(j.CSVPath, "s3://") {
 		return InvalidField("csvPath: cannot contain s3 protocol, just the bucket path")
 	}
 	return nil
 }
 
+// JobReschedule is the payload used to change a job that has not started yet
+type JobReschedule struct {
+	StartsAt     int64                  `json:"startsAt"`
+	ExpiresAt    int64                  `json:"expiresAt"`
+	TemplateName string                 `json:"templateName"`
+	Context      map[string]interface{} `json:"context"`
+	Metadata     map[string]interface{} `json:"metadata"`
+}
+
+// Validate implementation of the InputValidation interface
+func (r *JobReschedule) Validate(c echo.Context) error {
+	valid := time.Now().UnixNano() < r.StartsAt
+	if !valid {
+		return InvalidField("startsAt")
+	}
+
+	valid = r.ExpiresAt == 0 || r.StartsAt < r.ExpiresAt
+	if !valid {
+		return InvalidField("expiresAt")
+	}
+	return nil
+}
+
+// JobRejection is the payload used to reject a job pending approval
+type JobRejection struct {
+	Reason string `json:"reason"`
+}
+
+// Validate implementation of the InputValidation interface
+func (r *JobRejection) Validate(c echo.Context) error {
+	valid := govalidator.StringLength(r.Reason, "1", "1000")
+	if !valid {
+		return InvalidField("reason")
+	}
+	return nil
+}
+
 // Labels return the labels for metrics
 func (j *Job) Labels() []string {
 	return []string{
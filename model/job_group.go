@@ -26,9 +26,40 @@ import (
 	"github.com/satori/go.uuid"
 )
 
-// JobGroup is a collection of jobs
+// JobGroup is a collection of jobs, the totals are the sums of the jobs of the group
 type JobGroup struct {
-	ID    uuid.UUID `sql:",pk" json:"id"`
-	AppID uuid.UUID `json:"appId"`
-	Jobs  []*Job    `json:"jobs"`
+	ID               uuid.UUID      `sql:",pk" json:"id"`
+	AppID            uuid.UUID      `json:"appId"`
+	Jobs             []*Job         `json:"jobs"`
+	TotalBatches     int            `sql:"-" json:"totalBatches"`
+	CompletedBatches int            `sql:"-" json:"completedBatches"`
+	TotalUsers       int            `sql:"-" json:"totalUsers"`
+	TotalTokens      int            `sql:"-" json:"totalTokens"`
+	CompletedTokens  int            `sql:"-" json:"completedTokens"`
+	Feedbacks        map[string]int `sql:"-" json:"feedbacks"`
+}
+
+// Aggregate sets the group totals from its jobs
+func (g *JobGroup) Aggregate() {
+	g.TotalBatches = 0
+	g.CompletedBatches = 0
+	g.TotalUsers = 0
+	g.TotalTokens = 0
+	g.CompletedTokens = 0
+	g.Feedbacks = map[string]int{}
+	for _, job := range g.Jobs {
+		g.TotalBatches += job.TotalBatches
+		g.CompletedBatches += job.CompletedBatches
+		g.TotalUsers += job.TotalUsers
+		g.TotalTokens += job.TotalTokens
+		g.CompletedTokens += job.CompletedTokens
+		for feedback, count := range job.Feedbacks {
+			switch count := count.(type) {
+			case float64:
+				g.Feedbacks[feedback] += int(count)
+			case int:
+				g.Feedbacks[feedback] += count
+			}
+		}
+	}
 }
@@ -0,0 +1,93 @@
+/*
+ * Copyright (c) 2026 TFG Co <backend@tfgco.com>
+ * Author: TFG Co <backend@tfgco.com>
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy of
+ * this software and associated documentation files (the "Software"), to deal in
+ * the Software without restriction, including without limitation the rights to
+ * use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+ * the Software, and to permit persons to whom the Software is furnished to do so,
+ * subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+ * FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+ * COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+ * IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+ * CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+ */
+
+package model
+
+import (
+	"regexp"
+	"strings"
+)
+
+// DefaultLocale is the last locale of every fallback chain
+const DefaultLocale = "en"
+
+var localeRegex = regexp.MustCompile("^[a-z]{2,3}(-[a-z0-9]{2,8})*$")
+
+// NormalizeLocale lowercases the locale and uses - to separate language and region,
+// pt_BR, pt-BR and PT-br are all normalized to pt-br
+func NormalizeLocale(locale string) string {
+	return strings.Replace(strings.ToLower(strings.TrimSpace(locale)), "_", "-", -1)
+}
+
+// NormalizeLocaleFallbacks normalizes the keys and the values of the fallbacks
+func NormalizeLocaleFallbacks(fallbacks map[string][]string) map[string][]string {
+	if fallbacks == nil {
+		return nil
+	}
+	res := make(map[string][]string, len(fallbacks))
+	for locale, chain := range fallbacks {
+		normalized := []string{}
+		for _, fallback := range chain {
+			normalized = append(normalized, NormalizeLocale(fallback))
+		}
+		res[NormalizeLocale(locale)] = normalized
+	}
+	return res
+}
+
+// LocaleChain returns the locales that can be used for a user with the given locale, in order:
+// the locale itself, its fallbacks, its language, the language fallbacks and the default locale
+func (a *App) LocaleChain(locale string) []string {
+	locale = NormalizeLocale(locale)
+	chain := []string{locale}
+	chain = append(chain, a.LocaleFallbacks[locale]...)
+	if idx := strings.Index(locale, "-"); idx > 0 {
+		language := locale[:idx]
+		chain = append(chain, language)
+		chain = append(chain, a.LocaleFallbacks[language]...)
+	}
+	chain = append(chain, DefaultLocale)
+
+	seen := map[string]bool{}
+	res := make([]string, 0, len(chain))
+	for _, l := range chain {
+		if !seen[l] {
+			seen[l] = true
+			res = append(res, l)
+		}
+	}
+	return res
+}
+
+func validateLocaleFallbacks(fallbacks map[string][]string) bool {
+	for locale, chain := range fallbacks {
+		if !localeRegex.MatchString(NormalizeLocale(locale)) {
+			return false
+		}
+		for _, fallback := range chain {
+			if !localeRegex.MatchString(NormalizeLocale(fallback)) {
+				return false
+			}
+		}
+	}
+	return true
+}
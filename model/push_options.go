@@ -0,0 +1,61 @@
+/*
+ * Copyright (c) 2026 TFG Co <backend@tfgco.com>
+ * Author: TFG Co <backend@tfgco.com>
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy of
+ * this software and associated documentation files (the "Software"), to deal in
+ * the Software without restriction, including without limitation the rights to
+ * use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+ * the Software, and to permit persons to whom the Software is furnished to do so,
+ * subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+ * FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+ * COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+ * IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+ * CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+ */
+package model
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/asaskevich/govalidator"
+	"github.com/topfreegames/marathon/messages"
+)
+
+// validatePushOptions returns an error with the first invalid field of the options
+func validatePushOptions(options *messages.PushOptions) error {
+	if options == nil {
+		return nil
+	}
+	fields := []struct {
+		name  string
+		valid bool
+	}{
+		{"mediaUrl", options.MediaURL == "" || (govalidator.IsURL(options.MediaURL) && strings.HasPrefix(options.MediaURL, "https://"))},
+		{"actionCategory", govalidator.StringLength(options.ActionCategory, "0", "255")},
+		{"threadId", govalidator.StringLength(options.ThreadID, "0", "255")},
+		{"interruptionLevel", govalidator.StringMatches(options.InterruptionLevel, "^(passive|active|time-sensitive|critical)?$")},
+		{"priority", govalidator.StringMatches(options.Priority, "^(high|normal)?$")},
+		{"collapseKey", len(options.CollapseKey) <= 64},
+		{"channelId", govalidator.StringLength(options.ChannelID, "0", "255")},
+		{"analyticsLabel", govalidator.StringMatches(options.AnalyticsLabel, "^[a-zA-Z0-9-_.~%]{0,50}$")},
+	}
+	for _, field := range fields {
+		if !field.valid {
+			return InvalidField(fmt.Sprintf("pushOptions.%s", field.name))
+		}
+	}
+	return nil
+}
+
+// GetPushOptions returns the push options of the template with the ones set in the job replaced
+func (j *Job) GetPushOptions(template *Template) *messages.PushOptions {
+	return template.PushOptions.Merge(j.PushOptions)
+}
@@ -0,0 +1,84 @@
+/*
+ * Copyright (c) 2026 TFG Co <backend@tfgco.com>
+ * Author: TFG Co <backend@tfgco.com>
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy of
+ * this software and associated documentation files (the "Software"), to deal in
+ * the Software without restriction, including without limitation the rights to
+ * use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+ * the Software, and to permit persons to whom the Software is furnished to do so,
+ * subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+ * FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+ * COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+ * IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+ * CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+ */
+
+package model
+
+import (
+	"time"
+)
+
+// QuietHours is a daily window, in the user local time, in which the user is not sent pushes.
+// Start and end are clocks like 22:00, the window ends in the next day if end is before start
+// and it is empty if they are equal
+type QuietHours struct {
+	Start string `json:"start"`
+	End   string `json:"end"`
+}
+
+// parseClock returns the minutes since midnight of the clock
+func parseClock(clock string) (int, bool) {
+	t, err := time.Parse("15:04", clock)
+	if err != nil {
+		return 0, false
+	}
+	return t.Hour()*60 + t.Minute(), true
+}
+
+func validateQuietHours(quietHours *QuietHours) bool {
+	if quietHours == nil {
+		return true
+	}
+	_, validStart := parseClock(quietHours.Start)
+	_, validEnd := parseClock(quietHours.End)
+	return validStart && validEnd
+}
+
+// Until returns when the quiet window that contains t ends, it returns false if t is not in
+// quiet hours
+func (q *QuietHours) Until(t time.Time) (time.Time, bool) {
+	start, validStart := parseClock(q.Start)
+	end, validEnd := parseClock(q.End)
+	if !validStart || !validEnd || start == end {
+		return t, false
+	}
+	clock := t.Hour()*60 + t.Minute()
+	quiet := clock >= start && clock < end
+	if start > end {
+		quiet = clock >= start || clock < end
+	}
+	if !quiet {
+		return t, false
+	}
+	until := time.Date(t.Year(), t.Month(), t.Day(), 0, end, 0, 0, t.Location())
+	if !until.After(t) {
+		until = time.Date(t.Year(), t.Month(), t.Day()+1, 0, end, 0, 0, t.Location())
+	}
+	return until, true
+}
+
+// GetQuietHours returns the quiet hours of the job, or the ones of the app if the job has none
+func (j *Job) GetQuietHours() *QuietHours {
+	if j.QuietHours != nil {
+		return j.QuietHours
+	}
+	return j.App.QuietHours
+}
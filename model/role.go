@@ -0,0 +1,82 @@
+/*
+ * Copyright (c) 2026 TFG Co <backend@tfgco.com>
+ * Author: TFG Co <backend@tfgco.com>
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy of
+ * this software and associated documentation files (the "Software"), to deal in
+ * the Software without restriction, including without limitation the rights to
+ * use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+ * the Software, and to permit persons to whom the Software is furnished to do so,
+ * subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+ * FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+ * COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+ * IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+ * CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+ */
+
+package model
+
+import (
+	"github.com/satori/go.uuid"
+)
+
+// Roles a user can have in an app, every role can read the app and the app admin has all of them
+const (
+	RoleViewer         = "viewer"
+	RoleTemplateEditor = "template_editor"
+	RoleJobCreator     = "job_creator"
+	RoleApprover       = "approver"
+	RoleAppAdmin       = "app_admin"
+)
+
+var roles = map[string]bool{
+	RoleViewer:         true,
+	RoleTemplateEditor: true,
+	RoleJobCreator:     true,
+	RoleApprover:       true,
+	RoleAppAdmin:       true,
+}
+
+// IsAllowedApp returns true if the user has full access to the app through AllowedApps
+func (u *User) IsAllowedApp(appID uuid.UUID) bool {
+	for _, allowedApp := range u.AllowedApps {
+		if allowedApp == appID {
+			return true
+		}
+	}
+	return false
+}
+
+// HasAppRole returns true if one of the roles of the user in the app grants the given role
+func (u *User) HasAppRole(appID uuid.UUID, role string) bool {
+	for _, userRole := range u.AppRoles[appID.String()] {
+		if grantsRole(userRole, role) {
+			return true
+		}
+	}
+	return false
+}
+
+func grantsRole(granted, role string) bool {
+	return granted == role || granted == RoleAppAdmin || role == RoleViewer
+}
+
+func validateAppRoles(appRoles map[string][]string) bool {
+	for appID, userRoles := range appRoles {
+		if _, err := uuid.FromString(appID); err != nil {
+			return false
+		}
+		for _, role := range userRoles {
+			if !roles[role] {
+				return false
+			}
+		}
+	}
+	return true
+}
@@ -0,0 +1,164 @@
+/*
+ * Copyright (c) 2026 TFG Co <backend@tfgco.com>
+ * Author: TFG Co <backend@tfgco.com>
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy of
+ * this software and associated documentation files (the "Software"), to deal in
+ * the Software without restriction, including without limitation the rights to
+ * use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+ * the Software, and to permit persons to whom the Software is furnished to do so,
+ * subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+ * FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+ * COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+ * IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+ * CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+ */
+
+package model
+
+import (
+	"time"
+
+	"github.com/asaskevich/govalidator"
+	"github.com/labstack/echo/v4"
+	cron "github.com/robfig/cron/v3"
+	"github.com/satori/go.uuid"
+)
+
+// Schedule is a recurring job definition, each occurrence creates a new Job
+type Schedule struct {
+	ID             uuid.UUID              `sql:",pk" json:"id"`
+	CronExpression string                 `json:"cronExpression"`
+	Timezone       string                 `json:"timezone"`
+	EndsAt         int64                  `json:"endsAt"`
+	MaxRuns        int                    `json:"maxRuns"`
+	TotalRuns      int                    `json:"totalRuns"`
+	NextRunAt      int64                  `json:"nextRunAt"`
+	LastRunAt      int64                  `json:"lastRunAt"`
+	Paused         bool                   `json:"paused"`
+	TemplateName   string                 `json:"templateName"`
+	Service        string                 `json:"service"`
+	Context        map[string]interface{} `json:"context"`
+	Filters        map[string]interface{} `json:"filters"`
+	Metadata       map[string]interface{} `json:"metadata"`
+	CSVPath        string                 `json:"csvPath"`
+	ControlGroup   float64                `json:"controlGroup"`
+	UseHoldout     bool                   `json:"useHoldout"`
+	ExpiresIn      int64                  `json:"expiresIn"`
+	CreatedBy      string                 `json:"createdBy"`
+	App            App                    `json:"app"`
+	AppID          uuid.UUID              `json:"appId"`
+	CreatedAt      int64                  `json:"createdAt"`
+	UpdatedAt      int64                  `json:"updatedAt"`
+}
+
+// Validate implementation of the InputValidation interface
+func (s *Schedule) Validate(c echo.Context) error {
+	if s.Timezone == "" {
+		s.Timezone = "UTC"
+	}
+
+	if _, err := cron.ParseStandard(s.CronExpression); err != nil {
+		return InvalidField("cronExpression")
+	}
+
+	if _, err := time.LoadLocation(s.Timezone); err != nil {
+		return InvalidField("timezone")
+	}
+
+	valid := govalidator.StringMatches(s.Service, "^(apns|gcm)$")
+	if !valid {
+		return InvalidField("service")
+	}
+
+	valid = s.EndsAt == 0 || time.Now().UnixNano() < s.EndsAt
+	if !valid {
+		return InvalidField("endsAt")
+	}
+
+	valid = s.MaxRuns >= 0
+	if !valid {
+		return InvalidField("maxRuns")
+	}
+
+	valid = s.ExpiresIn >= 0
+	if !valid {
+		return InvalidField("expiresIn")
+	}
+
+	valid = s.ControlGroup >= 0 && s.ControlGroup < 1
+	if !valid {
+		return InvalidField("controlGroup")
+	}
+
+	valid = govalidator.IsEmail(s.CreatedBy)
+	if !valid {
+		return InvalidField("createdBy")
+	}
+
+	valid = !(len(s.Filters) != 0 && !govalidator.IsNull(s.CSVPath))
+	if !valid {
+		return InvalidField("filters or csvPath must exist, not both")
+	}
+
+	if !govalidator.IsNull(s.CSVPath) && govalidator.Contains(s.CSVPath, "s3://") {
+		return InvalidField("csvPath: cannot contain s3 protocol, just the bucket path")
+	}
+	return nil
+}
+
+// NextRun returns the first occurrence of the schedule after the given time,
+// the zero time is returned if the schedule has no more occurrences
+func (s *Schedule) NextRun(after time.Time) (time.Time, error) {
+	if s.MaxRuns > 0 && s.TotalRuns >= s.MaxRuns {
+		return time.Time{}, nil
+	}
+
+	expression, err := cron.ParseStandard(s.CronExpression)
+	if err != nil {
+		return time.Time{}, err
+	}
+	location, err := time.LoadLocation(s.Timezone)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	next := expression.Next(after.In(location))
+	if next.IsZero() || (s.EndsAt > 0 && next.UnixNano() > s.EndsAt) {
+		return time.Time{}, nil
+	}
+	return next, nil
+}
+
+// NewJob returns the job that should be created for the occurrence at the given time
+func (s *Schedule) NewJob(at time.Time) *Job {
+	now := time.Now().UnixNano()
+	job := &Job{
+		ID:           uuid.NewV4(),
+		AppID:        s.AppID,
+		App:          s.App,
+		ScheduleID:   s.ID,
+		TemplateName: s.TemplateName,
+		Service:      s.Service,
+		Context:      s.Context,
+		Filters:      s.Filters,
+		Metadata:     s.Metadata,
+		CSVPath:      s.CSVPath,
+		ControlGroup: s.ControlGroup,
+		UseHoldout:   s.UseHoldout,
+		CreatedBy:    s.CreatedBy,
+		StartsAt:     at.UnixNano(),
+		CreatedAt:    now,
+		UpdatedAt:    now,
+	}
+	if s.ExpiresIn > 0 {
+		job.ExpiresAt = at.Add(time.Duration(s.ExpiresIn)).UnixNano()
+	}
+	return job
+}
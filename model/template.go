@@ -26,20 +26,25 @@ import (
 	"github.com/asaskevich/govalidator"
 	"github.com/labstack/echo/v4"
 	"github.com/satori/go.uuid"
+	"github.com/topfreegames/marathon/messages"
 )
 
 // Template is the template model struct
 type Template struct {
-	ID        uuid.UUID              `sql:",pk" json:"id"`
-	Name      string                 `json:"name"`
-	Locale    string                 `json:"locale"`
-	Defaults  map[string]interface{} `json:"defaults"`
-	Body      map[string]interface{} `json:"body"`
-	CreatedBy string                 `json:"createdBy"`
-	App       App                    `json:"app"`
-	AppID     uuid.UUID              `json:"appId"`
-	CreatedAt int64                  `json:"createdAt"`
-	UpdatedAt int64                  `json:"updatedAt"`
+	ID          uuid.UUID              `sql:",pk" json:"id"`
+	Name        string                 `json:"name"`
+	Locale      string                 `json:"locale"`
+	Category    string                 `json:"category"`
+	Service     string                 `json:"service"`
+	Defaults    map[string]interface{} `json:"defaults"`
+	Body        map[string]interface{} `json:"body"`
+	PushOptions *messages.PushOptions  `json:"pushOptions"`
+	CreatedBy   string                 `json:"createdBy"`
+	App         App                    `json:"app"`
+	AppID       uuid.UUID              `json:"appId"`
+	Version     int                    `json:"version"`
+	CreatedAt   int64                  `json:"createdAt"`
+	UpdatedAt   int64                  `json:"updatedAt"`
 }
 
 // Validate implementation of the InputValidation interface
@@ -56,5 +61,72 @@ func (t *Template) Validate(c echo.Context) error {
 	if !valid {
 		return InvalidField("body")
 	}
+	valid = validateCategory(t.Category)
+	if !valid {
+		return InvalidField("category")
+	}
+	valid = govalidator.StringMatches(t.Service, "^(apns|gcm)?$")
+	if !valid {
+		return InvalidField("service")
+	}
+	return validatePushOptions(t.PushOptions)
+}
+
+// Services returns the services the template is sent to, both if it has no service
+func (t *Template) Services() []string {
+	if t.Service != "" {
+		return []string{t.Service}
+	}
+	return []string{"apns", "gcm"}
+}
+
+// MaxTemplatePreviewRecipients is the maximum number of users and tokens of a test send
+const MaxTemplatePreviewRecipients = 100
+
+// TemplatePreview is the payload used to render a template and optionally send it
+// to a few users or tokens
+type TemplatePreview struct {
+	Service   string                 `json:"service"`
+	Context   map[string]interface{} `json:"context"`
+	Metadata  map[string]interface{} `json:"metadata"`
+	ExpiresAt int64                  `json:"expiresAt"`
+	UserIDs   []string               `json:"userIds"`
+	Tokens    []string               `json:"tokens"`
+}
+
+// Validate implementation of the InputValidation interface
+func (p *TemplatePreview) Validate(c echo.Context) error {
+	valid := p.Service == "" || govalidator.StringMatches(p.Service, "^(apns|gcm)$")
+	if !valid {
+		return InvalidField("service")
+	}
+
+	valid = p.ExpiresAt >= 0
+	if !valid {
+		return InvalidField("expiresAt")
+	}
+
+	valid = len(p.Tokens) == 0 || p.Service != ""
+	if !valid {
+		return InvalidField("service: tokens can only be sent to a single service")
+	}
+
+	valid = len(p.UserIDs)+len(p.Tokens) <= MaxTemplatePreviewRecipients
+	if !valid {
+		return InvalidField("userIds and tokens: test send is limited to 100 recipients")
+	}
 	return nil
 }
+
+// Services returns the services that should be previewed, both if none was given
+func (p *TemplatePreview) Services() []string {
+	if p.Service != "" {
+		return []string{p.Service}
+	}
+	return []string{"apns", "gcm"}
+}
+
+// IsTestSend returns true if the preview should be sent to users or tokens
+func (p *TemplatePreview) IsTestSend() bool {
+	return len(p.UserIDs) > 0 || len(p.Tokens) > 0
+}
@@ -0,0 +1,345 @@
+/*
+ * Copyright (c) 2026 TFG Co <backend@tfgco.com>
+ * Author: TFG Co <backend@tfgco.com>
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy of
+ * this software and associated documentation files (the "Software"), to deal in
+ * the Software without restriction, including without limitation the rights to
+ * use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+ * the Software, and to permit persons to whom the Software is furnished to do so,
+ * subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+ * FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+ * COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+ * IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+ * CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+ */
+package model
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+	"github.com/topfreegames/marathon/messages"
+)
+
+// templateDocumentColumns are the csv columns of the template fields that are not in the
+// defaults or the body
+var templateDocumentColumns = []string{"name", "locale", "category", "service"}
+
+// templateDocumentJSONSuffix marks the csv columns whose cells are json values instead of strings
+const templateDocumentJSONSuffix = ":json"
+
+// TemplateDocument has every locale of a template name, it is the format in which templates
+// are imported and exported
+type TemplateDocument struct {
+	Name    string            `json:"name"`
+	Locales []*TemplateLocale `json:"locales"`
+}
+
+// TemplateLocale is a locale of a template document
+type TemplateLocale struct {
+	Locale      string                 `json:"locale"`
+	Category    string                 `json:"category"`
+	Service     string                 `json:"service"`
+	Defaults    map[string]interface{} `json:"defaults"`
+	Body        map[string]interface{} `json:"body"`
+	PushOptions *messages.PushOptions  `json:"pushOptions"`
+}
+
+// NewTemplateDocument returns the document of the templates, which must have the same name
+func NewTemplateDocument(templates []Template) *TemplateDocument {
+	document := &TemplateDocument{Locales: []*TemplateLocale{}}
+	for _, t := range templates {
+		document.Name = t.Name
+		document.Locales = append(document.Locales, &TemplateLocale{
+			Locale:      t.Locale,
+			Category:    t.Category,
+			Service:     t.Service,
+			Defaults:    t.Defaults,
+			Body:        t.Body,
+			PushOptions: t.PushOptions,
+		})
+	}
+	sort.Slice(document.Locales, func(i, j int) bool {
+		return document.Locales[i].Locale < document.Locales[j].Locale
+	})
+	return document
+}
+
+// Validate implementation of the InputValidation interface
+func (d *TemplateDocument) Validate(c echo.Context) error {
+	valid := len(d.Locales) > 0
+	if !valid {
+		return InvalidField("locales")
+	}
+	locales := map[string]bool{}
+	for _, t := range d.Templates() {
+		if err := t.Validate(c); err != nil {
+			return fmt.Errorf("locale %s: %s", t.Locale, err.Error())
+		}
+		if locales[t.Locale] {
+			return InvalidField(fmt.Sprintf("locales: %s is duplicated", t.Locale))
+		}
+		locales[t.Locale] = true
+	}
+	return nil
+}
+
+// Templates returns a template for each locale of the document
+func (d *TemplateDocument) Templates() []*Template {
+	templates := make([]*Template, len(d.Locales))
+	for i, l := range d.Locales {
+		defaults := l.Defaults
+		if defaults == nil {
+			defaults = map[string]interface{}{}
+		}
+		templates[i] = &Template{
+			Name:        d.Name,
+			Locale:      l.Locale,
+			Category:    l.Category,
+			Service:     l.Service,
+			Defaults:    defaults,
+			Body:        l.Body,
+			PushOptions: l.PushOptions,
+		}
+	}
+	return templates
+}
+
+// WriteCSV writes the document as a csv with a row by locale, the defaults, body and push options
+// are flattened in a column by key, e.g. body.alert.title, and the cells of the columns that have a value that
+// is not a string, suffixed with :json, are json values
+func (d *TemplateDocument) WriteCSV(w io.Writer) error {
+	rows := make([]map[string]interface{}, len(d.Locales))
+	jsonColumns := map[string]bool{}
+	for i, l := range d.Locales {
+		rows[i] = map[string]interface{}{}
+		flattenTemplateField("defaults", l.Defaults, rows[i])
+		flattenTemplateField("body", l.Body, rows[i])
+		if l.PushOptions != nil {
+			options := map[string]interface{}{}
+			b, err := json.Marshal(l.PushOptions)
+			if err != nil {
+				return err
+			}
+			json.Unmarshal(b, &options)
+			if len(options) > 0 {
+				flattenTemplateField("pushOptions", options, rows[i])
+			}
+		}
+		for column, value := range rows[i] {
+			if s, ok := value.(string); !ok || s == "" {
+				jsonColumns[column] = true
+			}
+		}
+	}
+	fieldColumns := []string{}
+	seen := map[string]bool{}
+	for _, row := range rows {
+		for column := range row {
+			if !seen[column] {
+				seen[column] = true
+				fieldColumns = append(fieldColumns, column)
+			}
+		}
+	}
+	sort.Strings(fieldColumns)
+
+	header := append([]string{}, templateDocumentColumns...)
+	for _, column := range fieldColumns {
+		if jsonColumns[column] {
+			column = column + templateDocumentJSONSuffix
+		}
+		header = append(header, column)
+	}
+	writer := csv.NewWriter(w)
+	if err := writer.Write(header); err != nil {
+		return err
+	}
+	for i, l := range d.Locales {
+		record := []string{d.Name, l.Locale, l.Category, l.Service}
+		for _, column := range fieldColumns {
+			value, ok := rows[i][column]
+			if !ok {
+				record = append(record, "")
+				continue
+			}
+			if !jsonColumns[column] {
+				record = append(record, value.(string))
+				continue
+			}
+			cell, err := json.Marshal(value)
+			if err != nil {
+				return err
+			}
+			record = append(record, string(cell))
+		}
+		if err := writer.Write(record); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// ReadTemplateDocumentCSV reads a document in the format written by WriteCSV, empty cells are
+// keys that the locale does not have
+func ReadTemplateDocumentCSV(r io.Reader) (*TemplateDocument, error) {
+	reader := csv.NewReader(r)
+	header, err := reader.Read()
+	if err != nil {
+		if err == io.EOF {
+			return nil, InvalidField("csv: it is empty")
+		}
+		return nil, err
+	}
+	columns := map[string]bool{}
+	for _, column := range header {
+		if columns[column] {
+			return nil, InvalidField(fmt.Sprintf("csv: column %s is duplicated", column))
+		}
+		columns[column] = true
+		path := strings.TrimSuffix(column, templateDocumentJSONSuffix)
+		if !isTemplateDocumentColumn(column) && !isTemplateFieldColumn(path, "defaults") && !isTemplateFieldColumn(path, "body") && !isTemplateFieldColumn(path, "pushOptions") {
+			return nil, InvalidField(fmt.Sprintf("csv: unknown column %s", column))
+		}
+	}
+	if !columns["name"] || !columns["locale"] {
+		return nil, InvalidField("csv: the name and locale columns are required")
+	}
+
+	document := &TemplateDocument{Locales: []*TemplateLocale{}}
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		locale := &TemplateLocale{}
+		fields := map[string]interface{}{}
+		for i, column := range header {
+			cell := record[i]
+			switch column {
+			case "name":
+				if document.Name != "" && cell != document.Name {
+					return nil, InvalidField("csv: every row must have the same name")
+				}
+				document.Name = cell
+			case "locale":
+				locale.Locale = cell
+			case "category":
+				locale.Category = cell
+			case "service":
+				locale.Service = cell
+			default:
+				if cell == "" {
+					continue
+				}
+				path := column
+				var value interface{} = cell
+				if strings.HasSuffix(column, templateDocumentJSONSuffix) {
+					path = strings.TrimSuffix(column, templateDocumentJSONSuffix)
+					if err := json.Unmarshal([]byte(cell), &value); err != nil {
+						return nil, InvalidField(fmt.Sprintf("csv: %s of locale %s is not json", column, locale.Locale))
+					}
+				}
+				if err := setTemplateField(fields, strings.Split(path, "."), value); err != nil {
+					return nil, InvalidField(fmt.Sprintf("csv: column %s conflicts with another column", column))
+				}
+			}
+		}
+		locale.Defaults, _ = fields["defaults"].(map[string]interface{})
+		locale.Body, _ = fields["body"].(map[string]interface{})
+		if options, ok := fields["pushOptions"]; ok {
+			locale.PushOptions, err = decodeTemplatePushOptions(options)
+			if err != nil {
+				return nil, InvalidField(fmt.Sprintf("csv: pushOptions of locale %s: %s", locale.Locale, err.Error()))
+			}
+		}
+		document.Locales = append(document.Locales, locale)
+	}
+	return document, nil
+}
+
+// decodeTemplatePushOptions returns the push options of the pushOptions columns of a row
+func decodeTemplatePushOptions(options interface{}) (*messages.PushOptions, error) {
+	b, err := json.Marshal(options)
+	if err != nil {
+		return nil, err
+	}
+	decoder := json.NewDecoder(bytes.NewReader(b))
+	decoder.DisallowUnknownFields()
+	pushOptions := &messages.PushOptions{}
+	if err := decoder.Decode(pushOptions); err != nil {
+		return nil, err
+	}
+	return pushOptions, nil
+}
+
+func isTemplateDocumentColumn(column string) bool {
+	for _, c := range templateDocumentColumns {
+		if c == column {
+			return true
+		}
+	}
+	return false
+}
+
+func isTemplateFieldColumn(path, field string) bool {
+	return path == field || strings.HasPrefix(path, field+".")
+}
+
+// flattenTemplateField sets a column for each value of the field, maps are flattened unless they
+// are empty or have keys that can't be columns
+func flattenTemplateField(column string, value interface{}, row map[string]interface{}) {
+	field, ok := value.(map[string]interface{})
+	if !ok || len(field) == 0 {
+		if value != nil {
+			row[column] = value
+		}
+		return
+	}
+	for key := range field {
+		if key == "" || strings.Contains(key, ".") || strings.HasSuffix(key, templateDocumentJSONSuffix) {
+			row[column] = value
+			return
+		}
+	}
+	for key, v := range field {
+		flattenTemplateField(fmt.Sprintf("%s.%s", column, key), v, row)
+	}
+}
+
+// setTemplateField sets the value in the path of the fields, creating the maps on the way
+func setTemplateField(fields map[string]interface{}, path []string, value interface{}) error {
+	key := path[0]
+	if len(path) == 1 {
+		if _, exists := fields[key]; exists {
+			return fmt.Errorf("%s is already set", key)
+		}
+		fields[key] = value
+		return nil
+	}
+	if _, exists := fields[key]; !exists {
+		fields[key] = map[string]interface{}{}
+	}
+	field, ok := fields[key].(map[string]interface{})
+	if !ok {
+		return fmt.Errorf("%s is already set", key)
+	}
+	return setTemplateField(field, path[1:], value)
+}
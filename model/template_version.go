@@ -0,0 +1,132 @@
+/*
+ * Copyright (c) 2026 TFG Co <backend@tfgco.com>
+ * Author: TFG Co <backend@tfgco.com>
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy of
+ * this software and associated documentation files (the "Software"), to deal in
+ * the Software without restriction, including without limitation the rights to
+ * use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+ * the Software, and to permit persons to whom the Software is furnished to do so,
+ * subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+ * FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+ * COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+ * IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+ * CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+ */
+
+package model
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/satori/go.uuid"
+	"github.com/topfreegames/marathon/messages"
+)
+
+// TemplateVersion is an immutable copy of a template, a new one is created by every change
+type TemplateVersion struct {
+	ID          uuid.UUID              `sql:",pk" json:"id"`
+	TemplateID  uuid.UUID              `sql:",notnull" json:"templateId"`
+	AppID       uuid.UUID              `json:"appId"`
+	Version     int                    `json:"version"`
+	Name        string                 `json:"name"`
+	Locale      string                 `json:"locale"`
+	Category    string                 `json:"category"`
+	Service     string                 `json:"service"`
+	Defaults    map[string]interface{} `json:"defaults"`
+	Body        map[string]interface{} `json:"body"`
+	PushOptions *messages.PushOptions  `json:"pushOptions"`
+	CreatedBy   string                 `json:"createdBy"`
+	CreatedAt   int64                  `json:"createdAt"`
+}
+
+// NewTemplateVersion returns the version of the template as it is now, changed by createdBy
+func NewTemplateVersion(t *Template, createdBy string) *TemplateVersion {
+	return &TemplateVersion{
+		ID:          uuid.NewV4(),
+		TemplateID:  t.ID,
+		AppID:       t.AppID,
+		Version:     t.Version,
+		Name:        t.Name,
+		Locale:      t.Locale,
+		Category:    t.Category,
+		Service:     t.Service,
+		Defaults:    t.Defaults,
+		Body:        t.Body,
+		PushOptions: t.PushOptions,
+		CreatedBy:   createdBy,
+		CreatedAt:   time.Now().UnixNano(),
+	}
+}
+
+// Template returns the template as it was in the version
+func (v *TemplateVersion) Template() Template {
+	return Template{
+		ID:          v.TemplateID,
+		Name:        v.Name,
+		Locale:      v.Locale,
+		Category:    v.Category,
+		Service:     v.Service,
+		Defaults:    v.Defaults,
+		Body:        v.Body,
+		PushOptions: v.PushOptions,
+		CreatedBy:   v.CreatedBy,
+		AppID:       v.AppID,
+		Version:     v.Version,
+		CreatedAt:   v.CreatedAt,
+		UpdatedAt:   v.CreatedAt,
+	}
+}
+
+// Diff returns the fields that differ from the version to the other one, defaults, body and
+// push options are compared by key, e.g. body.alert
+func (v *TemplateVersion) Diff(other *TemplateVersion) map[string]*AuditChange {
+	return DiffAudit(v.fields(), other.fields())
+}
+
+func (v *TemplateVersion) fields() map[string]interface{} {
+	fields := map[string]interface{}{
+		"name":     v.Name,
+		"locale":   v.Locale,
+		"category": v.Category,
+		"service":  v.Service,
+	}
+	for key, value := range v.Defaults {
+		fields[fmt.Sprintf("defaults.%s", key)] = value
+	}
+	for key, value := range v.Body {
+		fields[fmt.Sprintf("body.%s", key)] = value
+	}
+	options := map[string]interface{}{}
+	if v.PushOptions != nil {
+		b, _ := json.Marshal(v.PushOptions)
+		json.Unmarshal(b, &options)
+	}
+	for key, value := range options {
+		fields[fmt.Sprintf("pushOptions.%s", key)] = value
+	}
+	return fields
+}
+
+// TemplateRollback is the payload used to roll a template back to one of its versions
+type TemplateRollback struct {
+	Version int `json:"version"`
+}
+
+// Validate implementation of the InputValidation interface
+func (r *TemplateRollback) Validate(c echo.Context) error {
+	valid := r.Version > 0
+	if !valid {
+		return InvalidField("version")
+	}
+	return nil
+}
@@ -0,0 +1,74 @@
+/*
+ * Copyright (c) 2026 TFG Co <backend@tfgco.com>
+ * Author: TFG Co <backend@tfgco.com>
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy of
+ * this software and associated documentation files (the "Software"), to deal in
+ * the Software without restriction, including without limitation the rights to
+ * use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+ * the Software, and to permit persons to whom the Software is furnished to do so,
+ * subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+ * FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+ * COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+ * IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+ * CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+ */
+
+package model
+
+import (
+	"math"
+	"time"
+)
+
+// JobThrottle compares the pushes sent by a throttled job with the pushes it should have sent
+// at its push rate
+type JobThrottle struct {
+	PushRate             float64 `json:"pushRate"`
+	TargetTokens         int     `json:"targetTokens"`
+	CompletedTokens      int     `json:"completedTokens"`
+	EstimatedCompletedAt int64   `json:"estimatedCompletedAt"`
+}
+
+// PushRate returns the max pushes per second of the job, if the job is spread over a duration
+// the rate sends its total tokens in that duration, which for direct jobs are counted with the
+// job filters. Zero means the job is not throttled
+func (j *Job) PushRate() float64 {
+	if j.MaxPushesPerSecond > 0 {
+		return float64(j.MaxPushesPerSecond)
+	}
+	if j.SpreadOver > 0 && j.TotalTokens > 0 {
+		return float64(j.TotalTokens) / time.Duration(j.SpreadOver).Seconds()
+	}
+	return 0
+}
+
+// GetThrottle returns the progress of the job compared to its push rate, nil if the job is not
+// throttled or has not started
+func (j *Job) GetThrottle(now time.Time) *JobThrottle {
+	rate := j.PushRate()
+	startedAt := j.StartsAt
+	if startedAt == 0 {
+		startedAt = j.CreatedAt
+	}
+	if rate == 0 || now.UnixNano() < startedAt {
+		return nil
+	}
+	elapsed := now.Sub(time.Unix(0, startedAt)).Seconds()
+	throttle := &JobThrottle{
+		PushRate:        rate,
+		TargetTokens:    int(math.Min(rate*elapsed, float64(j.TotalTokens))),
+		CompletedTokens: j.CompletedTokens,
+	}
+	remaining := j.TotalTokens - j.CompletedTokens
+	if remaining > 0 {
+		throttle.EstimatedCompletedAt = now.Add(time.Duration(float64(remaining) / rate * float64(time.Second))).UnixNano()
+	}
+	return throttle
+}
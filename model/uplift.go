@@ -0,0 +1,74 @@
+/*
+ * Copyright (c) 2026 TFG Co <backend@tfgco.com>
+ * Author: TFG Co <backend@tfgco.com>
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy of
+ * this software and associated documentation files (the "Software"), to deal in
+ * the Software without restriction, including without limitation the rights to
+ * use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+ * the Software, and to permit persons to whom the Software is furnished to do so,
+ * subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+ * FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+ * COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+ * IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+ * CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+ */
+
+package model
+
+import (
+	"strings"
+
+	"github.com/labstack/echo/v4"
+)
+
+// Uplift report statuses
+const (
+	UpliftReportPending   = "pending"
+	UpliftReportCompleted = "completed"
+	UpliftReportFailed    = "failed"
+)
+
+// UpliftRequest is the payload used to create the uplift report of a job
+type UpliftRequest struct {
+	EventsPath string `json:"eventsPath"`
+	Event      string `json:"event"`
+}
+
+// Validate implementation of the InputValidation interface
+func (r *UpliftRequest) Validate(c echo.Context) error {
+	if strings.TrimSpace(r.EventsPath) == "" {
+		return InvalidField("eventsPath")
+	}
+	return nil
+}
+
+// UpliftGroup has the conversions of the treated or of the control group users
+type UpliftGroup struct {
+	Users          int     `json:"users"`
+	Conversions    int     `json:"conversions"`
+	ConversionRate float64 `json:"conversionRate"`
+}
+
+// UpliftReport compares the conversions of the users that received the job with the ones
+// of the control group
+type UpliftReport struct {
+	Status             string      `json:"status"`
+	Error              string      `json:"error,omitempty"`
+	EventsPath         string      `json:"eventsPath"`
+	Event              string      `json:"event"`
+	Treated            UpliftGroup `json:"treated"`
+	Control            UpliftGroup `json:"control"`
+	AbsoluteUplift     float64     `json:"absoluteUplift"`
+	RelativeUplift     float64     `json:"relativeUplift"`
+	ConfidenceInterval []float64   `json:"confidenceInterval"`
+	CreatedBy          string      `json:"createdBy"`
+	CreatedAt          int64       `json:"createdAt"`
+	CompletedAt        int64       `json:"completedAt"`
+}
@@ -30,13 +30,15 @@ import (
 
 // User is the user model struct
 type User struct {
-	ID          uuid.UUID   `sql:",pk" json:"id"`
-	Email       string      `json:"email"`
-	IsAdmin     bool        `sql:",notnull" json:"isAdmin"`
-	AllowedApps []uuid.UUID `pg:",array" json:"allowedApps"`
-	CreatedBy   string      `json:"createdBy"`
-	CreatedAt   int64       `json:"createdAt"`
-	UpdatedAt   int64       `json:"updatedAt"`
+	ID          uuid.UUID           `sql:",pk" json:"id"`
+	Email       string              `json:"email"`
+	IsAdmin     bool                `sql:",notnull" json:"isAdmin"`
+	IsApprover  bool                `sql:",notnull" json:"isApprover"`
+	AllowedApps []uuid.UUID         `pg:",array" json:"allowedApps"`
+	AppRoles    map[string][]string `json:"appRoles"`
+	CreatedBy   string              `json:"createdBy"`
+	CreatedAt   int64               `json:"createdAt"`
+	UpdatedAt   int64               `json:"updatedAt"`
 }
 
 // Validate implementation of the InputValidation interface
@@ -49,5 +51,14 @@ func (u *User) Validate(c echo.Context) error {
 	if !valid {
 		return InvalidField("createdBy")
 	}
+	valid = validateAppRoles(u.AppRoles)
+	if !valid {
+		return InvalidField("appRoles")
+	}
 	return nil
 }
+
+// CanApprove returns true if the user can approve the jobs of the app
+func (u *User) CanApprove(appID uuid.UUID) bool {
+	return u.IsAdmin || u.IsApprover || u.HasAppRole(appID, RoleApprover)
+}
@@ -0,0 +1,92 @@
+/*
+ * Copyright (c) 2026 TFG Co <backend@tfgco.com>
+ * Author: TFG Co <backend@tfgco.com>
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy of
+ * this software and associated documentation files (the "Software"), to deal in
+ * the Software without restriction, including without limitation the rights to
+ * use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+ * the Software, and to permit persons to whom the Software is furnished to do so,
+ * subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+ * FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+ * COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+ * IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+ * CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+ */
+
+package model
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// VariantSent is the variant feedbacks key that counts the pushes sent to the kafka
+const VariantSent = "sent"
+
+// TemplateNames returns the templates of the job, each one is a variant if there are many
+func (j *Job) TemplateNames() []string {
+	return strings.Split(j.TemplateName, ",")
+}
+
+// HasVariants returns true if the users of the job are split between many templates
+func (j *Job) HasVariants() bool {
+	return len(j.TemplateNames()) > 1
+}
+
+// ValidTemplateWeights returns true if the weights are empty, the templates are picked with the
+// same probability, or if every template of the job has a positive weight
+func (j *Job) ValidTemplateWeights() bool {
+	if len(j.TemplateWeights) == 0 {
+		return true
+	}
+	names := j.TemplateNames()
+	if len(j.TemplateWeights) != len(names) {
+		return false
+	}
+	for _, name := range names {
+		if weight, ok := j.TemplateWeights[name]; !ok || weight <= 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// VariantFeedbacksIncrQuery returns the query that increments the variant feedbacks of the job
+func VariantFeedbacksIncrQuery(jobID string, values map[string]map[string]int) (string, []interface{}) {
+	variants := []string{}
+	for variant := range values {
+		variants = append(variants, variant)
+	}
+	sort.Strings(variants)
+
+	objects := []string{}
+	params := []interface{}{}
+	for _, variant := range variants {
+		keys := []string{}
+		for key := range values[variant] {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		pairs := []string{}
+		variantParams := []interface{}{}
+		for _, key := range keys {
+			pairs = append(pairs, "?::text, COALESCE(variant_feedbacks->?->>?, '0')::int + ?")
+			variantParams = append(variantParams, key, variant, key, values[variant][key])
+		}
+		objects = append(objects, fmt.Sprintf("?::text, COALESCE(variant_feedbacks->?, '{}'::jsonb) || jsonb_build_object(%s)", strings.Join(pairs, ", ")))
+		params = append(params, variant, variant)
+		params = append(params, variantParams...)
+	}
+	query := fmt.Sprintf("UPDATE jobs SET variant_feedbacks = variant_feedbacks || jsonb_build_object(%s) WHERE id = ?", strings.Join(objects, ", "))
+	params = append(params, jobID)
+	return query, params
+}
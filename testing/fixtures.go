@@ -30,6 +30,7 @@ import (
 	"github.com/onsi/gomega"
 	uuid "github.com/satori/go.uuid"
 	"github.com/topfreegames/marathon/interfaces"
+	"github.com/topfreegames/marathon/messages"
 	"github.com/topfreegames/marathon/model"
 )
 
@@ -54,6 +55,9 @@ func CreateTestApp(db interfaces.DB, options ...map[string]interface{}) *model.A
 	app.Name = getOpt(opts, "name", "testapp").(string)
 	app.BundleID = getOpt(opts, "bundleId", fmt.Sprintf("com.app.%s", strings.Split(uuid.NewV4().String(), "-")[0])).(string)
 	app.CreatedBy = getOpt(opts, "createdBy", fmt.Sprintf("%s@test.com", strings.Split(uuid.NewV4().String(), "-")[0])).(string)
+	app.HoldoutPercentage = getOpt(opts, "holdoutPercentage", 0.0).(float64)
+	app.HoldoutSalt = getOpt(opts, "holdoutSalt", "").(string)
+	app.RequiresApproval = getOpt(opts, "requiresApproval", false).(bool)
 
 	err := db.Insert(&app)
 	gomega.Expect(err).NotTo(gomega.HaveOccurred())
@@ -82,6 +86,8 @@ func CreateTestUser(db interfaces.DB, options ...map[string]interface{}) *model.
 	user.ID = getOpt(opts, "id", uuid.NewV4()).(uuid.UUID)
 	user.Email = getOpt(opts, "email", fmt.Sprintf("%s@test.com", strings.Split(uuid.NewV4().String(), "-")[0])).(string)
 	user.IsAdmin = getOpt(opts, "isAdmin", true).(bool)
+	user.IsApprover = getOpt(opts, "isApprover", false).(bool)
+	user.AppRoles = getOpt(opts, "appRoles", map[string][]string{}).(map[string][]string)
 	user.AllowedApps = getOpt(opts, "allowedApps", []uuid.UUID{uuid.NewV4()}).([]uuid.UUID)
 	user.CreatedBy = getOpt(opts, "createdBy", fmt.Sprintf("%s@test.com", strings.Split(uuid.NewV4().String(), "-")[0])).(string)
 
@@ -90,6 +96,30 @@ func CreateTestUser(db interfaces.DB, options ...map[string]interface{}) *model.
 	return user
 }
 
+// CreateTestAPIToken for the user with specified optional values, the token is returned in its Token field
+func CreateTestAPIToken(db interfaces.DB, userID uuid.UUID, options ...map[string]interface{}) *model.APIToken {
+	opts := map[string]interface{}{}
+	if len(options) == 1 {
+		opts = options[0]
+	}
+
+	token := &model.APIToken{}
+	token.ID = getOpt(opts, "id", uuid.NewV4()).(uuid.UUID)
+	token.UserID = userID
+	token.Name = getOpt(opts, "name", "test token").(string)
+	token.Scopes = getOpt(opts, "scopes", []string{model.RoleAppAdmin}).([]string)
+	token.ExpiresAt = getOpt(opts, "expiresAt", int64(0)).(int64)
+	token.RevokedAt = getOpt(opts, "revokedAt", int64(0)).(int64)
+	token.CreatedBy = getOpt(opts, "createdBy", "test@test.com").(string)
+	token.CreatedAt = time.Now().UnixNano()
+
+	err := token.GenerateToken()
+	gomega.Expect(err).NotTo(gomega.HaveOccurred())
+	err = db.Insert(token)
+	gomega.Expect(err).NotTo(gomega.HaveOccurred())
+	return token
+}
+
 // CreateTestUsers for n users
 func CreateTestUsers(db interfaces.DB, n int, options ...map[string]interface{}) []*model.User {
 	users := make([]*model.User, n)
@@ -145,7 +175,7 @@ func CreateTestTemplate(db interfaces.DB, appID uuid.UUID, options ...map[string
 	}
 
 	defaults := getOpt(opts, "defaults", map[string]interface{}{"value": uuid.NewV4().String()}).(map[string]interface{})
-	body := getOpt(opts, "body", map[string]interface{}{"value": uuid.NewV4().String()}).(map[string]interface{})
+	body := getOpt(opts, "body", map[string]interface{}{"alert": uuid.NewV4().String()}).(map[string]interface{})
 
 	template := &model.Template{}
 	template.AppID = appID
@@ -155,9 +185,14 @@ func CreateTestTemplate(db interfaces.DB, appID uuid.UUID, options ...map[string
 	template.Name = getOpt(opts, "name", uuid.NewV4().String()).(string)
 	template.Locale = getOpt(opts, "locale", strings.Split(uuid.NewV4().String(), "-")[0]).(string)
 	template.CreatedBy = getOpt(opts, "createdBy", fmt.Sprintf("%s@test.com", strings.Split(uuid.NewV4().String(), "-")[0])).(string)
+	template.Service = getOpt(opts, "service", "").(string)
+	template.PushOptions, _ = getOpt(opts, "pushOptions", nil).(*messages.PushOptions)
+	template.Version = 1
 
 	err := db.Insert(&template)
 	gomega.Expect(err).NotTo(gomega.HaveOccurred())
+	err = db.Insert(model.NewTemplateVersion(template, template.CreatedBy))
+	gomega.Expect(err).NotTo(gomega.HaveOccurred())
 	return template
 }
 
@@ -184,7 +219,7 @@ func GetTemplatePayload(options ...map[string]interface{}) map[string]interface{
 	locale := getOpt(opts, "locale", strings.Split(uuid.NewV4().String(), "-")[0]).(string)
 
 	defaults := getOpt(opts, "defaults", map[string]interface{}{"value": uuid.NewV4().String()}).(map[string]interface{})
-	body := getOpt(opts, "body", map[string]interface{}{"value": uuid.NewV4().String()}).(map[string]interface{})
+	body := getOpt(opts, "body", map[string]interface{}{"alert": uuid.NewV4().String()}).(map[string]interface{})
 
 	template := map[string]interface{}{
 		"name":     name,
@@ -233,7 +268,10 @@ func CreateTestJob(db interfaces.DB, appID uuid.UUID, templateName string, optio
 	job.ID = getOpt(opts, "id", uuid.NewV4()).(uuid.UUID)
 	job.Service = getOpt(opts, "service", "apns").(string)
 	job.CSVPath = getOpt(opts, "csvPath", "").(string)
+	job.ControlGroupCSVPath = getOpt(opts, "controlGroupCsvPath", "").(string)
+	job.TreatedCSVPath = getOpt(opts, "treatedCsvPath", "").(string)
 	job.PastTimeStrategy = getOpt(opts, "pastTimeStrategy", "").(string)
+	job.Priority = getOpt(opts, "priority", "").(string)
 	job.ExpiresAt = getOpt(opts, "expiresAt", time.Now().Add(time.Hour).UnixNano()).(int64)
 	job.CreatedBy = getOpt(opts, "createdBy", fmt.Sprintf("%s@test.com", strings.Split(uuid.NewV4().String(), "-")[0])).(string)
 	job.StartsAt = getOpt(opts, "startsAt", time.Now().Add(time.Hour).UnixNano()).(int64)
@@ -287,3 +325,68 @@ func GetJobPayload(options ...map[string]interface{}) map[string]interface{} {
 	}
 	return job
 }
+
+// CreateTestSchedule with specified optional values
+func CreateTestSchedule(db interfaces.DB, appID uuid.UUID, templateName string, options ...map[string]interface{}) *model.Schedule {
+	opts := map[string]interface{}{}
+	if len(options) == 1 {
+		opts = options[0]
+	}
+
+	filters := getOpt(opts, "filters", map[string]interface{}{"locale": strings.Split(uuid.NewV4().String(), "-")[0]}).(map[string]interface{})
+	context := getOpt(opts, "context", map[string]interface{}{"value": uuid.NewV4().String()}).(map[string]interface{})
+	metadata := getOpt(opts, "metadata", map[string]interface{}{"meta": uuid.NewV4().String()}).(map[string]interface{})
+
+	schedule := &model.Schedule{}
+	schedule.ID = getOpt(opts, "id", uuid.NewV4()).(uuid.UUID)
+	schedule.AppID = appID
+	schedule.TemplateName = templateName
+	schedule.Filters = filters
+	schedule.Metadata = metadata
+	schedule.Context = context
+	schedule.CronExpression = getOpt(opts, "cronExpression", "0 10 * * *").(string)
+	schedule.Timezone = getOpt(opts, "timezone", "UTC").(string)
+	schedule.EndsAt = getOpt(opts, "endsAt", int64(0)).(int64)
+	schedule.MaxRuns = getOpt(opts, "maxRuns", 0).(int)
+	schedule.TotalRuns = getOpt(opts, "totalRuns", 0).(int)
+	schedule.NextRunAt = getOpt(opts, "nextRunAt", time.Now().Add(time.Hour).UnixNano()).(int64)
+	schedule.Paused = getOpt(opts, "paused", false).(bool)
+	schedule.Service = getOpt(opts, "service", "apns").(string)
+	schedule.CSVPath = getOpt(opts, "csvPath", "").(string)
+	schedule.ControlGroup = getOpt(opts, "controlGroup", 0.0).(float64)
+	schedule.ExpiresIn = getOpt(opts, "expiresIn", int64(0)).(int64)
+	schedule.CreatedBy = getOpt(opts, "createdBy", fmt.Sprintf("%s@test.com", strings.Split(uuid.NewV4().String(), "-")[0])).(string)
+	schedule.CreatedAt = time.Now().UnixNano()
+	schedule.UpdatedAt = time.Now().UnixNano()
+
+	err := db.Insert(&schedule)
+	gomega.Expect(err).NotTo(gomega.HaveOccurred())
+	return schedule
+}
+
+// GetSchedulePayload with specified optional values
+func GetSchedulePayload(options ...map[string]interface{}) map[string]interface{} {
+	opts := map[string]interface{}{}
+	if len(options) == 1 {
+		opts = options[0]
+	}
+
+	filters := getOpt(opts, "filters", map[string]interface{}{"locale": strings.Split(uuid.NewV4().String(), "-")[0]}).(map[string]interface{})
+	context := getOpt(opts, "context", map[string]interface{}{"value": uuid.NewV4().String()}).(map[string]interface{})
+	metadata := getOpt(opts, "metadata", map[string]interface{}{"meta": uuid.NewV4().String()}).(map[string]interface{})
+
+	schedule := map[string]interface{}{
+		"cronExpression": getOpt(opts, "cronExpression", "0 10 * * *").(string),
+		"timezone":       getOpt(opts, "timezone", "America/Sao_Paulo").(string),
+		"endsAt":         getOpt(opts, "endsAt", int64(0)).(int64),
+		"maxRuns":        getOpt(opts, "maxRuns", 0).(int),
+		"expiresIn":      getOpt(opts, "expiresIn", int64(time.Hour)).(int64),
+		"service":        getOpt(opts, "service", "apns").(string),
+		"csvPath":        getOpt(opts, "csvPath", "").(string),
+		"controlGroup":   getOpt(opts, "controlGroup", 0.0).(float64),
+		"filters":        filters,
+		"context":        context,
+		"metadata":       metadata,
+	}
+	return schedule
+}
@@ -62,7 +62,7 @@ func NewFakeKafkaProducer() *FakeKafkaProducer {
 }
 
 // SendAPNSPush for testing
-func (f *FakeKafkaProducer) SendAPNSPush(topic, deviceToken string, payload, messageMetadata map[string]interface{}, pushMetadata map[string]interface{}, pushExpiry int64, templateName string) error {
+func (f *FakeKafkaProducer) SendAPNSPush(topic, deviceToken string, payload, messageMetadata map[string]interface{}, pushMetadata map[string]interface{}, pushExpiry int64, templateName string, options *messages.PushOptions) error {
 	msg := messages.NewAPNSMessage(
 		deviceToken,
 		pushExpiry,
@@ -71,6 +71,7 @@ func (f *FakeKafkaProducer) SendAPNSPush(topic, deviceToken string, payload, mes
 		pushMetadata,
 		templateName,
 	)
+	msg.SetOptions(options)
 
 	if val, ok := pushMetadata["dryRun"]; ok {
 		if dryRun, _ := val.(bool); dryRun {
@@ -89,7 +90,7 @@ func (f *FakeKafkaProducer) SendAPNSPush(topic, deviceToken string, payload, mes
 }
 
 // SendGCMPush for testing
-func (f *FakeKafkaProducer) SendGCMPush(topic, deviceToken string, payload, messageMetadata map[string]interface{}, pushMetadata map[string]interface{}, pushExpiry int64, templateName string) error {
+func (f *FakeKafkaProducer) SendGCMPush(topic, deviceToken string, payload, messageMetadata map[string]interface{}, pushMetadata map[string]interface{}, pushExpiry int64, templateName string, options *messages.PushOptions) error {
 	msg := messages.NewGCMMessage(
 		deviceToken,
 		payload,
@@ -98,6 +99,7 @@ func (f *FakeKafkaProducer) SendGCMPush(topic, deviceToken string, payload, mess
 		pushExpiry,
 		templateName,
 	)
+	msg.SetOptions(options)
 
 	if val, ok := pushMetadata["dryRun"]; ok {
 		if dryRun, _ := val.(bool); dryRun {
@@ -337,9 +339,10 @@ func (s *FakeS3) CompleteMultipartUpload(multipartUpload *s3.CreateMultipartUplo
 	fullPath := *multipartUpload.Key
 	buffer := bytes.NewBufferString("")
 
-	for _, b := range s.multipart[fullPath] {
-		buffer.Write(b)
+	for i := int64(0); i < int64(len(s.multipart[fullPath])); i++ {
+		buffer.Write(s.multipart[fullPath][i])
 	}
+	delete(s.multipart, fullPath)
 	bytesTemp := buffer.Bytes()
 	s.PutObject(*multipartUpload.Key, &bytesTemp)
 	return nil
@@ -460,7 +463,23 @@ func Delete(app *api.Application, url, auth string) (int, string) {
 	return doRequest(app, "DELETE", url, "", auth)
 }
 
+//RequestWithToken sends a request to server authenticated by an API token
+func RequestWithToken(app *api.Application, method, url, body, token string) (int, string) {
+	return RequestWithHeaders(app, method, url, body, map[string]string{
+		"Authorization": fmt.Sprintf("Bearer %s", token),
+	})
+}
+
 func doRequest(app *api.Application, method, url, body, auth string) (int, string) {
+	headers := map[string]string{}
+	if auth != "" {
+		headers["x-forwarded-email"] = auth
+	}
+	return RequestWithHeaders(app, method, url, body, headers)
+}
+
+//RequestWithHeaders sends a request to server with the headers
+func RequestWithHeaders(app *api.Application, method, url, body string, headers map[string]string) (int, string) {
 	ts := httptest.NewServer(app.API)
 	defer ts.Close()
 
@@ -470,8 +489,8 @@ func doRequest(app *api.Application, method, url, body, auth string) (int, strin
 	}
 	req, err := http.NewRequest(method, fmt.Sprintf("%s%s", ts.URL, url), reader)
 	gomega.Expect(err).NotTo(gomega.HaveOccurred())
-	if auth != "" {
-		req.Header.Add("x-forwarded-email", auth)
+	for k, v := range headers {
+		req.Header.Add(k, v)
 	}
 
 	client := &http.Client{}
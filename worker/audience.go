@@ -0,0 +1,174 @@
+/*
+ * Copyright (c) 2026 TFG Co <backend@tfgco.com>
+ * Author: TFG Co <backend@tfgco.com>
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy of
+ * this software and associated documentation files (the "Software"), to deal in
+ * the Software without restriction, including without limitation the rights to
+ * use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+ * the Software, and to permit persons to whom the Software is furnished to do so,
+ * subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+ * FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+ * COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+ * IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+ * CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+ */
+
+package worker
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"math"
+
+	"github.com/topfreegames/marathon/interfaces"
+	"github.com/topfreegames/marathon/model"
+	pg "gopkg.in/pg.v5"
+)
+
+// Audience is the estimate of how many tokens a job would reach in a service
+type Audience struct {
+	Tokens       int            `json:"tokens"`
+	ControlGroup int            `json:"controlGroup"`
+	Locales      map[string]int `json:"locales"`
+	Timezones    map[string]int `json:"timezones"`
+}
+
+type audienceCount struct {
+	Locale string
+	Tz     string
+	Tokens int
+}
+
+func newAudience() *Audience {
+	return &Audience{
+		Locales:   map[string]int{},
+		Timezones: map[string]int{},
+	}
+}
+
+func (a *Audience) add(counts []audienceCount) {
+	for _, count := range counts {
+		a.Tokens += count.Tokens
+		a.Locales[count.Locale] += count.Tokens
+		a.Timezones[count.Tz] += count.Tokens
+	}
+}
+
+// the workers cut the control group from each batch, this is the same cut made once
+func (a *Audience) setControlGroup(controlGroup float64) {
+	a.ControlGroup = int(math.Ceil(float64(a.Tokens) * controlGroup))
+}
+
+// EstimateAudienceFromFilters counts the push db tokens that match the job filters
+func EstimateAudienceFromFilters(db interfaces.DB, job *model.Job) (*Audience, error) {
+	whereClause, params, err := GetWhereClauseFromFilters(job.Filters)
+	if err != nil {
+		return nil, err
+	}
+	query := fmt.Sprintf("SELECT locale, tz, count(*) AS tokens FROM %s", GetPushDBTableName(job.App.Name, job.Service))
+	if whereClause != "" {
+		query = fmt.Sprintf("%s WHERE %s", query, whereClause)
+	}
+	query = fmt.Sprintf("%s GROUP BY locale, tz", query)
+
+	var counts []audienceCount
+	_, err = db.Query(&counts, query, params...)
+	if err != nil {
+		return nil, err
+	}
+	audience := newAudience()
+	audience.add(counts)
+	audience.setControlGroup(job.ControlGroup)
+	return audience, nil
+}
+
+// EstimateAudienceFromCSV counts the push db tokens of the users in the job csv, the csv
+// is downloaded in chunks of chunkSize bytes and the users are counted in batches of batchSize
+func EstimateAudienceFromCSV(db interfaces.DB, s3 interfaces.S3, job *model.Job, chunkSize, batchSize int) (*Audience, error) {
+	totalSize, _, err := s3.DownloadChunk(0, 1, job.CSVPath)
+	if err != nil {
+		return nil, err
+	}
+
+	query := fmt.Sprintf("SELECT locale, tz, count(*) AS tokens FROM %s WHERE user_id IN (?) GROUP BY locale, tz", GetPushDBTableName(job.App.Name, job.Service))
+	audience := newAudience()
+	countUsers := func(ids []string) error {
+		for len(ids) > 0 {
+			size := batchSize
+			if size > len(ids) {
+				size = len(ids)
+			}
+			var counts []audienceCount
+			_, err := db.Query(&counts, query, pg.In(ids[:size]))
+			if err != nil {
+				return err
+			}
+			audience.add(counts)
+			ids = ids[size:]
+		}
+		return nil
+	}
+
+	// lines can be split between chunks, the incomplete last line is kept for the next one
+	var pending []byte
+	isFirstLine := true
+	for start := 0; start < totalSize; start += chunkSize {
+		size := totalSize - start
+		if size > chunkSize {
+			size = chunkSize
+		}
+		_, buffer, err := s3.DownloadChunk(int64(start), int64(size), job.CSVPath)
+		if err != nil {
+			return nil, err
+		}
+		data := append(pending, buffer.Bytes()...)
+		pending = nil
+		if start+size < totalSize {
+			end := bytes.LastIndexAny(data, "\r\n")
+			if end < 0 {
+				pending = data
+				continue
+			}
+			pending = append([]byte{}, data[end+1:]...)
+			data = data[:end+1]
+		}
+
+		ids, err := readCSVUserIDs(data, isFirstLine)
+		if err != nil {
+			return nil, err
+		}
+		isFirstLine = false
+		err = countUsers(ids)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	audience.setControlGroup(job.ControlGroup)
+	return audience, nil
+}
+
+// readCSVUserIDs returns the first column of the csv lines, the header is skipped if hasHeader
+func readCSVUserIDs(data []byte, hasHeader bool) ([]string, error) {
+	data = bytes.Replace(data, []byte{0x0D}, []byte{0x0A}, -1)
+	lines, err := csv.NewReader(bytes.NewReader(data)).ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	ids := []string{}
+	for i, line := range lines {
+		if i == 0 && hasHeader {
+			continue
+		}
+		ids = append(ids, line[0])
+	}
+	return ids, nil
+}
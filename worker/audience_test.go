@@ -0,0 +1,98 @@
+/*
+ * Copyright (c) 2026 TFG Co <backend@tfgco.com>
+ * Author: TFG Co <backend@tfgco.com>
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy of
+ * this software and associated documentation files (the "Software"), to deal in
+ * the Software without restriction, including without limitation the rights to
+ * use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+ * the Software, and to permit persons to whom the Software is furnished to do so,
+ * subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+ * FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+ * COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+ * IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+ * CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+ */
+
+package worker_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/topfreegames/marathon/model"
+	. "github.com/topfreegames/marathon/testing"
+	"github.com/topfreegames/marathon/worker"
+	"github.com/uber-go/zap"
+)
+
+var _ = Describe("Audience", func() {
+	logger := zap.New(
+		zap.NewJSONEncoder(zap.NoTime()),
+		zap.FatalLevel,
+	)
+	w := worker.NewWorker(logger, GetConfPath())
+	var fakeS3 *FakeS3
+	var job *model.Job
+
+	BeforeEach(func() {
+		fakeS3 = NewFakeS3(w.Config)
+		data := []byte(`userids
+9e558649-9c23-469d-a11c-59b05813e3d5
+57be9009-e616-42c6-9cfe-505508ede2d0
+a8e8d2d5-f178-4d90-9b31-683ad3aae920
+5c3033c0-24ad-487a-a80d-68432464c8de
+4223171e-c665-4612-9edd-485f229240bf
+2df5bb01-15d1-4569-bc56-49fa0a33c4c3
+user_id
+idisnotanuuidanditssizecanvaryforeachuser
+gamesendsanuseridthatisnotanuuid
+843a61f8-45b3-44f9-9ab7-8becb2765653`)
+		fakeS3.PutObject("test/jobs/obj1.csv", &data)
+
+		job = &model.Job{
+			App:          model.App{Name: "testapp"},
+			Service:      "apns",
+			CSVPath:      "test/jobs/obj1.csv",
+			ControlGroup: 0.1,
+		}
+	})
+
+	Describe("Estimate audience from csv", func() {
+		It("should count the tokens of the csv users whatever the chunk size", func() {
+			for _, chunkSize := range []int{1, 7, 64, 10 * 1024 * 1024} {
+				audience, err := worker.EstimateAudienceFromCSV(w.PushDB, fakeS3, job, chunkSize, 3)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(audience.Tokens).To(Equal(10))
+				Expect(audience.ControlGroup).To(Equal(1))
+				Expect(audience.Locales).To(Equal(map[string]int{"pt": 6, "en": 4}))
+				Expect(audience.Timezones).To(Equal(map[string]int{"-0300": 7, "-0500": 3}))
+			}
+		})
+
+		It("should fail if the csv does not exist", func() {
+			job.CSVPath = "test/jobs/notfound.csv"
+			_, err := worker.EstimateAudienceFromCSV(w.PushDB, fakeS3, job, 1024, 3)
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Describe("Estimate audience from filters", func() {
+		It("should count the tokens that match the filters", func() {
+			job.CSVPath = ""
+			job.Filters = map[string]interface{}{
+				"locale": "pt",
+				"NOTtz":  "-0500",
+			}
+			audience, err := worker.EstimateAudienceFromFilters(w.PushDB, job)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(audience.Locales).To(Equal(map[string]int{"pt": audience.Tokens}))
+			Expect(audience.Timezones).NotTo(HaveKey("-0500"))
+		})
+	})
+})
@@ -59,8 +59,8 @@ func NewCreateBatchesWorker(workers *Worker) *CreateBatchesWorker {
 	return b
 }
 
-// ReadFromCSV reads CSV from S3 and return correspondent array of strings
-func (b *CreateBatchesWorker) ReadFromCSV(buffer *[]byte, msg *BatchPart) []string {
+// ReadFromCSV reads CSV from S3 and return correspondent array of rows
+func (b *CreateBatchesWorker) ReadFromCSV(buffer *[]byte, msg *BatchPart) [][]string {
 	job := &msg.Job
 	for i, b := range *buffer {
 		if b == 0x0D {
@@ -68,19 +68,45 @@ func (b *CreateBatchesWorker) ReadFromCSV(buffer *[]byte, msg *BatchPart) []stri
 		}
 	}
 
-	r := csv.NewReader(bytes.NewReader(*buffer))
-	lines, err := r.ReadAll()
+	lines, err := readCSVRows(*buffer)
 	b.checkErr(job, err)
-	res := []string{}
+	res := [][]string{}
 	for i, line := range lines {
 		if i == 0 && msg.Part == 0 {
 			continue
 		}
-		res = append(res, line[0])
+		res = append(res, line)
 	}
 	return res
 }
 
+func readCSVRows(buffer []byte) ([][]string, error) {
+	r := csv.NewReader(bytes.NewReader(buffer))
+	// rows can have less columns than the header
+	r.FieldsPerRecord = -1
+	return r.ReadAll()
+}
+
+// getUserContexts returns the user ids of the csv rows and the values of the other columns by user id
+func getUserContexts(rows [][]string, columns []string) ([]string, map[string]map[string]interface{}) {
+	ids := make([]string, 0, len(rows))
+	contexts := map[string]map[string]interface{}{}
+	for _, row := range rows {
+		ids = append(ids, row[0])
+		if len(columns) == 0 {
+			continue
+		}
+		context := map[string]interface{}{}
+		for i, column := range columns {
+			if i+1 < len(row) {
+				context[column] = row[i+1]
+			}
+		}
+		contexts[row[0]] = context
+	}
+	return ids, contexts
+}
+
 func (b *CreateBatchesWorker) updateTotalBatches(totalBatches int, job *model.Job) {
 	job.TotalBatches = totalBatches
 	// coalesce is necessary since total_batches can be null
@@ -101,17 +127,21 @@ func (b *CreateBatchesWorker) updateCompletedAt(unixTime int64, job *model.Job)
 }
 
 func (b *CreateBatchesWorker) getUserBatchFromPG(userIds *[]string, job *model.Job) *[]User {
+	templatesByNameAndLocale, err := job.GetJobTemplatesByNameAndLocale(b.Workers.MarathonDB)
+	b.checkErr(job, err)
+	extraColumns := TemplateUserColumns(flattenTemplates(templatesByNameAndLocale)...)
+
 	var users []User
 	start := time.Now()
-	query := fmt.Sprintf("SELECT user_id, token, locale, tz FROM %s WHERE user_id IN (?)", GetPushDBTableName(job.App.Name, job.Service))
-	_, err := b.Workers.PushDB.Query(&users, query, pg.In(*userIds))
+	query := fmt.Sprintf("SELECT %s FROM %s WHERE user_id IN (?)", GetUsersSelect(extraColumns), GetPushDBTableName(job.App.Name, job.Service))
+	_, err = b.Workers.PushDB.Query(&users, query, pg.In(*userIds))
 	b.Workers.Statsd.Timing("get_csv_batch_from_pg", time.Now().Sub(start), job.Labels(), 1)
 
 	b.checkErr(job, err)
 	return &users
 }
 
-func (b *CreateBatchesWorker) processBatch(ids *[]string, job *model.Job) {
+func (b *CreateBatchesWorker) processBatch(ids *[]string, contexts map[string]map[string]interface{}, job *model.Job) {
 	if len(*ids) == 0 {
 		return
 	}
@@ -119,6 +149,9 @@ func (b *CreateBatchesWorker) processBatch(ids *[]string, job *model.Job) {
 
 	usersFromBatch := b.getUserBatchFromPG(ids, job)
 	numUsersFromBatch := len(*usersFromBatch)
+	for i, user := range *usersFromBatch {
+		(*usersFromBatch)[i].Context = contexts[user.UserID]
+	}
 	log.I(l, "got users from db", func(cm log.CM) {
 		cm.Write(zap.Int("usersInBatch", numUsersFromBatch))
 	})
@@ -136,7 +169,7 @@ func (b *CreateBatchesWorker) sendBatches(users []User, job *model.Job) {
 	log.I(l, "sending batch of users to process batches worker", func(cm log.CM) {
 		cm.Write(zap.Int("numUsers", len(users)))
 	})
-	_, err := b.Workers.CreateProcessBatchJob(job.ID.String(), job.App.Name, &users)
+	_, err := b.Workers.CreateProcessBatchJob(job.ID.String(), job.App.Name, job.Priority, &users)
 	b.checkErr(job, err)
 }
 
@@ -146,8 +179,16 @@ func (b *CreateBatchesWorker) updateTotalUsers(job *model.Job, totalUsers int) {
 	b.checkErr(job, err)
 }
 
-func (b *CreateBatchesWorker) processIDs(userIds []string, msg *BatchPart) {
+func (b *CreateBatchesWorker) processIDs(rows [][]string, msg *BatchPart) {
 	l := b.Logger
+	userIds, contexts := getUserContexts(rows, msg.Columns)
+	// remove the users of the app holdout group, they never receive the jobs that use it
+	if msg.Job.UseHoldout {
+		remaining := RemoveHoldoutUsers(&msg.Job, userIds)
+		err := IncrHoldoutUsers(b.Workers.MarathonDB, &msg.Job, len(userIds)-len(remaining))
+		b.checkErr(&msg.Job, err)
+		userIds = remaining
+	}
 	// create a controll group if needed
 	controlGroupSize := int(math.Ceil(float64(len(userIds)) * msg.Job.ControlGroup))
 	if controlGroupSize > 0 {
@@ -179,36 +220,40 @@ func (b *CreateBatchesWorker) processIDs(userIds []string, msg *BatchPart) {
 	}
 
 	// pull from db and send to kafta
-	b.processBatch(&userIds, &msg.Job)
+	b.processBatch(&userIds, contexts, &msg.Job)
 }
 
-// get the list of ids and send to redis the splited ids
-func (b *CreateBatchesWorker) getIDs(buffer *bytes.Buffer, msg *BatchPart) []string {
-	bBystes := buffer.Bytes()
-	lines := b.ReadFromCSV(&bBystes, msg)
-	totalLines := len(lines)
-	start := 0
-	end := totalLines
+// get the list of rows and send to redis the splited lines
+func (b *CreateBatchesWorker) getRows(buffer *bytes.Buffer, msg *BatchPart) [][]string {
+	bBystes := bytes.Replace(buffer.Bytes(), []byte{0x0D}, []byte{0x0A}, -1)
 
-	// is not the first part
-	if msg.Part != 0 && totalLines > 0 {
+	// is not the first part, the text before the first line break ends the last line of the previous part
+	if msg.Part != 0 {
+		ini := bBystes
+		bBystes = []byte{}
+		if idx := bytes.IndexByte(ini, 0x0A); idx >= 0 {
+			ini, bBystes = ini[:idx], ini[idx+1:]
+		}
 		str := fmt.Sprintf("%s-INI-%d", msg.Job.ID, msg.Part)
-		b.Workers.RedisClient.Set(str, lines[0], 90*24*time.Hour)
-		start++
+		b.Workers.RedisClient.Set(str, string(ini), 90*24*time.Hour)
 	}
 
-	// is not the last part
+	// is not the last part, the text after the last line break starts a line of the next part
 	if msg.Part != msg.TotalParts-1 {
+		idx := bytes.LastIndexByte(bBystes, 0x0A)
+		end := bBystes[idx+1:]
+		bBystes = bBystes[:idx+1]
 		str := fmt.Sprintf("%s-END-%d", msg.Job.ID, msg.Part)
-		b.Workers.RedisClient.Set(str, lines[totalLines-1], 90*24*time.Hour)
-		end--
+		b.Workers.RedisClient.Set(str, string(end), 90*24*time.Hour)
 	}
 
-	return lines[start:end]
+	return b.ReadFromCSV(&bBystes, msg)
 }
 
-func (b *CreateBatchesWorker) getSplitedIds(totalParts int, job *model.Job) []string {
-	var ids []string
+func (b *CreateBatchesWorker) getSplitedRows(msg *BatchPart) [][]string {
+	totalParts := msg.TotalParts
+	job := &msg.Job
+	var lines []byte
 	for i := 0; i < totalParts-1; i++ {
 		begin := fmt.Sprintf("%s-INI-%d", job.ID, i+1)
 		end := fmt.Sprintf("%s-END-%d", job.ID, i)
@@ -224,14 +269,14 @@ func (b *CreateBatchesWorker) getSplitedIds(totalParts int, job *model.Job) []st
 		}
 		b.checkErr(job, err)
 
-		ids = append(ids, endStr+beginStr)
-		ids = append(ids, beginStr)
-		ids = append(ids, endStr)
+		lines = append(lines, endStr+beginStr+"\n"...)
 
 		b.Workers.RedisClient.Del(begin)
 		b.Workers.RedisClient.Del(end)
 	}
-	return ids
+	rows, err := readCSVRows(lines)
+	b.checkErr(job, err)
+	return rows
 }
 
 func (b *CreateBatchesWorker) setAsComplete(part int, job *model.Job) int {
@@ -279,17 +324,17 @@ func (b *CreateBatchesWorker) Process(message *goworkers2.Msg) error {
 	b.Workers.Statsd.Timing(GetCsvFromS3Timing, time.Now().Sub(start), labels, 1)
 	b.checkErr(&msg.Job, err)
 
-	ids := b.getIDs(buffer, &msg)
+	rows := b.getRows(buffer, &msg)
 
 	// pull from db, send to control and send to kafka
-	b.processIDs(ids, &msg)
+	b.processIDs(rows, &msg)
 
 	completedParts := b.setAsComplete(msg.Part, &msg.Job)
 
 	if completedParts == msg.TotalParts {
-		ids = b.getSplitedIds(msg.TotalParts, &msg.Job)
+		rows = b.getSplitedRows(&msg)
 
-		b.processIDs(ids, &msg)
+		b.processIDs(rows, &msg)
 
 		if msg.Job.TotalUsers == 0 {
 			_, err := b.Workers.MarathonDB.Model(&msg.Job).Set("status = 'stopped', updated_at = ?, completed_at = ?", time.Now().UnixNano(), time.Now().UnixNano()).Where("id = ?", msg.Job.ID).Update()
@@ -308,7 +353,7 @@ func (b *CreateBatchesWorker) Process(message *goworkers2.Msg) error {
 		str := fmt.Sprintf("complete part %d of %d", completedParts, msg.TotalParts)
 		msg.Job.TagRunning(b.Workers.MarathonDB, nameCreateBatches, str)
 	}
-	ids = nil
+	rows = nil
 
 	l.Info("finished")
 	
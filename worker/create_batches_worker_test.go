@@ -101,6 +101,11 @@ idisnotanuuidanditssizecanvaryforeachuser
 user_id
 `)
 
+		// Users with personalized values
+		fakeData10 := []byte(`userIds,coins,friend_name
+9e558649-9c23-469d-a11c-59b05813e3d5,10,Ana
+57be9009-e616-42c6-9cfe-505508ede2d0,1,"Bob, Jr"`)
+
 		fakeS3.PutObject("test/jobs/obj1.csv", &fakeData1)
 		fakeS3.PutObject("test/jobs/obj2.csv", &fakeData2)
 		fakeS3.PutObject("test/jobs/obj3.csv", &fakeData3)
@@ -110,6 +115,7 @@ user_id
 		fakeS3.PutObject("test/jobs/obj7.csv", &fakeData7)
 		fakeS3.PutObject("test/jobs/obj8.csv", &fakeData8)
 		fakeS3.PutObject("test/jobs/obj9.csv", &fakeData9)
+		fakeS3.PutObject("test/jobs/obj10.csv", &fakeData10)
 		app = CreateTestApp(w.MarathonDB)
 		defaults := map[string]interface{}{
 			"user_name":   "Someone",
@@ -275,6 +281,41 @@ user_id
 			Expect(res).To(BeEquivalentTo(0))
 		})
 
+		It("should send the batches of a high priority job to the high priority queue", func() {
+			j := CreateTestJob(w.MarathonDB, app.ID, template.Name, map[string]interface{}{
+				"context":  context,
+				"filters":  map[string]interface{}{},
+				"csvPath":  "test/jobs/obj1.csv",
+				"priority": "high",
+			})
+
+			_, err := w.CreateCSVSplitJob(j)
+			Expect(err).NotTo(HaveOccurred())
+
+			jobData, err := w.RedisClient.LPop("queue:csv_split_worker").Result()
+			Expect(err).NotTo(HaveOccurred())
+			msg, err := goworkers2.NewMsg(string(jobData))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(func() { createCSVSplitWorker.Process(msg) }).ShouldNot(Panic())
+
+			jobData, err = w.RedisClient.LPop("queue:create_batches_worker").Result()
+			Expect(err).NotTo(HaveOccurred())
+			msg, err = goworkers2.NewMsg(string(jobData))
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(func() { createBatchesWorker.Process(msg) }).ShouldNot(Panic())
+
+			res, err := w.RedisClient.LLen("queue:process_batch_worker").Result()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(res).To(BeEquivalentTo(0))
+			job1, err := w.RedisClient.LPop("queue:process_batch_worker_high").Result()
+			Expect(err).NotTo(HaveOccurred())
+			j1 := map[string]interface{}{}
+			err = json.Unmarshal([]byte(job1), &j1)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(j1["queue"].(string)).To(Equal("process_batch_worker_high"))
+		})
+
 		It("should create batches with the right tokens and tz and send to process_batches_worker", func() {
 
 			j := CreateTestJob(w.MarathonDB, app.ID, template.Name, map[string]interface{}{
@@ -965,6 +1006,64 @@ user_id
 		Expect(userIds["idisnotanuuidanditssizecanvaryforeachuser"]).To(BeEquivalentTo("idisnotanuuidanditssizecanvaryforeachuser"))
 	})
 
+	It("should send the csv columns of each user even when split a file in middle of a line", func() {
+		j := CreateTestJob(w.MarathonDB, app.ID, template.Name, map[string]interface{}{
+			"context": context,
+			"filters": map[string]interface{}{},
+			"csvPath": "test/jobs/obj10.csv",
+		})
+
+		_, err := w.CreateCSVSplitJob(j)
+		Expect(err).NotTo(HaveOccurred())
+
+		jobData, err := w.RedisClient.LPop("queue:csv_split_worker").Result()
+		Expect(err).NotTo(HaveOccurred())
+		msg, err := goworkers2.NewMsg(string(jobData))
+		Expect(err).NotTo(HaveOccurred())
+		// it's the size of the header + first line + part of the second line
+		sizeLimit := 90
+		totalParts := 2
+		createCSVSplitWorker.Workers.Config.Set("workers.csvSplitWorker.csvSizeLimitMB", float64(sizeLimit)/1024/1024)
+		Expect(func() { createCSVSplitWorker.Process(msg) }).ShouldNot(Panic())
+
+		for i := 0; i < totalParts; i++ {
+			jobData, err = w.RedisClient.LPop("queue:create_batches_worker").Result()
+			Expect(err).NotTo(HaveOccurred())
+			msg, err = goworkers2.NewMsg(string(jobData))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(func() { createBatchesWorker.Process(msg) }).ShouldNot(Panic())
+		}
+
+		res, err := w.RedisClient.LLen("queue:process_batch_worker").Result()
+		Expect(err).NotTo(HaveOccurred())
+		// one batch from the first part and 1 batch from split ids process
+		Expect(res).To(BeEquivalentTo(2))
+
+		contexts := map[string]map[string]interface{}{}
+		for i := 0; i < int(res); i++ {
+			job1, err := w.RedisClient.LPop("queue:process_batch_worker").Result()
+			Expect(err).NotTo(HaveOccurred())
+			j1 := map[string]interface{}{}
+			err = json.Unmarshal([]byte(job1), &j1)
+			Expect(err).NotTo(HaveOccurred())
+			wMessage1, err := worker.ParseProcessBatchWorkerMessageArray(j1["args"].([]interface{}))
+			Expect(err).NotTo(HaveOccurred())
+			for _, user := range wMessage1.Users {
+				contexts[user.UserID] = user.Context
+			}
+		}
+
+		Expect(contexts).To(HaveLen(2))
+		Expect(contexts["9e558649-9c23-469d-a11c-59b05813e3d5"]).To(Equal(map[string]interface{}{
+			"coins":       "10",
+			"friend_name": "Ana",
+		}))
+		Expect(contexts["57be9009-e616-42c6-9cfe-505508ede2d0"]).To(Equal(map[string]interface{}{
+			"coins":       "1",
+			"friend_name": "Bob, Jr",
+		}))
+	})
+
 	// Describe("Read CSV from S3", func() {
 	// 	It("should return correct array from Unix csv data", func() {
 	// 		res := w.ReadCSVFromS3("test/jobs/obj3.csv")
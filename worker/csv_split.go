@@ -26,11 +26,17 @@
 package worker
 
 import (
+	"bytes"
+	"encoding/csv"
 	"encoding/json"
+	"fmt"
 	goworkers2 "github.com/digitalocean/go-workers2"
 	"math"
+	"strings"
+	"time"
 
 	"github.com/satori/go.uuid"
+	"github.com/topfreegames/marathon/interfaces"
 	"github.com/topfreegames/marathon/log"
 	"github.com/topfreegames/marathon/model"
 	"github.com/uber-go/zap"
@@ -44,10 +50,16 @@ type BatchPart struct {
 	TotalSize  int
 	Part       int
 	Job        model.Job
+	// Columns are the csv header names after the user id column, their values are
+	// available to the templates of each user
+	Columns []string
 }
 
 const nameSCVSplit = "csv_split_worker"
 
+// the header is read from the beginning of the file, it must fit in this size
+const csvHeaderMaxSizeBytes = 64 * 1024
+
 // CSVSplitWorker is the CSVSplitWorker struct
 type CSVSplitWorker struct {
 	Workers *Worker
@@ -95,9 +107,22 @@ func (b *CSVSplitWorker) Process(message *goworkers2.Msg) error {
 		return nil
 	}
 
-	// get file information
-	totalSize, _, err := b.Workers.S3Client.DownloadChunk(0, 1, job.CSVPath)
+	totalSize, header, err := GetCSVHeader(b.Workers.S3Client, job.CSVPath)
 	b.checkErr(job, err)
+	columns, err := GetCSVColumns(header)
+	if err == nil {
+		err = ValidateCSVColumns(columns, job.Context)
+	}
+	if err != nil {
+		log.I(l, "invalid csv header", func(cm log.CM) {
+			cm.Write(zap.Error(err))
+		})
+		_, dbErr := b.Workers.MarathonDB.Model(job).Set("status = 'stopped', updated_at = ?", time.Now().UnixNano()).Where("id = ?", job.ID).Update()
+		b.checkErr(job, dbErr)
+		job.TagError(b.Workers.MarathonDB, nameSCVSplit, err.Error())
+		b.Workers.Statsd.Incr(CsvSplitWorkerError, job.Labels(), 1)
+		return nil
+	}
 
 	start := 0
 	totalParts := int(math.Ceil(float64(totalSize) / csvSizeLimit))
@@ -114,6 +139,7 @@ func (b *CSVSplitWorker) Process(message *goworkers2.Msg) error {
 			TotalSize:  totalSize,
 			Part:       i,
 			Job:        *job,
+			Columns:    columns,
 		})
 		b.checkErr(job, err)
 		start += size
@@ -142,3 +168,59 @@ func (b *CSVSplitWorker) getCSVSizeLimitBytes() float64 {
 
 	return csvSizeLimitMB * 1024 * 1024
 }
+
+// GetCSVHeader returns the size of the csv file and its first bytes, which hold the header
+func GetCSVHeader(s3 interfaces.S3, path string) (int, []byte, error) {
+	totalSize, _, err := s3.DownloadChunk(0, 1, path)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	headerSize := totalSize
+	if headerSize > csvHeaderMaxSizeBytes {
+		headerSize = csvHeaderMaxSizeBytes
+	}
+	_, header, err := s3.DownloadChunk(0, int64(headerSize), path)
+	if err != nil {
+		return 0, nil, err
+	}
+	return totalSize, header.Bytes(), nil
+}
+
+// GetCSVColumns returns the header names after the user id column, the first line of the
+// buffer is the header
+func GetCSVColumns(buffer []byte) ([]string, error) {
+	buffer = bytes.Replace(buffer, []byte{0x0D}, []byte{0x0A}, -1)
+	if idx := bytes.IndexByte(buffer, 0x0A); idx >= 0 {
+		buffer = buffer[:idx]
+	}
+	r := csv.NewReader(bytes.NewReader(buffer))
+	header, err := r.Read()
+	if err != nil {
+		return nil, fmt.Errorf("invalid csv header: %s", err.Error())
+	}
+	columns := []string{}
+	for _, column := range header[1:] {
+		columns = append(columns, strings.TrimSpace(column))
+	}
+	return columns, nil
+}
+
+// ValidateCSVColumns checks that the csv columns can be used in the templates, they cannot
+// have the same name of a job context key
+func ValidateCSVColumns(columns []string, context map[string]interface{}) error {
+	seen := map[string]bool{}
+	for _, column := range columns {
+		if !columnNameRegex.MatchString(column) {
+			return fmt.Errorf("invalid csv column: %q", column)
+		}
+		if seen[column] {
+			return fmt.Errorf("invalid csv column: %s is duplicated", column)
+		}
+		if _, ok := context[column]; ok {
+			return fmt.Errorf("invalid csv column: %s is also a context key", column)
+		}
+		seen[column] = true
+	}
+	return nil
+}
@@ -204,5 +204,83 @@ var _ = Describe("CSVSplit Worker", func() {
 			Expect(err).NotTo(HaveOccurred())
 			Expect(size).To(Equal(int64(0)))
 		})
+
+		It("should send the csv columns to the next worker", func() {
+			j := CreateTestJob(w.MarathonDB, app.ID, template.Name, map[string]interface{}{
+				"filters": map[string]interface{}{},
+				"csvPath": "test/test.csv",
+			})
+
+			data := []byte("userIds, coins ,friend_name\nsomeuser,10,Ana\n")
+			_, err := w.S3Client.PutObject("test/test.csv", &data)
+			Expect(err).NotTo(HaveOccurred())
+
+			_, err = w.CreateCSVSplitJob(j)
+			Expect(err).NotTo(HaveOccurred())
+
+			jobData, err := w.RedisClient.LPop("queue:csv_split_worker").Result()
+			message, err := goworkers2.NewMsg(string(jobData))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(func() { createCSVSplitWorker.Process(message) }).ShouldNot(Panic())
+
+			jobData, err = w.RedisClient.LPop("queue:create_batches_worker").Result()
+			Expect(err).NotTo(HaveOccurred())
+			message, err = goworkers2.NewMsg(string(jobData))
+			Expect(err).NotTo(HaveOccurred())
+
+			var msg worker.BatchPart
+			err = json.Unmarshal([]byte(message.Args().ToJson()), &msg)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(msg.Columns).To(Equal([]string{"coins", "friend_name"}))
+		})
+
+		It("should stop the job if a csv column is also a context key", func() {
+			j := CreateTestJob(w.MarathonDB, app.ID, template.Name, map[string]interface{}{
+				"filters": map[string]interface{}{},
+				"context": map[string]interface{}{"coins": 5},
+				"csvPath": "test/test.csv",
+			})
+
+			data := []byte("userIds,coins\nsomeuser,10\n")
+			_, err := w.S3Client.PutObject("test/test.csv", &data)
+			Expect(err).NotTo(HaveOccurred())
+
+			_, err = w.CreateCSVSplitJob(j)
+			Expect(err).NotTo(HaveOccurred())
+
+			jobData, err := w.RedisClient.LPop("queue:csv_split_worker").Result()
+			message, err := goworkers2.NewMsg(string(jobData))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(func() { createCSVSplitWorker.Process(message) }).ShouldNot(Panic())
+
+			size, err := w.RedisClient.LLen("queue:create_batches_worker").Result()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(size).To(Equal(int64(0)))
+
+			updatedJob := &model.Job{}
+			err = w.MarathonDB.Model(updatedJob).Column("job.*").Where("job.id = ?", j.ID).Select()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(updatedJob.Status).To(Equal("stopped"))
+		})
+	})
+
+	Describe("CSV columns", func() {
+		It("should return the header names after the user id column", func() {
+			columns, err := worker.GetCSVColumns([]byte("userIds,coins,\"friend name\"\r\nsomeuser,10,Ana"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(columns).To(Equal([]string{"coins", "friend name"}))
+
+			columns, err = worker.GetCSVColumns([]byte("userIds\nsomeuser"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(columns).To(BeEmpty())
+		})
+
+		It("should validate the header names", func() {
+			context := map[string]interface{}{"coins": 5}
+			Expect(worker.ValidateCSVColumns([]string{"friend_name", "level"}, context)).To(Succeed())
+			Expect(worker.ValidateCSVColumns([]string{"friend name"}, context)).To(MatchError(`invalid csv column: "friend name"`))
+			Expect(worker.ValidateCSVColumns([]string{"level", "level"}, context)).To(MatchError("invalid csv column: level is duplicated"))
+			Expect(worker.ValidateCSVColumns([]string{"coins"}, context)).To(MatchError("invalid csv column: coins is also a context key"))
+		})
 	})
 })
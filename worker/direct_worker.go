@@ -30,11 +30,11 @@ import (
 	goworkers2 "github.com/digitalocean/go-workers2"
 	"math"
 	"math/rand"
-	"strings"
 	"time"
 
 	uuid "github.com/satori/go.uuid"
 	"github.com/topfreegames/marathon/log"
+	"github.com/topfreegames/marathon/messages"
 	"github.com/topfreegames/marathon/model"
 	"github.com/uber-go/zap"
 )
@@ -64,16 +64,16 @@ func NewDirectWorker(workers *Worker) *DirectWorker {
 	return b
 }
 
-func (b *DirectWorker) sendToKafka(service, topic string, msg, messageMetadata map[string]interface{}, pushMetadata map[string]interface{}, deviceToken string, expiresAt int64, templateName string) error {
+func (b *DirectWorker) sendToKafka(service, topic string, msg, messageMetadata map[string]interface{}, pushMetadata map[string]interface{}, deviceToken string, expiresAt int64, templateName string, options *messages.PushOptions) error {
 	pushExpiry := expiresAt / 1000000000 // convert from nanoseconds to seconds
 	switch service {
 	case "apns":
-		err := b.Workers.Kafka.SendAPNSPush(topic, deviceToken, msg, messageMetadata, pushMetadata, pushExpiry, templateName)
+		err := b.Workers.Kafka.SendAPNSPush(topic, deviceToken, msg, messageMetadata, pushMetadata, pushExpiry, templateName, options)
 		if err != nil {
 			return err
 		}
 	case "gcm":
-		err := b.Workers.Kafka.SendGCMPush(topic, deviceToken, msg, messageMetadata, pushMetadata, pushExpiry, templateName)
+		err := b.Workers.Kafka.SendGCMPush(topic, deviceToken, msg, messageMetadata, pushMetadata, pushExpiry, templateName, options)
 		if err != nil {
 			return err
 		}
@@ -103,14 +103,18 @@ func (b *DirectWorker) checkComplete(job *model.Job) (bool, error) {
 	return job.CompletedBatches == job.TotalBatches, err
 }
 
-func (b *DirectWorker) getQuery(job *model.Job) string {
-	filters := job.Filters
-	whereClause := GetWhereClauseFromFilters(filters)
-	query := fmt.Sprintf("SELECT user_id, token, locale, tz FROM %s WHERE seq_id >= ? AND seq_id < ?", GetPushDBTableName(job.App.Name, job.Service))
-	if (whereClause) != "" {
+func (b *DirectWorker) getQuery(job *model.Job, msg *DirectPartMsg, extraColumns []string) (string, []interface{}, error) {
+	whereClause, filterParams, err := GetWhereClauseFromFilters(job.Filters)
+	if err != nil {
+		return "", nil, err
+	}
+	query := fmt.Sprintf("SELECT %s FROM %s WHERE seq_id >= ? AND seq_id < ?", GetUsersSelect(extraColumns), GetPushDBTableName(job.App.Name, job.Service))
+	params := []interface{}{msg.SmallestSeqID, msg.BiggestSeqID}
+	if whereClause != "" {
 		query = fmt.Sprintf("%s AND %s", query, whereClause)
+		params = append(params, filterParams...)
 	}
-	return query
+	return query, params, nil
 }
 
 // Process processes the messages sent to batch worker queue and send them to kafka
@@ -151,6 +155,19 @@ func (b *DirectWorker) Process(message *goworkers2.Msg) error {
 		log.D(l, "valid")
 	}
 
+	queue, _ := message.Get("queue").String()
+	slot, acquired, err := b.Workers.AcquireAppSlot("direct", queue, job.App.Name)
+	b.checkErr(job, err)
+	if !acquired {
+		log.I(l, "app is sending too many batches, batch re-enqueued")
+		at := time.Now().Add(b.Workers.Config.GetDuration("workers.fairness.retryDelay")).UnixNano()
+		_, err = b.Workers.ScheduleDirectPartJob(job, &msg, at)
+		b.checkErr(job, err)
+		b.Workers.Statsd.Incr(DirectWorkerCompleted, job.Labels(), 1)
+		return nil
+	}
+	defer slot.Release()
+
 	templatesByNameAndLocale, err := job.GetJobTemplatesByNameAndLocale(b.Workers.MarathonDB)
 	b.checkErr(job, err)
 
@@ -160,8 +177,10 @@ func (b *DirectWorker) Process(message *goworkers2.Msg) error {
 	var users []User
 	start := time.Now()
 
-	q := b.getQuery(job)
-	r, err := b.Workers.PushDB.Query(&users, q, msg.SmallestSeqID, msg.BiggestSeqID)
+	extraColumns := TemplateUserColumns(flattenTemplates(templatesByNameAndLocale)...)
+	q, params, err := b.getQuery(job, &msg, extraColumns)
+	b.checkErr(job, err)
+	r, err := b.Workers.PushDB.Query(&users, q, params...)
 
 	if err != nil {
 		l.Error("Error fetching users", zap.Error(err))
@@ -183,6 +202,21 @@ func (b *DirectWorker) Process(message *goworkers2.Msg) error {
 			zap.Uint64("bigSeqId", msg.BiggestSeqID))
 	})
 
+	// remove the users of the app holdout group, they never receive the jobs that use it
+	if job.UseHoldout && job.App.HoldoutPercentage > 0 {
+		remaining := make([]User, 0, len(users))
+		for _, user := range users {
+			if !job.App.InHoldout(user.UserID) {
+				remaining = append(remaining, user)
+			}
+		}
+		holdoutUsers := len(users) - len(remaining)
+		err = IncrHoldoutUsers(b.Workers.MarathonDB, job, holdoutUsers)
+		b.checkErr(job, err)
+		successfulUsers -= holdoutUsers
+		users = remaining
+	}
+
 	// create a controll group if needed
 	controlGroupSize := int(math.Ceil(float64(len(users)) * job.ControlGroup))
 	if controlGroupSize > 0 {
@@ -205,33 +239,49 @@ func (b *DirectWorker) Process(message *goworkers2.Msg) error {
 		users = append(users[:len(users)-controlGroupSize], users[len(users):]...)
 	}
 
-	for _, user := range users {
-		templateName := job.TemplateName
-		templateNames := strings.Split(job.TemplateName, ",")
+	localeStats := map[string]int{}
+	idsByVariant := map[string][]string{}
+	treatedIDs := []string{}
+	cappedUsers := 0
+	variantFeedbacks := map[string]map[string]int{}
+	quietHours := job.GetQuietHours()
+	throttle := NewThrottle(b.Workers.RedisClient, job)
+	deferred := map[int64][]User{}
+	var throttled []User
+	var throttledAt int64
+	now := time.Now()
+	for i, user := range users {
+		if at, ok := QuietHoursEnd(quietHours, &user, now); ok {
+			if job.ExpiresAt == 0 || at < job.ExpiresAt {
+				deferred[at] = append(deferred[at], user)
+			} else {
+				log.D(l, "user quiet hours end after the job expires", func(cm log.CM) {
+					cm.Write(zap.String("userId", user.UserID))
+				})
+			}
+			successfulUsers--
+			continue
+		}
 
-		if templateNames != nil && len(templateNames) > 1 {
-			templateName = RandomElementFromSlice(templateNames)
+		templateName := SelectVariant(job, user.UserID)
+		if job.HasVariants() {
 			log.D(l, "selected template", func(cm log.CM) {
 				cm.Write(zap.Object("name", templateName))
 			})
 		}
 
-		templatesByLocale := templatesByNameAndLocale[templateName]
-		var template model.Template
-		if val, ok := templatesByLocale[strings.ToLower(user.Locale)]; ok {
-			template = val
-		} else if val, ok := templatesByLocale["en"]; ok {
-			template = val
-		} else {
-			b.checkErr(job, fmt.Errorf("there is no template for the given locale or 'en'"))
+		template, level, ok := SelectTemplate(templatesByNameAndLocale[templateName], job.App.LocaleChain(user.Locale))
+		if !ok {
+			log.D(l, "no template for the user locale", func(cm log.CM) {
+				cm.Write(zap.String("locale", user.Locale))
+			})
+			localeStats[LocaleStatsMissing]++
+			successfulUsers--
+			continue
 		}
+		localeStats[LocaleStatsFallback(level)]++
 
-		msgStr, msgErr := BuildMessageFromTemplate(template, job.Context)
-		b.checkErr(job, msgErr)
-
-		var msg map[string]interface{}
-		err = json.Unmarshal([]byte(msgStr), &msg)
-
+		msg, err := RenderTemplate(template, job.Context, &user)
 		b.checkErr(job, err)
 		pushMetadata := map[string]interface{}{
 			"userId":       user.UserID,
@@ -242,6 +292,38 @@ func (b *DirectWorker) Process(message *goworkers2.Msg) error {
 			"muid":         uuid.NewV4().String(),
 		}
 
+		caps := job.App.FrequencyCapsFor(template.Category)
+		allowed, err := ReserveFrequencyCaps(b.Workers.RedisClient, &job.App, caps, user.UserID, pushMetadata["muid"].(string))
+		b.checkErr(job, err)
+		if !allowed {
+			log.D(l, "user reached a frequency cap", func(cm log.CM) {
+				cm.Write(zap.String("userId", user.UserID))
+			})
+			cappedUsers++
+			successfulUsers--
+			continue
+		}
+		sendNow, wait, err := throttle.Take(len(users) - i)
+		if err != nil || !sendNow {
+			ReleaseFrequencyCaps(b.Workers.RedisClient, &job.App, caps, user.UserID, pushMetadata["muid"].(string))
+		}
+		b.checkErr(job, err)
+		if !sendNow {
+			// the rest of the batch is sent when the bucket refills instead of holding the worker
+			localeStats[LocaleStatsFallback(level)]--
+			throttled = users[i:]
+			throttledAt = time.Now().Add(wait).UnixNano()
+			successfulUsers -= len(throttled)
+			break
+		}
+		if job.HasVariants() {
+			pushMetadata["variant"] = templateName
+			idsByVariant[templateName] = append(idsByVariant[templateName], user.UserID)
+		}
+		if job.ControlGroup > 0 {
+			treatedIDs = append(treatedIDs, user.UserID)
+		}
+
 		dryRun := false
 		if val, ok := job.Metadata["dryRun"]; ok {
 			if dryRun, ok = val.(bool); ok {
@@ -249,15 +331,38 @@ func (b *DirectWorker) Process(message *goworkers2.Msg) error {
 			}
 		}
 
-		err = b.sendToKafka(job.Service, topic, msg, job.Metadata, pushMetadata, user.Token, job.ExpiresAt, templateName)
+		err = b.sendToKafka(job.Service, topic, msg, job.Metadata, pushMetadata, user.Token, job.ExpiresAt, templateName, job.GetPushOptions(&template))
 		if err != nil {
 			log.E(l, "error sending message to kafa", func(cm log.CM) {
 				cm.Write(zap.Error(err))
 			})
+			ReleaseFrequencyCaps(b.Workers.RedisClient, &job.App, caps, user.UserID, pushMetadata["muid"].(string))
 			successfulUsers--
+		} else if job.HasVariants() {
+			if variantFeedbacks[templateName] == nil {
+				variantFeedbacks[templateName] = map[string]int{}
+			}
+			variantFeedbacks[templateName][model.VariantSent]++
 		}
 	}
 
+	err = IncrLocaleStats(b.Workers.MarathonDB, job.ID, localeStats)
+	b.checkErr(job, err)
+	if len(idsByVariant) > 0 {
+		b.Workers.SendVariantsToRedis(job, idsByVariant)
+	}
+	if len(treatedIDs) > 0 {
+		b.Workers.SendTreatedGroupToRedis(job, treatedIDs)
+	}
+	err = IncrVariantFeedbacks(b.Workers.MarathonDB, job, variantFeedbacks)
+	b.checkErr(job, err)
+	err = IncrCappedUsers(b.Workers.MarathonDB, job, cappedUsers)
+	b.checkErr(job, err)
+	err = ScheduleDeferredUsers(b.Workers, job, job.App.Name, deferred)
+	b.checkErr(job, err)
+	err = ScheduleThrottledUsers(b.Workers, job, job.App.Name, throttled, throttledAt)
+	b.checkErr(job, err)
+
 	// ignore errors
 	b.addCompletedTokens(job, successfulUsers)
 	b.addCompletedBatch(job)
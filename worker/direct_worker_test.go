@@ -21,12 +21,15 @@ package worker_test
 
 import (
 	"bytes"
+	"encoding/json"
 	"fmt"
 	goworkers2 "github.com/digitalocean/go-workers2"
 	"math/rand"
+	"time"
 
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
+	"github.com/topfreegames/marathon/messages"
 	"github.com/topfreegames/marathon/model"
 	. "github.com/topfreegames/marathon/testing"
 	"github.com/topfreegames/marathon/worker"
@@ -191,5 +194,97 @@ var _ = Describe("Complete Test", func() {
 			Expect(err).NotTo(HaveOccurred())
 			Expect(dbJob.ControlGroupCSVPath).To(Equal(key))
 		})
+
+		It("should not send the job to the users in the app holdout group", func() {
+			_, err := w.MarathonDB.Model(app).Set("holdout_percentage = 0.5, holdout_salt = 'salt'").Update()
+			Expect(err).NotTo(HaveOccurred())
+			app.HoldoutPercentage = 0.5
+			app.HoldoutSalt = "salt"
+
+			_, err = w.PushDB.Query(nil, `
+				INSERT INTO myapp_apns (seq_id, user_id, token, locale, region, tz)
+				SELECT
+					generate_series(1, 100) AS seq_id,
+					generate_series(1, 100)::text AS user_id,
+					generate_series(1, 100)::text AS token,
+					'en' as locale,
+					'us' as region,
+					'+0000' as tz;
+			`)
+			Expect(err).NotTo(HaveOccurred())
+
+			held := 0
+			for i := 1; i <= 100; i++ {
+				if app.InHoldout(fmt.Sprintf("%d", i)) {
+					held++
+				}
+			}
+			Expect(held).To(BeNumerically(">", 0))
+
+			j := CreateTestJob(w.MarathonDB, app.ID, template.Name, map[string]interface{}{
+				"filters": map[string]interface{}{
+					"locale": "en",
+				},
+			})
+			j.UseHoldout = true
+			_, err = w.MarathonDB.Model(j).Column("use_holdout").Update()
+			Expect(err).NotTo(HaveOccurred())
+
+			runAllSteps(j)
+
+			Expect(producer.APNSMessages).To(HaveLen(100 - held))
+			for _, m := range producer.APNSMessages {
+				var apnsMessage messages.APNSMessage
+				err = json.Unmarshal([]byte(m), &apnsMessage)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(app.InHoldout(apnsMessage.Metadata["userId"].(string))).To(BeFalse())
+			}
+
+			dbJob := &model.Job{}
+			err = w.MarathonDB.Model(dbJob).Where("id = ?", j.ID).Select()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(dbJob.HoldoutUsers).To(Equal(held))
+		})
+
+		It("should not send the job to the users that reached a frequency cap", func() {
+			app.FrequencyCaps = []model.FrequencyCap{{MaxPushes: 1, Window: int64(time.Hour)}}
+			_, err := w.MarathonDB.Model(app).Column("frequency_caps").Update()
+			Expect(err).NotTo(HaveOccurred())
+
+			_, err = w.PushDB.Query(nil, `
+				INSERT INTO myapp_apns (seq_id, user_id, token, locale, region, tz)
+				SELECT
+					generate_series(1, 10) AS seq_id,
+					generate_series(1, 10)::text AS user_id,
+					generate_series(1, 10)::text AS token,
+					'en' as locale,
+					'us' as region,
+					'+0000' as tz;
+			`)
+			Expect(err).NotTo(HaveOccurred())
+
+			first := CreateTestJob(w.MarathonDB, app.ID, template.Name, map[string]interface{}{
+				"filters": map[string]interface{}{
+					"locale": "en",
+				},
+			})
+			runAllSteps(first)
+			Expect(producer.APNSMessages).To(HaveLen(10))
+
+			// the caps are kept, only the processed jobs are removed
+			w.RedisClient.Del("queue:direct_worker", "schedule")
+			second := CreateTestJob(w.MarathonDB, app.ID, template.Name, map[string]interface{}{
+				"filters": map[string]interface{}{
+					"locale": "en",
+				},
+			})
+			runAllSteps(second)
+			Expect(producer.APNSMessages).To(HaveLen(10))
+
+			dbJob := &model.Job{}
+			err = w.MarathonDB.Model(dbJob).Where("id = ?", second.ID).Select()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(dbJob.CappedUsers).To(Equal(10))
+		})
 	})
 })
@@ -0,0 +1,97 @@
+/*
+ * Copyright (c) 2026 TFG Co <backend@tfgco.com>
+ * Author: TFG Co <backend@tfgco.com>
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy of
+ * this software and associated documentation files (the "Software"), to deal in
+ * the Software without restriction, including without limitation the rights to
+ * use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+ * the Software, and to permit persons to whom the Software is furnished to do so,
+ * subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+ * FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+ * COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+ * IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+ * CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+ */
+
+package worker
+
+import (
+	"fmt"
+	"time"
+
+	uuid "github.com/satori/go.uuid"
+	redis "gopkg.in/redis.v5"
+)
+
+// appSlotScript takes a slot of the app if it holds less than ARGV[3] slots, the slots are
+// members of a sorted set scored by the time in milliseconds they were taken and the ones older
+// than ARGV[2] milliseconds are freed, so a crashed worker does not keep its slot forever
+var appSlotScript = redis.NewScript(`
+local now = tonumber(ARGV[1])
+local timeout = tonumber(ARGV[2])
+redis.call("ZREMRANGEBYSCORE", KEYS[1], "-inf", now - timeout)
+if redis.call("ZCARD", KEYS[1]) >= tonumber(ARGV[3]) then
+  return 0
+end
+redis.call("ZADD", KEYS[1], now, ARGV[4])
+redis.call("PEXPIRE", KEYS[1], timeout)
+return 1
+`)
+
+// GetAppSlotsRedisKey returns the slots taken by the app in the queue
+func GetAppSlotsRedisKey(queue, appName string) string {
+	return fmt.Sprintf("%s-%s-SLOTS", queue, appName)
+}
+
+// AppSlot is a batch of an app being sent by a queue
+type AppSlot struct {
+	client *redis.Client
+	key    string
+	member string
+}
+
+// AcquireAppSlot takes one of the slots of the app in the queue, an app sends at most limit
+// batches at the same time in a queue so it cannot starve the other apps. It returns false if
+// all the slots are taken, a limit of zero never blocks and returns a nil slot
+func AcquireAppSlot(client *redis.Client, queue, appName string, limit int, timeout time.Duration) (*AppSlot, bool, error) {
+	if limit <= 0 {
+		return nil, true, nil
+	}
+	slot := &AppSlot{
+		client: client,
+		key:    GetAppSlotsRedisKey(queue, appName),
+		member: uuid.NewV4().String(),
+	}
+	now := time.Now().UnixNano() / int64(time.Millisecond)
+	res, err := appSlotScript.Run(client, []string{slot.key}, now, int64(timeout/time.Millisecond), limit, slot.member).Result()
+	if err != nil {
+		return nil, false, err
+	}
+	if res.(int64) == 0 {
+		return nil, false, nil
+	}
+	return slot, true, nil
+}
+
+// Release frees the slot
+func (s *AppSlot) Release() error {
+	if s == nil {
+		return nil
+	}
+	return s.client.ZRem(s.key, s.member).Err()
+}
+
+// AcquireAppSlot takes a slot of the app in the queue with the limit configured for the worker
+// in workers.<worker>.maxAppConcurrency
+func (w *Worker) AcquireAppSlot(worker, queue, appName string) (*AppSlot, bool, error) {
+	limit := w.Config.GetInt(fmt.Sprintf("workers.%s.maxAppConcurrency", worker))
+	timeout := w.Config.GetDuration("workers.fairness.slotTimeout")
+	return AcquireAppSlot(w.RedisClient, queue, appName, limit, timeout)
+}
@@ -0,0 +1,90 @@
+/*
+ * Copyright (c) 2026 TFG Co <backend@tfgco.com>
+ * Author: TFG Co <backend@tfgco.com>
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy of
+ * this software and associated documentation files (the "Software"), to deal in
+ * the Software without restriction, including without limitation the rights to
+ * use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+ * the Software, and to permit persons to whom the Software is furnished to do so,
+ * subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+ * FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+ * COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+ * IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+ * CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+ */
+
+package worker_test
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/topfreegames/marathon/testing"
+	"github.com/topfreegames/marathon/worker"
+	"github.com/uber-go/zap"
+)
+
+var _ = Describe("Fairness", func() {
+	logger := zap.New(
+		zap.NewJSONEncoder(zap.NoTime()),
+		zap.FatalLevel,
+	)
+	w := worker.NewWorker(logger, GetConfPath())
+
+	BeforeEach(func() {
+		w.RedisClient.FlushAll()
+	})
+
+	Describe("App slots", func() {
+		It("should not limit the app if the limit is zero", func() {
+			slot, acquired, err := worker.AcquireAppSlot(w.RedisClient, "process_batch_worker", "testapp", 0, time.Minute)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(acquired).To(BeTrue())
+			Expect(slot.Release()).To(Succeed())
+		})
+
+		It("should not let an app take more than limit slots in a queue", func() {
+			first, acquired, err := worker.AcquireAppSlot(w.RedisClient, "process_batch_worker", "testapp", 2, time.Minute)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(acquired).To(BeTrue())
+			_, acquired, err = worker.AcquireAppSlot(w.RedisClient, "process_batch_worker", "testapp", 2, time.Minute)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(acquired).To(BeTrue())
+
+			_, acquired, err = worker.AcquireAppSlot(w.RedisClient, "process_batch_worker", "testapp", 2, time.Minute)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(acquired).To(BeFalse())
+
+			_, acquired, err = worker.AcquireAppSlot(w.RedisClient, "process_batch_worker", "otherapp", 2, time.Minute)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(acquired).To(BeTrue())
+			_, acquired, err = worker.AcquireAppSlot(w.RedisClient, "process_batch_worker_high", "testapp", 2, time.Minute)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(acquired).To(BeTrue())
+
+			Expect(first.Release()).To(Succeed())
+			_, acquired, err = worker.AcquireAppSlot(w.RedisClient, "process_batch_worker", "testapp", 2, time.Minute)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(acquired).To(BeTrue())
+		})
+
+		It("should free the slots older than the timeout", func() {
+			_, acquired, err := worker.AcquireAppSlot(w.RedisClient, "direct_worker", "testapp", 1, 50*time.Millisecond)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(acquired).To(BeTrue())
+
+			time.Sleep(100 * time.Millisecond)
+			_, acquired, err = worker.AcquireAppSlot(w.RedisClient, "direct_worker", "testapp", 1, 50*time.Millisecond)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(acquired).To(BeTrue())
+		})
+	})
+})
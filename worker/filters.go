@@ -0,0 +1,479 @@
+/*
+ * Copyright (c) 2026 TFG Co <backend@tfgco.com>
+ * Author: TFG Co <backend@tfgco.com>
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy of
+ * this software and associated documentation files (the "Software"), to deal in
+ * the Software without restriction, including without limitation the rights to
+ * use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+ * the Software, and to permit persons to whom the Software is furnished to do so,
+ * subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+ * FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+ * COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+ * IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+ * CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+ */
+
+package worker
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/topfreegames/marathon/interfaces"
+	pg "gopkg.in/pg.v5"
+)
+
+// Filter operators
+const (
+	FilterEq        = "eq"
+	FilterNeq       = "neq"
+	FilterIn        = "in"
+	FilterNotIn     = "not_in"
+	FilterGt        = "gt"
+	FilterGte       = "gte"
+	FilterLt        = "lt"
+	FilterLte       = "lte"
+	FilterBetween   = "between"
+	FilterIsNull    = "is_null"
+	FilterIsNotNull = "is_not_null"
+	FilterPrefix    = "prefix"
+	FilterBefore    = "before"
+	FilterAfter     = "after"
+)
+
+var filterComparisons = map[string]string{
+	FilterEq:  "=",
+	FilterNeq: "!=",
+	FilterGt:  ">",
+	FilterGte: ">=",
+	FilterLt:  "<",
+	FilterLte: "<=",
+}
+
+var columnNameRegex = regexp.MustCompile("^[a-zA-Z_][a-zA-Z0-9_]*$")
+
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// Filter is a node of a job filter expression, it is either a group (and, or, not)
+// or a condition over a push db column
+type Filter struct {
+	And    []*Filter   `json:"and,omitempty"`
+	Or     []*Filter   `json:"or,omitempty"`
+	Not    *Filter     `json:"not,omitempty"`
+	Column string      `json:"column,omitempty"`
+	Op     string      `json:"op,omitempty"`
+	Value  interface{} `json:"value,omitempty"`
+}
+
+// IsFilterExpression returns true if the job filters use the filter expression format
+// instead of the legacy column map format
+func IsFilterExpression(filters map[string]interface{}) bool {
+	for _, key := range []string{"and", "or", "not", "column"} {
+		if _, ok := filters[key]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// ParseFilters parses the job filters, both the filter expression and the legacy format
+// ({"column": "a,b", "NOTcolumn": "c"}) are accepted, nil is returned if there are no filters
+func ParseFilters(filters map[string]interface{}) (*Filter, error) {
+	if len(filters) == 0 {
+		return nil, nil
+	}
+
+	if IsFilterExpression(filters) {
+		b, err := json.Marshal(filters)
+		if err != nil {
+			return nil, err
+		}
+		decoder := json.NewDecoder(strings.NewReader(string(b)))
+		decoder.DisallowUnknownFields()
+		filter := &Filter{}
+		if err := decoder.Decode(filter); err != nil {
+			return nil, fmt.Errorf("invalid filters: %s", err.Error())
+		}
+		return filter, nil
+	}
+
+	keys := make([]string, 0, len(filters))
+	for key := range filters {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		ci, cj := strings.TrimPrefix(keys[i], "NOT"), strings.TrimPrefix(keys[j], "NOT")
+		if ci == cj {
+			return keys[i] < keys[j]
+		}
+		return ci < cj
+	})
+
+	filter := &Filter{}
+	for _, key := range keys {
+		val, ok := filters[key].(string)
+		if !ok {
+			return nil, fmt.Errorf("invalid filters: %s must be a string", key)
+		}
+		op := FilterIn
+		column := key
+		if strings.HasPrefix(key, "NOT") {
+			op = FilterNotIn
+			column = strings.TrimPrefix(key, "NOT")
+		}
+		values := []interface{}{}
+		for _, v := range strings.Split(val, ",") {
+			values = append(values, v)
+		}
+		filter.And = append(filter.And, &Filter{Column: column, Op: op, Value: values})
+	}
+	return filter, nil
+}
+
+// WithColumnFilter returns a copy of the job filters restricted to the users whose
+// column has one of the values, an empty value also matches the users whose column is null
+// as they are scanned as empty strings
+func WithColumnFilter(filters map[string]interface{}, column string, values []string) map[string]interface{} {
+	withNull := false
+	for _, v := range values {
+		if v == "" {
+			withNull = true
+		}
+	}
+	if !IsFilterExpression(filters) && !withNull {
+		res := map[string]interface{}{}
+		for k, v := range filters {
+			res[k] = v
+		}
+		res[column] = strings.Join(values, ",")
+		return res
+	}
+
+	in := []interface{}{}
+	for _, v := range values {
+		in = append(in, v)
+	}
+	condition := map[string]interface{}{"column": column, "op": FilterIn, "value": in}
+	if withNull {
+		condition = map[string]interface{}{
+			"or": []interface{}{
+				condition,
+				map[string]interface{}{"column": column, "op": FilterIsNull},
+			},
+		}
+	}
+	if len(filters) == 0 {
+		return condition
+	}
+	if !IsFilterExpression(filters) {
+		// the legacy filters were validated with the job, so they are always parsed
+		if filter, err := ParseFilters(filters); err == nil {
+			filters, _ = filter.ToMap()
+		}
+	}
+	return map[string]interface{}{
+		"and": []interface{}{filters, condition},
+	}
+}
+
+// MarshalJSON keeps only the keys used by the filter node, zero values included
+func (f *Filter) MarshalJSON() ([]byte, error) {
+	switch {
+	case f.And != nil:
+		return json.Marshal(map[string]interface{}{"and": f.And})
+	case f.Or != nil:
+		return json.Marshal(map[string]interface{}{"or": f.Or})
+	case f.Not != nil:
+		return json.Marshal(map[string]interface{}{"not": f.Not})
+	}
+	res := map[string]interface{}{"column": f.Column, "op": f.Op}
+	if f.Value != nil {
+		res["value"] = f.Value
+	}
+	return json.Marshal(res)
+}
+
+// ToMap returns the filter expression in the format it is stored in the job filters
+func (f *Filter) ToMap() (map[string]interface{}, error) {
+	b, err := json.Marshal(f)
+	if err != nil {
+		return nil, err
+	}
+	res := map[string]interface{}{}
+	err = json.Unmarshal(b, &res)
+	return res, err
+}
+
+// MapValues replaces the string values of the conditions over column by fn(value)
+func (f *Filter) MapValues(column string, fn func(string) string) {
+	f.walk(func(leaf *Filter) {
+		if leaf.Column != column {
+			return
+		}
+		switch value := leaf.Value.(type) {
+		case string:
+			leaf.Value = fn(value)
+		case []interface{}:
+			for i, v := range value {
+				if str, ok := v.(string); ok {
+					value[i] = fn(str)
+				}
+			}
+		}
+	})
+}
+
+// Columns returns the push db columns used by the filter
+func (f *Filter) Columns() []string {
+	columns := []string{}
+	f.walk(func(leaf *Filter) {
+		columns = append(columns, leaf.Column)
+	})
+	return columns
+}
+
+func (f *Filter) walk(fn func(leaf *Filter)) {
+	switch {
+	case f.And != nil:
+		for _, child := range f.And {
+			child.walk(fn)
+		}
+	case f.Or != nil:
+		for _, child := range f.Or {
+			child.walk(fn)
+		}
+	case f.Not != nil:
+		f.Not.walk(fn)
+	default:
+		fn(f)
+	}
+}
+
+// Validate checks the filter structure, operators and values, columns maps the push
+// db table column names to their data types and if not nil every column must exist in it
+func (f *Filter) Validate(columns map[string]string) error {
+	groups := 0
+	if f.And != nil {
+		groups++
+	}
+	if f.Or != nil {
+		groups++
+	}
+	if f.Not != nil {
+		groups++
+	}
+	isLeaf := f.Column != "" || f.Op != "" || f.Value != nil
+	if groups > 1 || (groups == 1 && isLeaf) {
+		return fmt.Errorf("invalid filters: a filter must have only one of and, or, not or column")
+	}
+
+	switch {
+	case f.And != nil || f.Or != nil:
+		children := f.And
+		if f.Or != nil {
+			children = f.Or
+		}
+		if len(children) == 0 {
+			return fmt.Errorf("invalid filters: and/or groups cannot be empty")
+		}
+		for _, child := range children {
+			if child == nil {
+				return fmt.Errorf("invalid filters: and/or groups cannot have null filters")
+			}
+			if err := child.Validate(columns); err != nil {
+				return err
+			}
+		}
+		return nil
+	case f.Not != nil:
+		return f.Not.Validate(columns)
+	}
+
+	if !columnNameRegex.MatchString(f.Column) {
+		return fmt.Errorf("invalid filters: invalid column %q", f.Column)
+	}
+	if columns != nil {
+		dataType, ok := columns[f.Column]
+		if !ok {
+			return fmt.Errorf("invalid filters: column %s does not exist", f.Column)
+		}
+		if err := f.checkColumnType(dataType); err != nil {
+			return err
+		}
+	}
+	_, _, err := f.condition()
+	return err
+}
+
+// checkColumnType checks that the operator can be used with the column data type, the
+// push db would only fail when the batches are sent
+func (f *Filter) checkColumnType(dataType string) error {
+	switch f.Op {
+	case FilterBefore, FilterAfter:
+		if !isDateType(dataType) {
+			return fmt.Errorf("invalid filters: %s %s needs a date column, %s is %s", f.Column, f.Op, f.Column, dataType)
+		}
+	case FilterBetween:
+		if isTextType(dataType) {
+			return fmt.Errorf("invalid filters: %s between cannot be used with text columns", f.Column)
+		}
+	case FilterPrefix:
+		if !isTextType(dataType) {
+			return fmt.Errorf("invalid filters: %s prefix needs a text column, %s is %s", f.Column, f.Column, dataType)
+		}
+	}
+	return nil
+}
+
+func isTextType(dataType string) bool {
+	return dataType == "text" || strings.HasPrefix(dataType, "character")
+}
+
+func isDateType(dataType string) bool {
+	return dataType == "date" || strings.HasPrefix(dataType, "timestamp") || strings.HasPrefix(dataType, "time ")
+}
+
+// Compile returns the SQL condition of the filter with ? placeholders and its parameters
+func (f *Filter) Compile() (string, []interface{}, error) {
+	switch {
+	case f.And != nil || f.Or != nil:
+		children := f.And
+		connector := " AND "
+		if f.Or != nil {
+			children = f.Or
+			connector = " OR "
+		}
+		if len(children) == 0 {
+			return "", nil, fmt.Errorf("invalid filters: and/or groups cannot be empty")
+		}
+		conditions := []string{}
+		params := []interface{}{}
+		for _, child := range children {
+			if child == nil {
+				return "", nil, fmt.Errorf("invalid filters: and/or groups cannot have null filters")
+			}
+			condition, childParams, err := child.Compile()
+			if err != nil {
+				return "", nil, err
+			}
+			conditions = append(conditions, condition)
+			params = append(params, childParams...)
+		}
+		return fmt.Sprintf("(%s)", strings.Join(conditions, connector)), params, nil
+	case f.Not != nil:
+		condition, params, err := f.Not.Compile()
+		if err != nil {
+			return "", nil, err
+		}
+		return fmt.Sprintf("NOT %s", condition), params, nil
+	}
+
+	if !columnNameRegex.MatchString(f.Column) {
+		return "", nil, fmt.Errorf("invalid filters: invalid column %q", f.Column)
+	}
+	return f.condition()
+}
+
+func (f *Filter) condition() (string, []interface{}, error) {
+	column := fmt.Sprintf("\"%s\"", f.Column)
+
+	if comparison, ok := filterComparisons[f.Op]; ok {
+		if !isScalar(f.Value) {
+			return "", nil, fmt.Errorf("invalid filters: %s %s needs a single value", f.Column, f.Op)
+		}
+		return fmt.Sprintf("%s %s ?", column, comparison), []interface{}{f.Value}, nil
+	}
+
+	switch f.Op {
+	case FilterIn, FilterNotIn:
+		values, ok := f.Value.([]interface{})
+		if !ok || len(values) == 0 {
+			return "", nil, fmt.Errorf("invalid filters: %s %s needs a non empty list of values", f.Column, f.Op)
+		}
+		for _, v := range values {
+			if !isScalar(v) {
+				return "", nil, fmt.Errorf("invalid filters: %s %s needs a list of single values", f.Column, f.Op)
+			}
+		}
+		if f.Op == FilterNotIn {
+			return fmt.Sprintf("%s NOT IN (?)", column), []interface{}{pg.In(values)}, nil
+		}
+		return fmt.Sprintf("%s IN (?)", column), []interface{}{pg.In(values)}, nil
+	case FilterBetween:
+		values, ok := f.Value.([]interface{})
+		if !ok || len(values) != 2 || !isScalar(values[0]) || !isScalar(values[1]) {
+			return "", nil, fmt.Errorf("invalid filters: %s between needs a list with two values", f.Column)
+		}
+		return fmt.Sprintf("%s BETWEEN ? AND ?", column), values, nil
+	case FilterIsNull:
+		return fmt.Sprintf("%s IS NULL", column), []interface{}{}, nil
+	case FilterIsNotNull:
+		return fmt.Sprintf("%s IS NOT NULL", column), []interface{}{}, nil
+	case FilterPrefix:
+		prefix, ok := f.Value.(string)
+		if !ok || prefix == "" {
+			return "", nil, fmt.Errorf("invalid filters: %s prefix needs a non empty string", f.Column)
+		}
+		return fmt.Sprintf("%s LIKE ?", column), []interface{}{likeEscaper.Replace(prefix) + "%"}, nil
+	case FilterBefore, FilterAfter:
+		value, ok := f.Value.(string)
+		if !ok {
+			return "", nil, fmt.Errorf("invalid filters: %s %s needs a RFC3339 date", f.Column, f.Op)
+		}
+		date, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			return "", nil, fmt.Errorf("invalid filters: %s %s needs a RFC3339 date", f.Column, f.Op)
+		}
+		if f.Op == FilterBefore {
+			return fmt.Sprintf("%s < ?", column), []interface{}{date}, nil
+		}
+		return fmt.Sprintf("%s > ?", column), []interface{}{date}, nil
+	}
+	return "", nil, fmt.Errorf("invalid filters: unknown operator %q", f.Op)
+}
+
+func isScalar(value interface{}) bool {
+	switch value.(type) {
+	case string, float64, bool, int, int64:
+		return true
+	}
+	return false
+}
+
+// GetWhereClauseFromFilters returns the SQL condition of the job filters with ? placeholders
+// and its parameters, an empty condition is returned if there are no filters
+func GetWhereClauseFromFilters(filters map[string]interface{}) (string, []interface{}, error) {
+	filter, err := ParseFilters(filters)
+	if err != nil || filter == nil {
+		return "", nil, err
+	}
+	return filter.Compile()
+}
+
+// GetPushDBColumns returns the columns of the push db table mapped to their data types
+func GetPushDBColumns(db interfaces.DB, tableName string) (map[string]string, error) {
+	var columns []struct {
+		ColumnName string
+		DataType   string
+	}
+	_, err := db.Query(&columns, "SELECT column_name, data_type FROM information_schema.columns WHERE table_name = ?", tableName)
+	if err != nil {
+		return nil, err
+	}
+	res := map[string]string{}
+	for _, column := range columns {
+		res[column.ColumnName] = column.DataType
+	}
+	return res, nil
+}
@@ -0,0 +1,300 @@
+/*
+ * Copyright (c) 2026 TFG Co <backend@tfgco.com>
+ * Author: TFG Co <backend@tfgco.com>
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy of
+ * this software and associated documentation files (the "Software"), to deal in
+ * the Software without restriction, including without limitation the rights to
+ * use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+ * the Software, and to permit persons to whom the Software is furnished to do so,
+ * subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+ * FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+ * COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+ * IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+ * CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+ */
+
+package worker_test
+
+import (
+	"encoding/json"
+	"strings"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/topfreegames/marathon/worker"
+	"gopkg.in/pg.v5/orm"
+)
+
+var _ = Describe("Filters", func() {
+	columns := map[string]string{
+		"user_id":    "character varying",
+		"locale":     "character varying",
+		"region":     "character varying",
+		"tz":         "character varying",
+		"created_at": "timestamp without time zone",
+		"seq_id":     "bigint",
+	}
+
+	parse := func(filters string) (*worker.Filter, error) {
+		var f map[string]interface{}
+		err := json.Unmarshal([]byte(filters), &f)
+		Expect(err).NotTo(HaveOccurred())
+		return worker.ParseFilters(f)
+	}
+
+	compile := func(filters string) (string, error) {
+		f, err := parse(filters)
+		Expect(err).NotTo(HaveOccurred())
+		if err := f.Validate(columns); err != nil {
+			return "", err
+		}
+		where, params, err := f.Compile()
+		if err != nil {
+			return "", err
+		}
+		return string(orm.Formatter{}.FormatQuery(nil, where, params...)), nil
+	}
+
+	Describe("Parse", func() {
+		It("should return nil if filters is empty", func() {
+			f, err := worker.ParseFilters(map[string]interface{}{})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(f).To(BeNil())
+		})
+
+		It("should parse legacy filters", func() {
+			f, err := parse(`{"locale": "en,fr", "NOTregion": "US"}`)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(f.And).To(HaveLen(2))
+			Expect(f.And[0].Column).To(Equal("locale"))
+			Expect(f.And[0].Op).To(Equal(worker.FilterIn))
+			Expect(f.And[0].Value).To(Equal([]interface{}{"en", "fr"}))
+			Expect(f.And[1].Column).To(Equal("region"))
+			Expect(f.And[1].Op).To(Equal(worker.FilterNotIn))
+			Expect(f.And[1].Value).To(Equal([]interface{}{"US"}))
+		})
+
+		It("should fail if legacy filter value is not a string", func() {
+			_, err := parse(`{"locale": 1}`)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(Equal("invalid filters: locale must be a string"))
+		})
+
+		It("should parse filter expressions", func() {
+			f, err := parse(`{"or": [{"column": "locale", "op": "eq", "value": "en"}, {"not": {"column": "tz", "op": "is_null"}}]}`)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(f.Or).To(HaveLen(2))
+			Expect(f.Or[0].Column).To(Equal("locale"))
+			Expect(f.Or[1].Not.Op).To(Equal(worker.FilterIsNull))
+		})
+
+		It("should fail if filter expression has unknown keys", func() {
+			_, err := parse(`{"and": [{"column": "locale", "operator": "eq", "value": "en"}]}`)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("unknown field \"operator\""))
+		})
+	})
+
+	Describe("Compile", func() {
+		It("should compile comparisons", func() {
+			where, err := compile(`{"and": [
+				{"column": "locale", "op": "eq", "value": "en"},
+				{"column": "region", "op": "neq", "value": "US"},
+				{"column": "seq_id", "op": "gte", "value": 10},
+				{"column": "seq_id", "op": "lt", "value": 20}
+			]}`)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(where).To(Equal(`("locale" = 'en' AND "region" != 'US' AND "seq_id" >= 10 AND "seq_id" < 20)`))
+		})
+
+		It("should compile nested groups", func() {
+			where, err := compile(`{"or": [
+				{"column": "locale", "op": "in", "value": ["en", "fr"]},
+				{"not": {"and": [
+					{"column": "region", "op": "not_in", "value": ["US"]},
+					{"column": "tz", "op": "is_not_null"}
+				]}}
+			]}`)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(where).To(Equal(`("locale" IN ('en','fr') OR NOT ("region" NOT IN ('US') AND "tz" IS NOT NULL))`))
+		})
+
+		It("should compile ranges and null checks", func() {
+			where, err := compile(`{"and": [
+				{"column": "seq_id", "op": "between", "value": [1, 100]},
+				{"column": "tz", "op": "is_null"}
+			]}`)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(where).To(Equal(`("seq_id" BETWEEN 1 AND 100 AND "tz" IS NULL)`))
+		})
+
+		It("should compile prefix matches escaping like wildcards", func() {
+			where, err := compile(`{"column": "user_id", "op": "prefix", "value": "abc_1%"}`)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(where).To(Equal(`"user_id" LIKE 'abc\_1\%%'`))
+		})
+
+		It("should compile date comparisons", func() {
+			where, err := compile(`{"and": [
+				{"column": "created_at", "op": "after", "value": "2016-01-01T00:00:00Z"},
+				{"column": "created_at", "op": "before", "value": "2017-01-01T00:00:00Z"}
+			]}`)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(where).To(ContainSubstring(`"created_at" > '2016-01-01 00:00:00`))
+			Expect(where).To(ContainSubstring(`"created_at" < '2017-01-01 00:00:00`))
+		})
+
+		It("should escape values", func() {
+			where, err := compile(`{"column": "locale", "op": "eq", "value": "en' OR '1'='1"}`)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(where).To(Equal(`"locale" = 'en'' OR ''1''=''1'`))
+		})
+	})
+
+	Describe("Validate", func() {
+		It("should fail if column does not exist", func() {
+			_, err := compile(`{"column": "age", "op": "gt", "value": 18}`)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(Equal("invalid filters: column age does not exist"))
+		})
+
+		It("should fail if legacy filter column does not exist", func() {
+			_, err := compile(`{"NOTage": "18"}`)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(Equal("invalid filters: column age does not exist"))
+		})
+
+		It("should fail if column is not a valid identifier", func() {
+			_, err := compile(`{"column": "locale\" = 'en' OR \"1", "op": "eq", "value": "en"}`)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("invalid filters: invalid column"))
+		})
+
+		It("should fail if operator is unknown", func() {
+			_, err := compile(`{"column": "locale", "op": "like", "value": "en%"}`)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(Equal(`invalid filters: unknown operator "like"`))
+		})
+
+		It("should fail if group is empty", func() {
+			_, err := compile(`{"and": []}`)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(Equal("invalid filters: and/or groups cannot be empty"))
+		})
+
+		It("should fail if filter mixes groups and conditions", func() {
+			_, err := compile(`{"and": [{"column": "tz", "op": "is_null"}], "column": "locale", "op": "eq", "value": "en"}`)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(Equal("invalid filters: a filter must have only one of and, or, not or column"))
+		})
+
+		It("should fail if in has no values", func() {
+			_, err := compile(`{"column": "locale", "op": "in", "value": []}`)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(Equal("invalid filters: locale in needs a non empty list of values"))
+		})
+
+		It("should fail if comparison has a list value", func() {
+			_, err := compile(`{"column": "locale", "op": "eq", "value": ["en"]}`)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(Equal("invalid filters: locale eq needs a single value"))
+		})
+
+		It("should fail if between does not have two values", func() {
+			_, err := compile(`{"column": "seq_id", "op": "between", "value": [1]}`)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(Equal("invalid filters: seq_id between needs a list with two values"))
+		})
+
+		It("should fail if date is invalid", func() {
+			_, err := compile(`{"column": "created_at", "op": "after", "value": "yesterday"}`)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(Equal("invalid filters: created_at after needs a RFC3339 date"))
+		})
+
+		It("should fail if date comparisons are not on date columns", func() {
+			_, err := compile(`{"column": "locale", "op": "before", "value": "2016-01-01T00:00:00Z"}`)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(Equal("invalid filters: locale before needs a date column, locale is character varying"))
+
+			_, err = compile(`{"not": {"column": "seq_id", "op": "after", "value": "2016-01-01T00:00:00Z"}}`)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(Equal("invalid filters: seq_id after needs a date column, seq_id is bigint"))
+		})
+
+		It("should fail if between is on a text column", func() {
+			_, err := compile(`{"column": "region", "op": "between", "value": ["A", "M"]}`)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(Equal("invalid filters: region between cannot be used with text columns"))
+
+			_, err = compile(`{"column": "created_at", "op": "between", "value": ["2016-01-01T00:00:00Z", "2017-01-01T00:00:00Z"]}`)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("should fail if prefix is not on a text column", func() {
+			_, err := compile(`{"or": [{"column": "locale", "op": "prefix", "value": "en"}, {"column": "seq_id", "op": "prefix", "value": "1"}]}`)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(Equal("invalid filters: seq_id prefix needs a text column, seq_id is bigint"))
+		})
+	})
+
+	Describe("To Map", func() {
+		It("should keep zero values and normalized values", func() {
+			f, err := parse(`{"and": [{"column": "seq_id", "op": "eq", "value": 0}, {"column": "locale", "op": "in", "value": ["EN", "fr"]}]}`)
+			Expect(err).NotTo(HaveOccurred())
+			f.MapValues("locale", strings.ToLower)
+			res, err := f.ToMap()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(res).To(Equal(map[string]interface{}{
+				"and": []interface{}{
+					map[string]interface{}{"column": "seq_id", "op": "eq", "value": float64(0)},
+					map[string]interface{}{"column": "locale", "op": "in", "value": []interface{}{"en", "fr"}},
+				},
+			}))
+		})
+	})
+
+	Describe("With Column Filter", func() {
+		It("should add the column to legacy filters", func() {
+			filters := map[string]interface{}{"locale": "en"}
+			res := worker.WithColumnFilter(filters, "tz", []string{"-0300", "-0200"})
+			Expect(res).To(Equal(map[string]interface{}{"locale": "en", "tz": "-0300,-0200"}))
+			Expect(filters).To(Equal(map[string]interface{}{"locale": "en"}))
+		})
+
+		It("should add the column to filter expressions", func() {
+			filters := map[string]interface{}{"column": "locale", "op": "eq", "value": "en"}
+			res := worker.WithColumnFilter(filters, "tz", []string{"-0300"})
+			f, err := worker.ParseFilters(res)
+			Expect(err).NotTo(HaveOccurred())
+			where, params, err := f.Compile()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(orm.Formatter{}.FormatQuery(nil, where, params...))).To(Equal(`("locale" = 'en' AND "tz" IN ('-0300'))`))
+		})
+
+		It("should match null columns with the empty value", func() {
+			filters := map[string]interface{}{"locale": "en"}
+			res := worker.WithColumnFilter(filters, "tz", []string{"", "-0300"})
+			Expect(filters).To(Equal(map[string]interface{}{"locale": "en"}))
+			f, err := worker.ParseFilters(res)
+			Expect(err).NotTo(HaveOccurred())
+			where, params, err := f.Compile()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(orm.Formatter{}.FormatQuery(nil, where, params...))).To(Equal(`(("locale" IN ('en')) AND ("tz" IN ('','-0300') OR "tz" IS NULL))`))
+		})
+
+		It("should match null columns with the empty value without other filters", func() {
+			res := worker.WithColumnFilter(map[string]interface{}{}, "tz", []string{""})
+			where, params, err := worker.GetWhereClauseFromFilters(res)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(orm.Formatter{}.FormatQuery(nil, where, params...))).To(Equal(`("tz" IN ('') OR "tz" IS NULL)`))
+		})
+	})
+})
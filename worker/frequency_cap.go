@@ -0,0 +1,101 @@
+/*
+ * Copyright (c) 2026 TFG Co <backend@tfgco.com>
+ * Author: TFG Co <backend@tfgco.com>
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy of
+ * this software and associated documentation files (the "Software"), to deal in
+ * the Software without restriction, including without limitation the rights to
+ * use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+ * the Software, and to permit persons to whom the Software is furnished to do so,
+ * subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+ * FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+ * COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+ * IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+ * CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+ */
+
+package worker
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/topfreegames/marathon/interfaces"
+	"github.com/topfreegames/marathon/model"
+	redis "gopkg.in/redis.v5"
+)
+
+// frequencyCapScript adds the push to the sorted set of each cap, scored by the push time in
+// milliseconds, only if no cap was reached. ARGV has the push time, the push id and the window
+// and the max pushes of each key
+var frequencyCapScript = redis.NewScript(`
+local now = tonumber(ARGV[1])
+for i, key in ipairs(KEYS) do
+  local window = tonumber(ARGV[i * 2 + 1])
+  local limit = tonumber(ARGV[i * 2 + 2])
+  redis.call("ZREMRANGEBYSCORE", key, "-inf", now - window)
+  if redis.call("ZCARD", key) >= limit then
+    return 0
+  end
+end
+for i, key in ipairs(KEYS) do
+  redis.call("ZADD", key, now, ARGV[2])
+  redis.call("PEXPIRE", key, ARGV[i * 2 + 1])
+end
+return 1
+`)
+
+// GetFrequencyCapRedisKey returns the sorted set with the pushes the user received that count
+// for the cap
+func GetFrequencyCapRedisKey(app *model.App, frequencyCap model.FrequencyCap, userID string) string {
+	category := frequencyCap.Category
+	if category == "" {
+		category = "*"
+	}
+	return fmt.Sprintf("%s-CAP-%s-%d-%s", app.ID.String(), category, frequencyCap.Window, userID)
+}
+
+// ReserveFrequencyCaps counts the push in the caps and returns true if the user can receive it,
+// if any cap was reached it returns false and the push is not counted
+func ReserveFrequencyCaps(client *redis.Client, app *model.App, caps []model.FrequencyCap, userID, pushID string) (bool, error) {
+	if len(caps) == 0 {
+		return true, nil
+	}
+	keys := make([]string, 0, len(caps))
+	args := []interface{}{time.Now().UnixNano() / int64(time.Millisecond), pushID}
+	for _, frequencyCap := range caps {
+		keys = append(keys, GetFrequencyCapRedisKey(app, frequencyCap, userID))
+		args = append(args, frequencyCap.Window/int64(time.Millisecond), frequencyCap.MaxPushes)
+	}
+	allowed, err := frequencyCapScript.Run(client, keys, args...).Result()
+	if err != nil {
+		return false, err
+	}
+	return allowed.(int64) == 1, nil
+}
+
+// ReleaseFrequencyCaps removes from the caps a push that could not be sent
+func ReleaseFrequencyCaps(client *redis.Client, app *model.App, caps []model.FrequencyCap, userID, pushID string) error {
+	for _, frequencyCap := range caps {
+		err := client.ZRem(GetFrequencyCapRedisKey(app, frequencyCap, userID), pushID).Err()
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// IncrCappedUsers adds the users that were not sent the job because they reached a frequency cap
+func IncrCappedUsers(db interfaces.DB, job *model.Job, cappedUsers int) error {
+	if cappedUsers == 0 {
+		return nil
+	}
+	_, err := db.Exec("UPDATE jobs SET capped_users = capped_users + ? WHERE id = ?", cappedUsers, job.ID)
+	return err
+}
@@ -0,0 +1,132 @@
+/*
+ * Copyright (c) 2026 TFG Co <backend@tfgco.com>
+ * Author: TFG Co <backend@tfgco.com>
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy of
+ * this software and associated documentation files (the "Software"), to deal in
+ * the Software without restriction, including without limitation the rights to
+ * use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+ * the Software, and to permit persons to whom the Software is furnished to do so,
+ * subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+ * FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+ * COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+ * IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+ * CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+ */
+
+package worker_test
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	uuid "github.com/satori/go.uuid"
+	"github.com/topfreegames/marathon/model"
+	. "github.com/topfreegames/marathon/testing"
+	"github.com/topfreegames/marathon/worker"
+	"github.com/uber-go/zap"
+)
+
+var _ = Describe("Frequency caps", func() {
+	logger := zap.New(
+		zap.NewJSONEncoder(zap.NoTime()),
+		zap.FatalLevel,
+	)
+	w := worker.NewWorker(logger, GetConfPath())
+
+	var app *model.App
+
+	BeforeEach(func() {
+		w.RedisClient.FlushAll()
+		app = &model.App{
+			ID: uuid.NewV4(),
+			FrequencyCaps: []model.FrequencyCap{
+				{MaxPushes: 3, Window: int64(time.Hour)},
+				{Category: "promo", MaxPushes: 1, Window: int64(24 * time.Hour)},
+			},
+		}
+	})
+
+	Describe("Caps for a category", func() {
+		It("should return the caps without a category and the caps of the category", func() {
+			Expect(app.FrequencyCapsFor("")).To(Equal(app.FrequencyCaps[:1]))
+			Expect(app.FrequencyCapsFor("news")).To(Equal(app.FrequencyCaps[:1]))
+			Expect(app.FrequencyCapsFor("promo")).To(Equal(app.FrequencyCaps))
+		})
+	})
+
+	Describe("Reserve", func() {
+		It("should allow every push if there are no caps", func() {
+			for i := 0; i < 10; i++ {
+				allowed, err := worker.ReserveFrequencyCaps(w.RedisClient, app, nil, "user", uuid.NewV4().String())
+				Expect(err).NotTo(HaveOccurred())
+				Expect(allowed).To(BeTrue())
+			}
+		})
+
+		It("should allow pushes until the cap is reached", func() {
+			caps := app.FrequencyCapsFor("news")
+			for i := 0; i < 3; i++ {
+				allowed, err := worker.ReserveFrequencyCaps(w.RedisClient, app, caps, "user", uuid.NewV4().String())
+				Expect(err).NotTo(HaveOccurred())
+				Expect(allowed).To(BeTrue())
+			}
+			allowed, err := worker.ReserveFrequencyCaps(w.RedisClient, app, caps, "user", uuid.NewV4().String())
+			Expect(err).NotTo(HaveOccurred())
+			Expect(allowed).To(BeFalse())
+
+			allowed, err = worker.ReserveFrequencyCaps(w.RedisClient, app, caps, "other", uuid.NewV4().String())
+			Expect(err).NotTo(HaveOccurred())
+			Expect(allowed).To(BeTrue())
+		})
+
+		It("should not count the push in any cap if one of them was reached", func() {
+			allowed, err := worker.ReserveFrequencyCaps(w.RedisClient, app, app.FrequencyCapsFor("promo"), "user", uuid.NewV4().String())
+			Expect(err).NotTo(HaveOccurred())
+			Expect(allowed).To(BeTrue())
+			allowed, err = worker.ReserveFrequencyCaps(w.RedisClient, app, app.FrequencyCapsFor("promo"), "user", uuid.NewV4().String())
+			Expect(err).NotTo(HaveOccurred())
+			Expect(allowed).To(BeFalse())
+
+			count, err := w.RedisClient.ZCard(worker.GetFrequencyCapRedisKey(app, app.FrequencyCaps[0], "user")).Result()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(count).To(BeEquivalentTo(1))
+		})
+
+		It("should forget the pushes older than the window", func() {
+			app.FrequencyCaps = []model.FrequencyCap{{MaxPushes: 1, Window: int64(100 * time.Millisecond)}}
+			allowed, err := worker.ReserveFrequencyCaps(w.RedisClient, app, app.FrequencyCaps, "user", uuid.NewV4().String())
+			Expect(err).NotTo(HaveOccurred())
+			Expect(allowed).To(BeTrue())
+
+			time.Sleep(200 * time.Millisecond)
+			allowed, err = worker.ReserveFrequencyCaps(w.RedisClient, app, app.FrequencyCaps, "user", uuid.NewV4().String())
+			Expect(err).NotTo(HaveOccurred())
+			Expect(allowed).To(BeTrue())
+		})
+	})
+
+	Describe("Release", func() {
+		It("should remove the push from the caps", func() {
+			caps := app.FrequencyCapsFor("promo")
+			pushID := uuid.NewV4().String()
+			allowed, err := worker.ReserveFrequencyCaps(w.RedisClient, app, caps, "user", pushID)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(allowed).To(BeTrue())
+
+			err = worker.ReleaseFrequencyCaps(w.RedisClient, app, caps, "user", pushID)
+			Expect(err).NotTo(HaveOccurred())
+
+			allowed, err = worker.ReserveFrequencyCaps(w.RedisClient, app, caps, "user", uuid.NewV4().String())
+			Expect(err).NotTo(HaveOccurred())
+			Expect(allowed).To(BeTrue())
+		})
+	})
+})
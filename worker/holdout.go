@@ -0,0 +1,63 @@
+/*
+ * Copyright (c) 2026 TFG Co <backend@tfgco.com>
+ * Author: TFG Co <backend@tfgco.com>
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy of
+ * this software and associated documentation files (the "Software"), to deal in
+ * the Software without restriction, including without limitation the rights to
+ * use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+ * the Software, and to permit persons to whom the Software is furnished to do so,
+ * subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+ * FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+ * COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+ * IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+ * CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+ */
+
+package worker
+
+import (
+	"fmt"
+
+	"github.com/topfreegames/marathon/interfaces"
+	"github.com/topfreegames/marathon/model"
+	"gopkg.in/pg.v5"
+)
+
+// RemoveHoldoutUsers returns the users ids that are not in the holdout group of the job app
+func RemoveHoldoutUsers(job *model.Job, ids []string) []string {
+	if !job.UseHoldout || job.App.HoldoutPercentage == 0 {
+		return ids
+	}
+	remaining := make([]string, 0, len(ids))
+	for _, id := range ids {
+		if !job.App.InHoldout(id) {
+			remaining = append(remaining, id)
+		}
+	}
+	return remaining
+}
+
+// IncrHoldoutUsers adds the users that were held out of the job
+func IncrHoldoutUsers(db interfaces.DB, job *model.Job, holdoutUsers int) error {
+	if holdoutUsers == 0 {
+		return nil
+	}
+	_, err := db.Exec("UPDATE jobs SET holdout_users = holdout_users + ? WHERE id = ?", holdoutUsers, job.ID)
+	return err
+}
+
+// GetUserIDsPage returns the next page of the distinct users ids of the push db table that come
+// after the given user id, the pages are ordered by user id
+func GetUserIDsPage(db interfaces.DB, tableName, after string, limit int) ([]string, error) {
+	var ids pg.Strings
+	query := fmt.Sprintf("SELECT DISTINCT user_id FROM %s WHERE user_id > ? ORDER BY user_id LIMIT ?", tableName)
+	_, err := db.Query(&ids, query, after, limit)
+	return ids, err
+}
@@ -0,0 +1,94 @@
+/*
+ * Copyright (c) 2026 TFG Co <backend@tfgco.com>
+ * Author: TFG Co <backend@tfgco.com>
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy of
+ * this software and associated documentation files (the "Software"), to deal in
+ * the Software without restriction, including without limitation the rights to
+ * use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+ * the Software, and to permit persons to whom the Software is furnished to do so,
+ * subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+ * FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+ * COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+ * IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+ * CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+ */
+
+package worker_test
+
+import (
+	"fmt"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/topfreegames/marathon/model"
+	"github.com/topfreegames/marathon/worker"
+)
+
+var _ = Describe("Holdout", func() {
+	ids := make([]string, 1000)
+	for i := range ids {
+		ids[i] = fmt.Sprintf("user-%d", i)
+	}
+
+	Describe("In holdout", func() {
+		It("should always put the user in the same bucket", func() {
+			app := &model.App{HoldoutPercentage: 0.1, HoldoutSalt: "salt"}
+			for _, id := range ids {
+				Expect(app.HoldoutBucket(id)).To(Equal(app.HoldoutBucket(id)))
+				Expect(app.HoldoutBucket(id)).To(BeNumerically("<", model.HoldoutBuckets))
+			}
+		})
+
+		It("should hold out a stable percentage of the users", func() {
+			app := &model.App{HoldoutPercentage: 0.1, HoldoutSalt: "salt"}
+			held := map[string]bool{}
+			for _, id := range ids {
+				if app.InHoldout(id) {
+					held[id] = true
+				}
+			}
+			Expect(len(held)).To(BeNumerically("~", 100, 40))
+
+			// increasing the percentage keeps the users that were already held out
+			app.HoldoutPercentage = 0.2
+			for id := range held {
+				Expect(app.InHoldout(id)).To(BeTrue())
+			}
+		})
+
+		It("should hold out other users if the salt changes", func() {
+			app := &model.App{HoldoutPercentage: 0.5, HoldoutSalt: "salt"}
+			other := &model.App{HoldoutPercentage: 0.5, HoldoutSalt: "other"}
+			different := 0
+			for _, id := range ids {
+				if app.InHoldout(id) != other.InHoldout(id) {
+					different++
+				}
+			}
+			Expect(different).To(BeNumerically(">", 0))
+		})
+	})
+
+	Describe("Remove holdout users", func() {
+		It("should keep every user if the job does not use the holdout", func() {
+			job := &model.Job{App: model.App{HoldoutPercentage: 0.5, HoldoutSalt: "salt"}}
+			Expect(worker.RemoveHoldoutUsers(job, ids)).To(Equal(ids))
+		})
+
+		It("should remove the users in the holdout group", func() {
+			job := &model.Job{UseHoldout: true, App: model.App{HoldoutPercentage: 0.5, HoldoutSalt: "salt"}}
+			remaining := worker.RemoveHoldoutUsers(job, ids)
+			Expect(len(remaining)).To(BeNumerically("<", len(ids)))
+			for _, id := range remaining {
+				Expect(job.App.InHoldout(id)).To(BeFalse())
+			}
+		})
+	})
+})
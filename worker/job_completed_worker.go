@@ -26,8 +26,9 @@ import (
 	"bytes"
 	"fmt"
 	goworkers2 "github.com/digitalocean/go-workers2"
-	"io"
 
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/s3"
 	"github.com/satori/go.uuid"
 	"github.com/topfreegames/marathon/email"
 	"github.com/topfreegames/marathon/log"
@@ -53,29 +54,57 @@ func NewJobCompletedWorker(workers *Worker) *JobCompletedWorker {
 	return b
 }
 
+// minS3PartSize is the minimum size of every part of a multipart upload but the last one
+const minS3PartSize = 5 * 1024 * 1024
+
+// flushRedisList writes the ids of the redis list to a csv with the header in s3 and deletes
+// the list, the ids are read in pages and uploaded in parts so they are never all in memory
+func (b *JobCompletedWorker) flushRedisList(job *model.Job, key, header, writePath string) {
+	pageSize := int64(b.Workers.Config.GetInt("workers.jobCompleted.pageSize"))
+	upload, err := b.Workers.S3Client.InitMultipartUpload(writePath)
+	b.checkErr(job, err)
+
+	parts := []*s3.CompletedPart{}
+	csvBuffer := bytes.NewBufferString(fmt.Sprintf("%s\n", header))
+	uploadPart := func() {
+		partNumber := int64(len(parts) + 1)
+		res, err := b.Workers.S3Client.UploadPart(csvBuffer, upload, partNumber)
+		b.checkErr(job, err)
+		parts = append(parts, &s3.CompletedPart{ETag: res.ETag, PartNumber: aws.Int64(partNumber)})
+		csvBuffer = &bytes.Buffer{}
+	}
+	for start := int64(0); ; start += pageSize {
+		ids, err := b.Workers.RedisClient.LRange(key, start, start+pageSize-1).Result()
+		b.checkErr(job, err)
+		for _, id := range ids {
+			csvBuffer.WriteString(fmt.Sprintf("%s\n", id))
+		}
+		if csvBuffer.Len() >= minS3PartSize {
+			uploadPart()
+		}
+		if int64(len(ids)) < pageSize {
+			break
+		}
+	}
+	if csvBuffer.Len() > 0 || len(parts) == 0 {
+		uploadPart()
+	}
+	err = b.Workers.S3Client.CompleteMultipartUpload(upload, parts)
+	b.checkErr(job, err)
+
+	err = b.Workers.RedisClient.Del(key).Err()
+	b.checkErr(job, err)
+}
+
 func (b *JobCompletedWorker) flushControlGroup(job *model.Job) {
 	hash := job.ID.String()
 	hash = fmt.Sprintf("%s-CONTROL", hash)
-	controlGroup, err := b.Workers.RedisClient.LRange(hash, 0, -1).Result()
-	b.checkErr(job, err)
 
 	folder := b.Workers.Config.GetString("s3.controlGroupFolder")
-	csvBuffer := &bytes.Buffer{}
-	csvWriter := io.Writer(csvBuffer)
-	csvWriter.Write([]byte("controlGroupUserIds\n"))
-	for _, user := range controlGroup {
-		csvWriter.Write([]byte(fmt.Sprintf("%s\n", user)))
-	}
-
 	bucket := b.Workers.Config.GetString("s3.bucket")
 	writePath := fmt.Sprintf("%s/%s/job-%s.csv", bucket, folder, job.ID.String())
-	csvBytes := csvBuffer.Bytes()
-	_, err = b.Workers.S3Client.PutObject(writePath, &csvBytes)
-	b.checkErr(job, err)
+	b.flushRedisList(job, hash, "controlGroupUserIds", writePath)
 	b.updateJobControlGroupCSVPath(job, writePath)
-
-	err = b.Workers.RedisClient.Del(hash).Err()
-	b.checkErr(job, err)
 }
 
 func (b *JobCompletedWorker) updateJobControlGroupCSVPath(job *model.Job, csvPath string) {
@@ -84,6 +113,41 @@ func (b *JobCompletedWorker) updateJobControlGroupCSVPath(job *model.Job, csvPat
 	b.checkErr(job, err)
 }
 
+func (b *JobCompletedWorker) flushTreatedGroup(job *model.Job) {
+	hash := job.ID.String()
+	hash = fmt.Sprintf("%s-TREATED", hash)
+
+	folder := b.Workers.Config.GetString("s3.controlGroupFolder")
+	bucket := b.Workers.Config.GetString("s3.bucket")
+	writePath := fmt.Sprintf("%s/%s/job-%s-treated.csv", bucket, folder, job.ID.String())
+	b.flushRedisList(job, hash, "treatedUserIds", writePath)
+	b.updateJobTreatedCSVPath(job, writePath)
+}
+
+func (b *JobCompletedWorker) updateJobTreatedCSVPath(job *model.Job, csvPath string) {
+	job.TreatedCSVPath = csvPath
+	_, err := b.Workers.MarathonDB.Model(job).Set("treated_csv_path = ?treated_csv_path").Update()
+	b.checkErr(job, err)
+}
+
+func (b *JobCompletedWorker) flushVariants(job *model.Job) {
+	folder := b.Workers.Config.GetString("s3.controlGroupFolder")
+	bucket := b.Workers.Config.GetString("s3.bucket")
+	paths := map[string]string{}
+	for _, variant := range job.TemplateNames() {
+		writePath := fmt.Sprintf("%s/%s/job-%s-%s.csv", bucket, folder, job.ID.String(), variant)
+		b.flushRedisList(job, GetVariantRedisKey(job, variant), "variantUserIds", writePath)
+		paths[variant] = writePath
+	}
+	b.updateJobVariantCSVPaths(job, paths)
+}
+
+func (b *JobCompletedWorker) updateJobVariantCSVPaths(job *model.Job, paths map[string]string) {
+	job.VariantCSVPaths = paths
+	_, err := b.Workers.MarathonDB.Model(job).Set("variant_csv_paths = ?variant_csv_paths").Update()
+	b.checkErr(job, err)
+}
+
 // Process processes the messages sent to worker queue
 func (b *JobCompletedWorker) Process(message *goworkers2.Msg) error {
 	arr, err := message.Args().Array()
@@ -112,6 +176,16 @@ func (b *JobCompletedWorker) Process(message *goworkers2.Msg) error {
 	job.TagRunning(b.Workers.MarathonDB, nameJobCompleted, "sending control group")
 	b.flushControlGroup(job)
 
+	if job.ControlGroup > 0 {
+		job.TagRunning(b.Workers.MarathonDB, nameJobCompleted, "sending treated group")
+		b.flushTreatedGroup(job)
+	}
+
+	if job.HasVariants() {
+		job.TagRunning(b.Workers.MarathonDB, nameJobCompleted, "sending variants")
+		b.flushVariants(job)
+	}
+
 	job.TagSuccess(b.Workers.MarathonDB, nameJobCompleted, "finished")
 	b.Workers.Statsd.Incr(JobCompletedWorkerCompleted, job.Labels(), 1)
 
@@ -24,6 +24,9 @@ package worker_test
 
 import (
 	"encoding/json"
+	"fmt"
+	"strings"
+
 	goworkers2 "github.com/digitalocean/go-workers2"
 
 	. "github.com/onsi/ginkgo"
@@ -72,6 +75,96 @@ var _ = Describe("JobCompleted Worker", func() {
 			}).ShouldNot(Panic())
 		})
 
+		It("should write a csv with the users of each variant", func() {
+			template2 := CreateTestTemplate(w.MarathonDB, app.ID)
+			variantJob := CreateTestJob(w.MarathonDB, app.ID, fmt.Sprintf("%s,%s", template.Name, template2.Name))
+			w.SendVariantsToRedis(variantJob, map[string][]string{
+				template.Name:  {"user1", "user2"},
+				template2.Name: {"user3"},
+			})
+
+			msgB, err := json.Marshal(map[string][]interface{}{
+				"args": {variantJob.ID.String()},
+			})
+			Expect(err).NotTo(HaveOccurred())
+			message, err := goworkers2.NewMsg(string(msgB))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(func() { jobCompletedWorker.Process(message) }).ShouldNot(Panic())
+
+			dbJob := &model.Job{}
+			err = w.MarathonDB.Model(dbJob).Where("id = ?", variantJob.ID).Select()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(dbJob.VariantCSVPaths).To(HaveLen(2))
+
+			csv, err := fakeS3.GetObject(dbJob.VariantCSVPaths[template.Name])
+			Expect(err).NotTo(HaveOccurred())
+			lines := strings.Split(strings.TrimSpace(string(csv)), "\n")
+			Expect(lines[0]).To(Equal("variantUserIds"))
+			Expect(lines[1:]).To(ConsistOf("user1", "user2"))
+
+			csv, err = fakeS3.GetObject(dbJob.VariantCSVPaths[template2.Name])
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(csv)).To(Equal("variantUserIds\nuser3\n"))
+
+			exists, err := w.RedisClient.Exists(worker.GetVariantRedisKey(variantJob, template.Name)).Result()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(exists).To(BeFalse())
+		})
+
+		It("should write a csv with the treated users if the job has a control group", func() {
+			controlGroupJob := CreateTestJob(w.MarathonDB, app.ID, template.Name, map[string]interface{}{
+				"controlGroup": 0.2,
+			})
+			w.SendTreatedGroupToRedis(controlGroupJob, []string{"user1", "user2"})
+
+			msgB, err := json.Marshal(map[string][]interface{}{
+				"args": {controlGroupJob.ID.String()},
+			})
+			Expect(err).NotTo(HaveOccurred())
+			message, err := goworkers2.NewMsg(string(msgB))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(func() { jobCompletedWorker.Process(message) }).ShouldNot(Panic())
+
+			dbJob := &model.Job{}
+			err = w.MarathonDB.Model(dbJob).Where("id = ?", controlGroupJob.ID).Select()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(dbJob.TreatedCSVPath).To(HaveSuffix(fmt.Sprintf("job-%s-treated.csv", controlGroupJob.ID.String())))
+
+			csv, err := fakeS3.GetObject(dbJob.TreatedCSVPath)
+			Expect(err).NotTo(HaveOccurred())
+			lines := strings.Split(strings.TrimSpace(string(csv)), "\n")
+			Expect(lines[0]).To(Equal("treatedUserIds"))
+			Expect(lines[1:]).To(ConsistOf("user1", "user2"))
+		})
+
+		It("should write a csv with the control group read in pages", func() {
+			ids := []string{"user1", "user2", "user3", "user4", "user5"}
+			w.SendControlGroupToRedis(job, ids)
+
+			msgB, err := json.Marshal(map[string][]interface{}{
+				"args": {job.ID.String()},
+			})
+			Expect(err).NotTo(HaveOccurred())
+			message, err := goworkers2.NewMsg(string(msgB))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(func() { jobCompletedWorker.Process(message) }).ShouldNot(Panic())
+
+			dbJob := &model.Job{}
+			err = w.MarathonDB.Model(dbJob).Where("id = ?", job.ID).Select()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(dbJob.ControlGroupCSVPath).To(HaveSuffix(fmt.Sprintf("job-%s.csv", job.ID.String())))
+
+			csv, err := fakeS3.GetObject(dbJob.ControlGroupCSVPath)
+			Expect(err).NotTo(HaveOccurred())
+			lines := strings.Split(strings.TrimSpace(string(csv)), "\n")
+			Expect(lines[0]).To(Equal("controlGroupUserIds"))
+			Expect(lines[1:]).To(ConsistOf("user1", "user2", "user3", "user4", "user5"))
+
+			exists, err := w.RedisClient.Exists(fmt.Sprintf("%s-CONTROL", job.ID.String())).Result()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(exists).To(BeFalse())
+		})
+
 		It("should not process when job is not found in db", func() {
 			_, err := w.MarathonDB.Exec("DELETE FROM jobs;")
 			Expect(err).NotTo(HaveOccurred())
@@ -0,0 +1,82 @@
+/*
+ * Copyright (c) 2026 TFG Co <backend@tfgco.com>
+ * Author: TFG Co <backend@tfgco.com>
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy of
+ * this software and associated documentation files (the "Software"), to deal in
+ * the Software without restriction, including without limitation the rights to
+ * use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+ * the Software, and to permit persons to whom the Software is furnished to do so,
+ * subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+ * FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+ * COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+ * IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+ * CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+ */
+
+package worker
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	uuid "github.com/satori/go.uuid"
+	"github.com/topfreegames/marathon/interfaces"
+	"github.com/topfreegames/marathon/model"
+)
+
+const (
+	// LocaleStatsExact counts the users that got a template in their own locale
+	LocaleStatsExact = "exact"
+	// LocaleStatsMissing counts the users skipped because no locale of their chain has a template
+	LocaleStatsMissing = "missing"
+)
+
+// LocaleStatsFallback returns the key that counts the users served by the given fallback level
+func LocaleStatsFallback(level int) string {
+	if level == 0 {
+		return LocaleStatsExact
+	}
+	return fmt.Sprintf("fallback_%d", level)
+}
+
+// SelectTemplate returns the template of the first locale of the chain that has one and its
+// position in the chain, the bool is false if none of them has a template
+func SelectTemplate(templatesByLocale map[string]model.Template, chain []string) (model.Template, int, bool) {
+	for level, locale := range chain {
+		if template, ok := templatesByLocale[locale]; ok {
+			return template, level, true
+		}
+	}
+	return model.Template{}, 0, false
+}
+
+// IncrLocaleStats adds the counters to the locale stats of the job
+func IncrLocaleStats(db interfaces.DB, jobID uuid.UUID, stats map[string]int) error {
+	if len(stats) == 0 {
+		return nil
+	}
+	keys := []string{}
+	for key := range stats {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	pairs := []string{}
+	params := []interface{}{}
+	for _, key := range keys {
+		pairs = append(pairs, "?::text, COALESCE(locale_stats->>?, '0')::int + ?")
+		params = append(params, key, key, stats[key])
+	}
+	query := fmt.Sprintf("UPDATE jobs SET locale_stats = locale_stats || jsonb_build_object(%s) WHERE id = ?", strings.Join(pairs, ", "))
+	params = append(params, jobID)
+	_, err := db.Exec(query, params...)
+	return err
+}
@@ -0,0 +1,79 @@
+/*
+ * Copyright (c) 2026 TFG Co <backend@tfgco.com>
+ * Author: TFG Co <backend@tfgco.com>
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy of
+ * this software and associated documentation files (the "Software"), to deal in
+ * the Software without restriction, including without limitation the rights to
+ * use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+ * the Software, and to permit persons to whom the Software is furnished to do so,
+ * subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+ * FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+ * COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+ * IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+ * CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+ */
+
+package worker_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/topfreegames/marathon/model"
+	"github.com/topfreegames/marathon/worker"
+)
+
+var _ = Describe("Locale", func() {
+	app := &model.App{
+		LocaleFallbacks: map[string][]string{
+			"pt-br": {"pt-pt"},
+			"pt":    {"es"},
+		},
+	}
+
+	Describe("Locale chain", func() {
+		It("should normalize the locale", func() {
+			Expect(model.NormalizeLocale("pt_BR")).To(Equal("pt-br"))
+			Expect(model.NormalizeLocale("pt-BR")).To(Equal("pt-br"))
+			Expect(model.NormalizeLocale(" PT ")).To(Equal("pt"))
+		})
+
+		It("should return the locale, its fallbacks, its language and en", func() {
+			Expect(app.LocaleChain("pt_BR")).To(Equal([]string{"pt-br", "pt-pt", "pt", "es", "en"}))
+			Expect(app.LocaleChain("PT")).To(Equal([]string{"pt", "es", "en"}))
+			Expect(app.LocaleChain("fr-CA")).To(Equal([]string{"fr-ca", "fr", "en"}))
+			Expect(app.LocaleChain("en")).To(Equal([]string{"en"}))
+		})
+	})
+
+	Describe("Select template", func() {
+		templatesByLocale := map[string]model.Template{
+			"es": {Locale: "es"},
+			"en": {Locale: "en"},
+		}
+
+		It("should return the first template of the chain and its level", func() {
+			template, level, ok := worker.SelectTemplate(templatesByLocale, app.LocaleChain("pt-BR"))
+			Expect(ok).To(BeTrue())
+			Expect(template.Locale).To(Equal("es"))
+			Expect(level).To(Equal(3))
+			Expect(worker.LocaleStatsFallback(level)).To(Equal("fallback_3"))
+
+			template, level, ok = worker.SelectTemplate(templatesByLocale, app.LocaleChain("ES"))
+			Expect(ok).To(BeTrue())
+			Expect(template.Locale).To(Equal("es"))
+			Expect(worker.LocaleStatsFallback(level)).To(Equal(worker.LocaleStatsExact))
+		})
+
+		It("should return false if no locale of the chain has a template", func() {
+			_, _, ok := worker.SelectTemplate(map[string]model.Template{"es": {}}, app.LocaleChain("de"))
+			Expect(ok).To(BeFalse())
+		})
+	})
+})
@@ -25,6 +25,13 @@ const (
 	ResumeJobWorkerCompleted = "completed_resume_job_worker"
 	ResumeJobWorkerError     = "error_resume_job_worker"
 
+	UpliftWorkerStart     = "starting_uplift_worker"
+	UpliftWorkerCompleted = "completed_uplift_worker"
+	UpliftWorkerError     = "error_uplift_worker"
+
+	SchedulerJobCreated = "scheduler_job_created"
+	SchedulerError      = "error_scheduler"
+
 	GetCsvFromS3Timing   = "get_csv_from_s3"
 	GetUsersFromDbTiming = "get_from_pg"
 )
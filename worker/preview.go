@@ -0,0 +1,111 @@
+/*
+ * Copyright (c) 2026 TFG Co <backend@tfgco.com>
+ * Author: TFG Co <backend@tfgco.com>
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy of
+ * this software and associated documentation files (the "Software"), to deal in
+ * the Software without restriction, including without limitation the rights to
+ * use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+ * the Software, and to permit persons to whom the Software is furnished to do so,
+ * subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+ * FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+ * COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+ * IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+ * CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+ */
+
+package worker
+
+import (
+	"fmt"
+	"time"
+
+	uuid "github.com/satori/go.uuid"
+	"github.com/topfreegames/marathon/messages"
+	"github.com/topfreegames/marathon/model"
+	pg "gopkg.in/pg.v5"
+)
+
+// BuildPushMessage renders the template with the context and returns the message
+// serialized the same way the push producer sends it to kafka
+func BuildPushMessage(service, deviceToken string, template model.Template, preview *model.TemplatePreview, pushMetadata map[string]interface{}) (string, error) {
+	msg, err := RenderTemplate(template, preview.Context, nil)
+	if err != nil {
+		return "", err
+	}
+	pushExpiry := preview.ExpiresAt / 1000000000 // convert from nanoseconds to seconds
+
+	switch service {
+	case "apns":
+		apnsMessage := messages.NewAPNSMessage(deviceToken, pushExpiry, msg, preview.Metadata, pushMetadata, template.Name)
+		apnsMessage.SetOptions(template.PushOptions)
+		return apnsMessage.ToJSON()
+	case "gcm":
+		gcmMessage := messages.NewGCMMessage(deviceToken, msg, preview.Metadata, pushMetadata, pushExpiry, template.Name)
+		gcmMessage.SetOptions(template.PushOptions)
+		return gcmMessage.ToJSON()
+	}
+	return "", fmt.Errorf("service should be in ['apns', 'gcm']")
+}
+
+// SendTemplatePreview sends the template to the preview users and tokens of the service through
+// the push producer, it returns how many pushes were sent
+func (w *Worker) SendTemplatePreview(app *model.App, service string, template model.Template, preview *model.TemplatePreview) (int, error) {
+	users := []User{}
+	if len(preview.UserIDs) > 0 {
+		query := fmt.Sprintf("SELECT %s FROM %s WHERE user_id IN (?)", GetUsersSelect(TemplateUserColumns(template)), GetPushDBTableName(app.Name, service))
+		_, err := w.PushDB.Query(&users, query, pg.In(preview.UserIDs))
+		if err != nil {
+			return 0, err
+		}
+	}
+	for _, token := range preview.Tokens {
+		users = append(users, User{Token: token})
+	}
+
+	topic := BuildTopicName(app.Name, service, w.Config.GetString("workers.topicTemplate"))
+	pushExpiry := preview.ExpiresAt / 1000000000 // convert from nanoseconds to seconds
+	for i, user := range users {
+		msg, err := RenderTemplate(template, preview.Context, &user)
+		if err != nil {
+			return i, err
+		}
+		pushMetadata := NewTemplatePreviewMetadata(template.Name, preview)
+		pushMetadata["userId"] = user.UserID
+		pushMetadata["muid"] = uuid.NewV4().String()
+
+		switch service {
+		case "apns":
+			err = w.Kafka.SendAPNSPush(topic, user.Token, msg, preview.Metadata, pushMetadata, pushExpiry, template.Name, template.PushOptions)
+		case "gcm":
+			err = w.Kafka.SendGCMPush(topic, user.Token, msg, preview.Metadata, pushMetadata, pushExpiry, template.Name, template.PushOptions)
+		default:
+			err = fmt.Errorf("service should be in ['apns', 'gcm']")
+		}
+		if err != nil {
+			return i, err
+		}
+	}
+	return len(users), nil
+}
+
+// NewTemplatePreviewMetadata returns the push metadata of template previews and test sends
+func NewTemplatePreviewMetadata(templateName string, preview *model.TemplatePreview) map[string]interface{} {
+	pushMetadata := map[string]interface{}{
+		"pushTime":     time.Now().Unix(),
+		"templateName": templateName,
+		"pushType":     "test",
+	}
+	if val, ok := preview.Metadata["dryRun"]; ok {
+		if dryRun, ok := val.(bool); ok {
+			pushMetadata["dryRun"] = dryRun
+		}
+	}
+	return pushMetadata
+}
@@ -0,0 +1,84 @@
+/*
+ * Copyright (c) 2026 TFG Co <backend@tfgco.com>
+ * Author: TFG Co <backend@tfgco.com>
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy of
+ * this software and associated documentation files (the "Software"), to deal in
+ * the Software without restriction, including without limitation the rights to
+ * use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+ * the Software, and to permit persons to whom the Software is furnished to do so,
+ * subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+ * FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+ * COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+ * IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+ * CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+ */
+
+package worker
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/spf13/viper"
+	"github.com/topfreegames/marathon/model"
+)
+
+// Priorities are the job priorities, from the most to the least urgent
+var Priorities = []string{model.PriorityHigh, model.PriorityNormal, model.PriorityLow}
+
+// PriorityQueue returns the queue of the worker that sends the batches of jobs with the given
+// priority, the normal priority queue keeps the worker name so enqueued batches are not lost
+func PriorityQueue(worker, priority string) string {
+	if priority == "" || priority == model.PriorityNormal {
+		return worker
+	}
+	return fmt.Sprintf("%s_%s", worker, priority)
+}
+
+// QueuePriority returns the priority of the jobs sent by the queue of the worker, it returns
+// false if the queue is not one of the worker queues
+func QueuePriority(worker, queue string) (string, bool) {
+	for _, priority := range Priorities {
+		if PriorityQueue(worker, priority) == queue {
+			return priority, true
+		}
+	}
+	return model.PriorityNormal, false
+}
+
+// PriorityConcurrency returns the concurrency of each priority queue of the worker. The normal
+// priority queue keeps the worker concurrency, so the batches of normal jobs are sent as fast as
+// before the priority queues, and the other queues get it scaled by their weight in
+// workers.priorities.weights relative to the normal weight. The concurrency of a queue can be
+// set by workers.<worker>.priorities.<priority>.concurrency. Every queue has at least one
+// goroutine so low priority jobs are slowed down but never starved
+func PriorityConcurrency(config *viper.Viper, worker string) map[string]int {
+	total := config.GetInt(fmt.Sprintf("workers.%s.concurrency", worker))
+	normalWeight := config.GetInt(fmt.Sprintf("workers.priorities.weights.%s", model.PriorityNormal))
+
+	concurrency := map[string]int{}
+	for _, priority := range Priorities {
+		key := fmt.Sprintf("workers.%s.priorities.%s.concurrency", worker, priority)
+		if config.IsSet(key) {
+			concurrency[priority] = config.GetInt(key)
+			continue
+		}
+		weight := config.GetInt(fmt.Sprintf("workers.priorities.weights.%s", priority))
+		n := total
+		if priority != model.PriorityNormal && normalWeight > 0 {
+			n = int(math.Round(float64(total*weight) / float64(normalWeight)))
+		}
+		if n < 1 {
+			n = 1
+		}
+		concurrency[priority] = n
+	}
+	return concurrency
+}
@@ -0,0 +1,98 @@
+/*
+ * Copyright (c) 2026 TFG Co <backend@tfgco.com>
+ * Author: TFG Co <backend@tfgco.com>
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy of
+ * this software and associated documentation files (the "Software"), to deal in
+ * the Software without restriction, including without limitation the rights to
+ * use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+ * the Software, and to permit persons to whom the Software is furnished to do so,
+ * subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+ * FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+ * COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+ * IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+ * CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+ */
+
+package worker_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/spf13/viper"
+	"github.com/topfreegames/marathon/worker"
+)
+
+var _ = Describe("Priorities", func() {
+	Describe("Priority queue", func() {
+		It("should keep the worker queue for normal priority jobs", func() {
+			Expect(worker.PriorityQueue("process_batch_worker", "normal")).To(Equal("process_batch_worker"))
+			Expect(worker.PriorityQueue("process_batch_worker", "")).To(Equal("process_batch_worker"))
+		})
+
+		It("should use a queue for each other priority", func() {
+			Expect(worker.PriorityQueue("process_batch_worker", "high")).To(Equal("process_batch_worker_high"))
+			Expect(worker.PriorityQueue("direct_worker", "low")).To(Equal("direct_worker_low"))
+		})
+
+		It("should return the priority of a queue", func() {
+			priority, ok := worker.QueuePriority("direct_worker", "direct_worker_high")
+			Expect(ok).To(BeTrue())
+			Expect(priority).To(Equal("high"))
+
+			priority, ok = worker.QueuePriority("direct_worker", "direct_worker")
+			Expect(ok).To(BeTrue())
+			Expect(priority).To(Equal("normal"))
+
+			_, ok = worker.QueuePriority("direct_worker", "process_batch_worker_high")
+			Expect(ok).To(BeFalse())
+		})
+	})
+
+	Describe("Priority concurrency", func() {
+		var config *viper.Viper
+
+		BeforeEach(func() {
+			config = viper.New()
+			config.Set("workers.processBatch.concurrency", 20)
+			config.Set("workers.priorities.weights.high", 6)
+			config.Set("workers.priorities.weights.normal", 3)
+			config.Set("workers.priorities.weights.low", 1)
+		})
+
+		It("should keep the worker concurrency for the normal queue and scale the others by their weights", func() {
+			Expect(worker.PriorityConcurrency(config, "processBatch")).To(Equal(map[string]int{
+				"high":   40,
+				"normal": 20,
+				"low":    7,
+			}))
+		})
+
+		It("should use the worker concurrency for every queue without a normal weight", func() {
+			config.Set("workers.priorities.weights.normal", 0)
+			Expect(worker.PriorityConcurrency(config, "processBatch")).To(Equal(map[string]int{
+				"high":   20,
+				"normal": 20,
+				"low":    20,
+			}))
+		})
+
+		It("should give at least one goroutine to every queue", func() {
+			config.Set("workers.priorities.weights.low", 0)
+			Expect(worker.PriorityConcurrency(config, "processBatch")["low"]).To(Equal(1))
+		})
+
+		It("should use the concurrency configured for a queue", func() {
+			config.Set("workers.processBatch.priorities.high.concurrency", 30)
+			concurrency := worker.PriorityConcurrency(config, "processBatch")
+			Expect(concurrency["high"]).To(Equal(30))
+			Expect(concurrency["normal"]).To(Equal(20))
+		})
+	})
+})
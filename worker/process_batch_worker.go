@@ -23,16 +23,15 @@
 package worker
 
 import (
-	"encoding/json"
 	"fmt"
 	goworkers2 "github.com/digitalocean/go-workers2"
 	"math/rand"
-	"strings"
 	"time"
 
 	uuid "github.com/satori/go.uuid"
 	"github.com/topfreegames/marathon/email"
 	"github.com/topfreegames/marathon/log"
+	"github.com/topfreegames/marathon/messages"
 	"github.com/topfreegames/marathon/model"
 	"github.com/uber-go/zap"
 )
@@ -81,16 +80,16 @@ func (b *ProcessBatchWorker) incrFailedBatches(j *model.Job, appName string) {
 	}
 }
 
-func (b *ProcessBatchWorker) sendToKafka(service, topic string, msg, messageMetadata map[string]interface{}, pushMetadata map[string]interface{}, deviceToken string, expiresAt int64, templateName string) error {
+func (b *ProcessBatchWorker) sendToKafka(service, topic string, msg, messageMetadata map[string]interface{}, pushMetadata map[string]interface{}, deviceToken string, expiresAt int64, templateName string, options *messages.PushOptions) error {
 	pushExpiry := expiresAt / 1000000000 // convert from nanoseconds to seconds
 	switch service {
 	case "apns":
-		err := b.Workers.Kafka.SendAPNSPush(topic, deviceToken, msg, messageMetadata, pushMetadata, pushExpiry, templateName)
+		err := b.Workers.Kafka.SendAPNSPush(topic, deviceToken, msg, messageMetadata, pushMetadata, pushExpiry, templateName, options)
 		if err != nil {
 			return err
 		}
 	case "gcm":
-		err := b.Workers.Kafka.SendGCMPush(topic, deviceToken, msg, messageMetadata, pushMetadata, pushExpiry, templateName)
+		err := b.Workers.Kafka.SendGCMPush(topic, deviceToken, msg, messageMetadata, pushMetadata, pushExpiry, templateName, options)
 		if err != nil {
 			return err
 		}
@@ -146,12 +145,13 @@ func (b *ProcessBatchWorker) moveJobToPausedQueue(job *model.Job, message *gowor
 	}
 }
 
-func (b *ProcessBatchWorker) checkErrWithReEnqueue(parsed *BatchWorkerMessage, l zap.Logger, err error) {
+func (b *ProcessBatchWorker) checkErrWithReEnqueue(parsed *BatchWorkerMessage, priority string, l zap.Logger, err error) {
 	if err != nil {
 		at := time.Now().Add(time.Duration(rand.Intn(100)) * time.Second).UnixNano()
 		b.Workers.ScheduleProcessBatchJob(
 			parsed.JobID.String(),
 			parsed.AppName,
+			priority,
 			&parsed.Users,
 			at,
 		)
@@ -173,9 +173,11 @@ func (b *ProcessBatchWorker) Process(message *goworkers2.Msg) error {
 	parsed, err := ParseProcessBatchWorkerMessageArray(arr)
 	checkErr(l, err)
 	log.D(l, "Parsed message info successfully.")
+	queue, _ := message.Get("queue").String()
+	priority, _ := QueuePriority(nameProcessBatchWorker, queue)
 
 	job, err := b.Workers.GetJob(parsed.JobID)
-	b.checkErrWithReEnqueue(parsed, l, err)
+	b.checkErrWithReEnqueue(parsed, priority, l, err)
 
 	l = l.With(
 		zap.String("jobID", job.ID.String()),
@@ -211,11 +213,23 @@ func (b *ProcessBatchWorker) Process(message *goworkers2.Msg) error {
 		log.D(l, "valid")
 	}
 
+	slot, acquired, err := b.Workers.AcquireAppSlot("processBatch", queue, parsed.AppName)
+	b.checkErrWithReEnqueue(parsed, priority, l, err)
+	if !acquired {
+		log.I(l, "app is sending too many batches, batch re-enqueued")
+		at := time.Now().Add(b.Workers.Config.GetDuration("workers.fairness.retryDelay")).UnixNano()
+		_, err = b.Workers.ScheduleProcessBatchJob(job.ID.String(), parsed.AppName, priority, &parsed.Users, at)
+		b.checkErr(job, err)
+		b.Workers.Statsd.Incr(ProcessBatchWorkerCompleted, job.Labels(), 1)
+		return nil
+	}
+	defer slot.Release()
+
 	templatesByNameAndLocale, err := job.GetJobTemplatesByNameAndLocale(b.Workers.MarathonDB)
 	if err != nil {
 		b.incrFailedBatches(job, parsed.AppName)
 	}
-	b.checkErrWithReEnqueue(parsed, l, err)
+	b.checkErrWithReEnqueue(parsed, priority, l, err)
 	log.D(l, "Retrieved templatesByNameAndLocale successfully.", func(cm log.CM) {
 		cm.Write(zap.Object("templatesByNameAndLocale", templatesByNameAndLocale))
 	})
@@ -225,35 +239,50 @@ func (b *ProcessBatchWorker) Process(message *goworkers2.Msg) error {
 	log.D(l, "Built topic name successfully.", func(cm log.CM) {
 		cm.Write(zap.String("topic", topic))
 	})
-	for _, user := range parsed.Users {
-		templateName := job.TemplateName
-		templateNames := strings.Split(job.TemplateName, ",")
+	localeStats := map[string]int{}
+	idsByVariant := map[string][]string{}
+	treatedIDs := []string{}
+	cappedUsers := 0
+	variantFeedbacks := map[string]map[string]int{}
+	skippedUsers := 0
+	quietHours := job.GetQuietHours()
+	throttle := NewThrottle(b.Workers.RedisClient, job)
+	deferred := map[int64][]User{}
+	var throttled []User
+	var throttledAt int64
+	now := time.Now()
+	for i, user := range parsed.Users {
+		if at, ok := QuietHoursEnd(quietHours, &user, now); ok {
+			if job.ExpiresAt == 0 || at < job.ExpiresAt {
+				deferred[at] = append(deferred[at], user)
+			} else {
+				log.D(l, "user quiet hours end after the job expires", func(cm log.CM) {
+					cm.Write(zap.String("userId", user.UserID))
+				})
+			}
+			skippedUsers++
+			continue
+		}
 
-		if templateNames != nil && len(templateNames) > 1 {
-			templateName = RandomElementFromSlice(templateNames)
+		templateName := SelectVariant(job, user.UserID)
+		if job.HasVariants() {
 			log.D(l, "selected template", func(cm log.CM) {
 				cm.Write(zap.Object("name", templateName))
 			})
 		}
 
-		templatesByLocale := templatesByNameAndLocale[templateName]
-		var template model.Template
-		if val, ok := templatesByLocale[strings.ToLower(user.Locale)]; ok {
-			template = val
-		} else if val, ok := templatesByLocale["en"]; ok {
-			template = val
-		} else {
-			b.incrFailedBatches(job, parsed.AppName)
-			b.checkErr(job, fmt.Errorf("there is no template for the given locale or 'en'"))
+		template, level, ok := SelectTemplate(templatesByNameAndLocale[templateName], job.App.LocaleChain(user.Locale))
+		if !ok {
+			log.D(l, "no template for the user locale", func(cm log.CM) {
+				cm.Write(zap.String("locale", user.Locale))
+			})
+			localeStats[LocaleStatsMissing]++
+			skippedUsers++
+			continue
 		}
+		localeStats[LocaleStatsFallback(level)]++
 
-		msgStr, msgErr := BuildMessageFromTemplate(template, job.Context)
-		if msgErr != nil {
-			b.incrFailedBatches(job, parsed.AppName)
-		}
-		b.checkErr(job, msgErr)
-		var msg map[string]interface{}
-		err = json.Unmarshal([]byte(msgStr), &msg)
+		msg, err := RenderTemplate(template, job.Context, &user)
 		if err != nil {
 			b.incrFailedBatches(job, parsed.AppName)
 		}
@@ -267,6 +296,38 @@ func (b *ProcessBatchWorker) Process(message *goworkers2.Msg) error {
 			"muid":         uuid.NewV4().String(),
 		}
 
+		caps := job.App.FrequencyCapsFor(template.Category)
+		allowed, err := ReserveFrequencyCaps(b.Workers.RedisClient, &job.App, caps, user.UserID, pushMetadata["muid"].(string))
+		b.checkErr(job, err)
+		if !allowed {
+			log.D(l, "user reached a frequency cap", func(cm log.CM) {
+				cm.Write(zap.String("userId", user.UserID))
+			})
+			cappedUsers++
+			skippedUsers++
+			continue
+		}
+		sendNow, wait, err := throttle.Take(len(parsed.Users) - i)
+		if err != nil || !sendNow {
+			ReleaseFrequencyCaps(b.Workers.RedisClient, &job.App, caps, user.UserID, pushMetadata["muid"].(string))
+		}
+		b.checkErr(job, err)
+		if !sendNow {
+			// the rest of the batch is sent when the bucket refills instead of holding the worker
+			localeStats[LocaleStatsFallback(level)]--
+			throttled = parsed.Users[i:]
+			throttledAt = time.Now().Add(wait).UnixNano()
+			skippedUsers += len(throttled)
+			break
+		}
+		if job.HasVariants() {
+			pushMetadata["variant"] = templateName
+			idsByVariant[templateName] = append(idsByVariant[templateName], user.UserID)
+		}
+		if job.ControlGroup > 0 {
+			treatedIDs = append(treatedIDs, user.UserID)
+		}
+
 		dryRun := false
 		if val, ok := job.Metadata["dryRun"]; ok {
 			if dryRun, ok = val.(bool); ok {
@@ -274,9 +335,10 @@ func (b *ProcessBatchWorker) Process(message *goworkers2.Msg) error {
 			}
 		}
 
-		err = b.sendToKafka(job.Service, topic, msg, job.Metadata, pushMetadata, user.Token, job.ExpiresAt, templateName)
+		err = b.sendToKafka(job.Service, topic, msg, job.Metadata, pushMetadata, user.Token, job.ExpiresAt, templateName, job.GetPushOptions(&template))
 		if err != nil {
 			batchErrorCounter = batchErrorCounter + 1
+			ReleaseFrequencyCaps(b.Workers.RedisClient, &job.App, caps, user.UserID, pushMetadata["muid"].(string))
 			log.E(l, "Failed to send message to Kafka.", func(cm log.CM) {
 				cm.Write(
 					zap.String("service", job.Service),
@@ -288,13 +350,34 @@ func (b *ProcessBatchWorker) Process(message *goworkers2.Msg) error {
 					zap.Error(err),
 				)
 			})
+		} else if job.HasVariants() {
+			if variantFeedbacks[templateName] == nil {
+				variantFeedbacks[templateName] = map[string]int{}
+			}
+			variantFeedbacks[templateName][model.VariantSent]++
 		}
 	}
 	log.D(l, "Sent push to pusher for batch users.")
+	err = IncrLocaleStats(b.Workers.MarathonDB, job.ID, localeStats)
+	b.checkErr(job, err)
+	if len(idsByVariant) > 0 {
+		b.Workers.SendVariantsToRedis(job, idsByVariant)
+	}
+	if len(treatedIDs) > 0 {
+		b.Workers.SendTreatedGroupToRedis(job, treatedIDs)
+	}
+	err = IncrVariantFeedbacks(b.Workers.MarathonDB, job, variantFeedbacks)
+	b.checkErr(job, err)
+	err = IncrCappedUsers(b.Workers.MarathonDB, job, cappedUsers)
+	b.checkErr(job, err)
+	err = ScheduleDeferredUsers(b.Workers, job, parsed.AppName, deferred)
+	b.checkErr(job, err)
+	err = ScheduleThrottledUsers(b.Workers, job, parsed.AppName, throttled, throttledAt)
+	b.checkErr(job, err)
 	err = b.updateJobBatchesInfo(parsed.JobID)
 	b.checkErr(job, err)
 	log.D(l, "Updated job batches info successfully.")
-	err = b.updateJobUsersInfo(parsed.JobID, len(parsed.Users)-batchErrorCounter)
+	err = b.updateJobUsersInfo(parsed.JobID, len(parsed.Users)-batchErrorCounter-skippedUsers)
 	b.checkErr(job, err)
 	log.D(l, "Updated job users info successfully.")
 	if float64(batchErrorCounter)/float64(len(parsed.Users)) > b.Workers.Config.GetFloat64("workers.processBatch.maxUserFailureInBatch") {
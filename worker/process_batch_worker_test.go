@@ -263,6 +263,49 @@ var _ = Describe("ProcessBatch Worker", func() {
 			}
 		})
 
+		It("should put the variant in push metadata and count the pushes sent by variant", func() {
+			appName := strings.Split(app.BundleID, ".")[2]
+
+			compressedUsers, err := worker.CompressUsers(&users)
+			Expect(err).NotTo(HaveOccurred())
+			messageObj := []interface{}{
+				jobWithManyTemplates.ID,
+				appName,
+				compressedUsers,
+			}
+
+			msgB, err := json.Marshal(map[string][]interface{}{
+				"args": messageObj,
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			message, err := goworkers2.NewMsg(string(msgB))
+			Expect(err).NotTo(HaveOccurred())
+
+			processBatchWorker.Process(message)
+
+			sent := map[string]int{}
+			for idx := range users {
+				var apnsMessage messages.APNSMessage
+				err = json.Unmarshal([]byte(mockKafkaProducer.APNSMessages[idx]), &apnsMessage)
+				Expect(err).NotTo(HaveOccurred())
+				variant := worker.SelectVariant(jobWithManyTemplates, users[idx].UserID)
+				Expect(apnsMessage.Metadata["variant"]).To(Equal(variant))
+				Expect(apnsMessage.Metadata["templateName"]).To(Equal(variant))
+				sent[variant]++
+			}
+
+			dbJob := &model.Job{}
+			err = w.MarathonDB.Model(dbJob).Where("id = ?", jobWithManyTemplates.ID).Select()
+			Expect(err).NotTo(HaveOccurred())
+			for variant, count := range sent {
+				Expect(dbJob.VariantFeedbacks[variant][model.VariantSent]).To(Equal(count))
+				ids, err := w.RedisClient.LRange(worker.GetVariantRedisKey(jobWithManyTemplates, variant), 0, -1).Result()
+				Expect(err).NotTo(HaveOccurred())
+				Expect(ids).To(HaveLen(count))
+			}
+		})
+
 		It("should set job completedAt if last batch and schedule job_completed job", func() {
 			_, err := w.MarathonDB.Model(&model.Job{}).Set("completed_batches = 0").Set("total_batches = 1").Where("id = ?", job.ID).Update()
 			Expect(err).NotTo(HaveOccurred())
@@ -404,6 +447,121 @@ var _ = Describe("ProcessBatch Worker", func() {
 			Expect(dbJob.CompletedTokens).To(Equal(len(users)))
 		})
 
+		It("should defer the users in quiet hours to the end of the quiet window", func() {
+			now := time.Now().UTC()
+			job.QuietHours = &model.QuietHours{
+				Start: now.Add(-time.Hour).Format("15:04"),
+				End:   now.Add(30 * time.Minute).Format("15:04"),
+			}
+			_, err := w.MarathonDB.Model(job).Column("quiet_hours").Update()
+			Expect(err).NotTo(HaveOccurred())
+			_, err = w.MarathonDB.Model(&model.Job{}).Set("completed_batches = 0").Set("total_batches = 1").Where("id = ?", job.ID).Update()
+			Expect(err).NotTo(HaveOccurred())
+
+			users[0].Tz = "+0000"
+			appName := strings.Split(app.BundleID, ".")[2]
+			compressedUsers, err := worker.CompressUsers(&users)
+			Expect(err).NotTo(HaveOccurred())
+			messageObj := []interface{}{
+				job.ID,
+				appName,
+				compressedUsers,
+			}
+			msgB, err := json.Marshal(map[string][]interface{}{
+				"args": messageObj,
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			message, err := goworkers2.NewMsg(string(msgB))
+			Expect(err).NotTo(HaveOccurred())
+
+			processBatchWorker.Process(message)
+			Expect(mockKafkaProducer.APNSMessages).To(HaveLen(1))
+
+			dbJob := model.Job{
+				ID: job.ID,
+			}
+			err = w.MarathonDB.Select(&dbJob)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(dbJob.DeferredUsers).To(Equal(1))
+			Expect(dbJob.CompletedTokens).To(Equal(1))
+			Expect(dbJob.TotalBatches).To(Equal(2))
+			Expect(dbJob.CompletedBatches).To(Equal(1))
+			Expect(dbJob.CompletedAt).To(BeZero())
+
+			res, err := w.RedisClient.ZCard("schedule").Result()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(res).To(BeEquivalentTo(1))
+			var data workers.EnqueueData
+			jobs, err := w.RedisClient.ZRange("schedule", 0, -1).Result()
+			bytes, err := RedisReplyToBytes(jobs[0], err)
+			Expect(err).NotTo(HaveOccurred())
+			json.Unmarshal(bytes, &data)
+			at := time.Unix(0, int64(data.At*workers.NanoSecondPrecision))
+			Expect(at.Unix()).To(BeNumerically("~", now.Add(30*time.Minute).Unix(), 60))
+			Expect(data.Queue).To(Equal("process_batch_worker"))
+
+			parsed, err := worker.ParseProcessBatchWorkerMessageArray(data.Args.([]interface{}))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(parsed.Users).To(HaveLen(1))
+			Expect(parsed.Users[0].UserID).To(Equal(users[0].UserID))
+		})
+
+		It("should schedule the rest of the batch when the job reaches its push rate", func() {
+			job.MaxPushesPerSecond = 1
+			_, err := w.MarathonDB.Model(job).Column("max_pushes_per_second").Update()
+			Expect(err).NotTo(HaveOccurred())
+			_, err = w.MarathonDB.Model(&model.Job{}).Set("completed_batches = 0").Set("total_batches = 1").Where("id = ?", job.ID).Update()
+			Expect(err).NotTo(HaveOccurred())
+
+			appName := strings.Split(app.BundleID, ".")[2]
+			compressedUsers, err := worker.CompressUsers(&users)
+			Expect(err).NotTo(HaveOccurred())
+			messageObj := []interface{}{
+				job.ID,
+				appName,
+				compressedUsers,
+			}
+			msgB, err := json.Marshal(map[string][]interface{}{
+				"args": messageObj,
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			message, err := goworkers2.NewMsg(string(msgB))
+			Expect(err).NotTo(HaveOccurred())
+
+			start := time.Now()
+			processBatchWorker.Process(message)
+			Expect(time.Since(start)).To(BeNumerically("<", 500*time.Millisecond))
+			Expect(mockKafkaProducer.APNSMessages).To(HaveLen(1))
+
+			dbJob := model.Job{
+				ID: job.ID,
+			}
+			err = w.MarathonDB.Select(&dbJob)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(dbJob.CompletedTokens).To(Equal(1))
+			Expect(dbJob.TotalBatches).To(Equal(2))
+			Expect(dbJob.CompletedBatches).To(Equal(1))
+			Expect(dbJob.CompletedAt).To(BeZero())
+
+			var data workers.EnqueueData
+			jobs, err := w.RedisClient.ZRange("schedule", 0, -1).Result()
+			Expect(jobs).To(HaveLen(1))
+			bytes, err := RedisReplyToBytes(jobs[0], err)
+			Expect(err).NotTo(HaveOccurred())
+			json.Unmarshal(bytes, &data)
+			at := time.Unix(0, int64(data.At*workers.NanoSecondPrecision))
+			Expect(at).To(BeTemporally(">", start))
+			Expect(at).To(BeTemporally("<", start.Add(3*time.Second)))
+			Expect(data.Queue).To(Equal("process_batch_worker"))
+
+			parsed, err := worker.ParseProcessBatchWorkerMessageArray(data.Args.([]interface{}))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(parsed.Users).To(HaveLen(1))
+			Expect(parsed.Users[0].UserID).To(Equal(users[1].UserID))
+		})
+
 		It("should not process batch if job is expired", func() {
 			_, err := w.MarathonDB.Model(&model.Job{}).Set("completed_batches = 0").Set("expires_at = ?", time.Now().UnixNano()-50000).Where("id = ?", job.ID).Update()
 			appName := strings.Split(app.BundleID, ".")[2]
@@ -506,6 +664,83 @@ var _ = Describe("ProcessBatch Worker", func() {
 			}
 		})
 
+		It("should use the locale fallback chain and count users by fallback level", func() {
+			_, err := w.MarathonDB.Model(app).Set("locale_fallbacks = ?", map[string][]string{"es": {"fr"}}).Where("id = ?", app.ID).Update()
+			Expect(err).NotTo(HaveOccurred())
+
+			locales := []string{"fr", "pt_BR", "es", "de"}
+			users = make([]worker.User, len(locales))
+			for index, locale := range locales {
+				users[index] = worker.User{
+					UserID: uuid.NewV4().String(),
+					Token:  strings.Replace(uuid.NewV4().String(), "-", "", -1),
+					Locale: locale,
+				}
+			}
+			appName := strings.Split(app.BundleID, ".")[2]
+			compressedUsers, err := worker.CompressUsers(&users)
+			Expect(err).NotTo(HaveOccurred())
+			msgB, err := json.Marshal(map[string][]interface{}{
+				"args": []interface{}{job.ID, appName, compressedUsers},
+			})
+			Expect(err).NotTo(HaveOccurred())
+			message, err := goworkers2.NewMsg(string(msgB))
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(func() { processBatchWorker.Process(message) }).ShouldNot(Panic())
+
+			alerts := []string{
+				"Everyone a aimé ta ville!",
+				"Everyone curtiram sua vila!",
+				"Everyone a aimé ta ville!",
+				"Everyone just liked your village!",
+			}
+			Expect(mockKafkaProducer.APNSMessages).To(HaveLen(len(alerts)))
+			for idx, alert := range alerts {
+				var apnsMessage messages.APNSMessage
+				err = json.Unmarshal([]byte(mockKafkaProducer.APNSMessages[idx]), &apnsMessage)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(apnsMessage.Payload.Aps["alert"]).To(Equal(alert))
+			}
+
+			dbJob := &model.Job{}
+			err = w.MarathonDB.Model(dbJob).Where("id = ?", job.ID).Select()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(dbJob.LocaleStats).To(Equal(map[string]int{
+				"exact":      1,
+				"fallback_1": 3,
+			}))
+		})
+
+		It("should skip users without a template in their locale chain", func() {
+			ptTemplate := CreateTestTemplate(w.MarathonDB, app.ID, map[string]interface{}{
+				"locale": "pt",
+			})
+			ptJob := CreateTestJob(w.MarathonDB, app.ID, ptTemplate.Name)
+			users[1].Locale = "pt-BR"
+			appName := strings.Split(app.BundleID, ".")[2]
+			compressedUsers, err := worker.CompressUsers(&users)
+			Expect(err).NotTo(HaveOccurred())
+			msgB, err := json.Marshal(map[string][]interface{}{
+				"args": []interface{}{ptJob.ID, appName, compressedUsers},
+			})
+			Expect(err).NotTo(HaveOccurred())
+			message, err := goworkers2.NewMsg(string(msgB))
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(func() { processBatchWorker.Process(message) }).ShouldNot(Panic())
+			Expect(mockKafkaProducer.APNSMessages).To(HaveLen(1))
+
+			dbJob := &model.Job{}
+			err = w.MarathonDB.Model(dbJob).Where("id = ?", ptJob.ID).Select()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(dbJob.CompletedTokens).To(Equal(1))
+			Expect(dbJob.LocaleStats).To(Equal(map[string]int{
+				"fallback_1": 1,
+				"missing":    1,
+			}))
+		})
+
 		It("should process the message and put the right pushMetadata on it if apns push", func() {
 			userID := uuid.NewV4().String()
 			token := strings.Replace(uuid.NewV4().String(), "-", "", -1)
@@ -598,6 +833,42 @@ var _ = Describe("ProcessBatch Worker", func() {
 			}
 		})
 
+		It("should send the template push options with the ones of the job replaced", func() {
+			templateOptions := &messages.PushOptions{ThreadID: "likes", CollapseKey: "village", InterruptionLevel: "passive"}
+			for _, table := range []string{"templates", "template_versions"} {
+				_, err := w.MarathonDB.Exec(fmt.Sprintf("UPDATE %s SET push_options = ? WHERE app_id = ?", table), templateOptions, app.ID)
+				Expect(err).NotTo(HaveOccurred())
+			}
+			jobOptions := &messages.PushOptions{InterruptionLevel: "time-sensitive"}
+			_, err := w.MarathonDB.Model(&model.Job{}).Set("push_options = ?", jobOptions).Where("id = ?", job.ID).Update()
+			Expect(err).NotTo(HaveOccurred())
+
+			user := worker.User{
+				UserID: uuid.NewV4().String(),
+				Token:  strings.Replace(uuid.NewV4().String(), "-", "", -1),
+				Locale: "pt",
+			}
+			appName := strings.Split(app.BundleID, ".")[2]
+			compressedUsers, err := worker.CompressUsers(&[]worker.User{user})
+			Expect(err).NotTo(HaveOccurred())
+			msgB, err := json.Marshal(map[string][]interface{}{
+				"args": []interface{}{job.ID, appName, compressedUsers},
+			})
+			Expect(err).NotTo(HaveOccurred())
+			message, err := goworkers2.NewMsg(string(msgB))
+			Expect(err).NotTo(HaveOccurred())
+
+			processBatchWorker.Process(message)
+
+			Expect(mockKafkaProducer.APNSMessages).To(HaveLen(1))
+			var apnsMessage messages.APNSMessage
+			err = json.Unmarshal([]byte(mockKafkaProducer.APNSMessages[0]), &apnsMessage)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(apnsMessage.CollapseID).To(Equal("village"))
+			Expect(apnsMessage.Payload.Aps["thread-id"]).To(Equal("likes"))
+			Expect(apnsMessage.Payload.Aps["interruption-level"]).To(Equal("time-sensitive"))
+		})
+
 		It("should increment failedJobs", func() {
 			// unexistent template
 			w.MarathonDB.Exec("DELETE FROM templates;")
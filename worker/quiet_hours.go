@@ -0,0 +1,74 @@
+/*
+ * Copyright (c) 2026 TFG Co <backend@tfgco.com>
+ * Author: TFG Co <backend@tfgco.com>
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy of
+ * this software and associated documentation files (the "Software"), to deal in
+ * the Software without restriction, including without limitation the rights to
+ * use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+ * the Software, and to permit persons to whom the Software is furnished to do so,
+ * subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+ * FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+ * COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+ * IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+ * CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+ */
+
+package worker
+
+import (
+	"time"
+
+	"github.com/topfreegames/marathon/model"
+)
+
+// QuietHoursEnd returns when the quiet hours of the user end, in nanoseconds since epoch, it
+// returns false if the user is not in quiet hours or has no valid tz
+func QuietHoursEnd(quietHours *model.QuietHours, user *User, now time.Time) (int64, bool) {
+	if quietHours == nil {
+		return 0, false
+	}
+	location, ok := UserLocation(user.Tz)
+	if !ok {
+		return 0, false
+	}
+	until, ok := quietHours.Until(now.In(location))
+	if !ok {
+		return 0, false
+	}
+	return until.UnixNano(), true
+}
+
+// ScheduleDeferredUsers schedules a process batch job for the users of each end of quiet hours
+// and counts them in the job, the scheduled batches are added to the job total batches so it
+// only completes after they are processed
+func ScheduleDeferredUsers(w *Worker, job *model.Job, appName string, deferred map[int64][]User) error {
+	if len(deferred) == 0 {
+		return nil
+	}
+	deferredUsers := 0
+	for _, users := range deferred {
+		deferredUsers += len(users)
+	}
+	_, err := w.MarathonDB.Exec(
+		"UPDATE jobs SET total_batches = coalesce(total_batches, 0) + ?, deferred_users = deferred_users + ? WHERE id = ?",
+		len(deferred), deferredUsers, job.ID,
+	)
+	if err != nil {
+		return err
+	}
+	for at, users := range deferred {
+		users := users
+		_, err = w.ScheduleProcessBatchJob(job.ID.String(), appName, job.Priority, &users, at)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
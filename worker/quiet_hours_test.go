@@ -0,0 +1,96 @@
+/*
+ * Copyright (c) 2026 TFG Co <backend@tfgco.com>
+ * Author: TFG Co <backend@tfgco.com>
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy of
+ * this software and associated documentation files (the "Software"), to deal in
+ * the Software without restriction, including without limitation the rights to
+ * use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+ * the Software, and to permit persons to whom the Software is furnished to do so,
+ * subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+ * FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+ * COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+ * IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+ * CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+ */
+
+package worker_test
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/topfreegames/marathon/model"
+	"github.com/topfreegames/marathon/worker"
+)
+
+var _ = Describe("Quiet hours", func() {
+	night := &model.QuietHours{Start: "22:00", End: "08:00"}
+	lunch := &model.QuietHours{Start: "12:00", End: "14:00"}
+
+	Describe("Until", func() {
+		It("should return the end of a window in the same day", func() {
+			until, ok := lunch.Until(time.Date(2026, 3, 10, 12, 30, 0, 0, time.UTC))
+			Expect(ok).To(BeTrue())
+			Expect(until).To(Equal(time.Date(2026, 3, 10, 14, 0, 0, 0, time.UTC)))
+
+			_, ok = lunch.Until(time.Date(2026, 3, 10, 14, 0, 0, 0, time.UTC))
+			Expect(ok).To(BeFalse())
+			_, ok = lunch.Until(time.Date(2026, 3, 10, 11, 59, 0, 0, time.UTC))
+			Expect(ok).To(BeFalse())
+		})
+
+		It("should return the end of a window that ends in the next day", func() {
+			until, ok := night.Until(time.Date(2026, 3, 10, 23, 0, 0, 0, time.UTC))
+			Expect(ok).To(BeTrue())
+			Expect(until).To(Equal(time.Date(2026, 3, 11, 8, 0, 0, 0, time.UTC)))
+
+			until, ok = night.Until(time.Date(2026, 3, 31, 3, 0, 0, 0, time.UTC))
+			Expect(ok).To(BeTrue())
+			Expect(until).To(Equal(time.Date(2026, 3, 31, 8, 0, 0, 0, time.UTC)))
+
+			_, ok = night.Until(time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC))
+			Expect(ok).To(BeFalse())
+		})
+
+		It("should never be in an empty window", func() {
+			empty := &model.QuietHours{Start: "10:00", End: "10:00"}
+			_, ok := empty.Until(time.Date(2026, 3, 10, 10, 0, 0, 0, time.UTC))
+			Expect(ok).To(BeFalse())
+		})
+	})
+
+	Describe("Quiet hours end", func() {
+		now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+
+		It("should use the user tz", func() {
+			at, ok := worker.QuietHoursEnd(night, &worker.User{Tz: "-0500"}, now)
+			Expect(ok).To(BeTrue())
+			Expect(at).To(Equal(time.Date(2026, 3, 10, 13, 0, 0, 0, time.UTC).UnixNano()))
+
+			_, ok = worker.QuietHoursEnd(night, &worker.User{Tz: "+0000"}, now)
+			Expect(ok).To(BeFalse())
+		})
+
+		It("should not defer users without quiet hours or a valid tz", func() {
+			_, ok := worker.QuietHoursEnd(nil, &worker.User{Tz: "-0500"}, now)
+			Expect(ok).To(BeFalse())
+			_, ok = worker.QuietHoursEnd(night, &worker.User{}, now)
+			Expect(ok).To(BeFalse())
+		})
+
+		It("should prefer the job quiet hours to the app ones", func() {
+			job := &model.Job{App: model.App{QuietHours: night}}
+			Expect(job.GetQuietHours()).To(Equal(night))
+			job.QuietHours = lunch
+			Expect(job.GetQuietHours()).To(Equal(lunch))
+		})
+	})
+})
@@ -89,7 +89,7 @@ func (b *ResumeJobWorker) Process(message *goworkers2.Msg) error {
 		b.checkErr(job, err)
 		parsed, err := ParseProcessBatchWorkerMessageArray(pausedJobArr)
 		b.checkErr(job, err)
-		_, err = b.Workers.CreateProcessBatchJob(parsed.JobID.String(), parsed.AppName, &parsed.Users)
+		_, err = b.Workers.CreateProcessBatchJob(parsed.JobID.String(), parsed.AppName, job.Priority, &parsed.Users)
 		b.checkErr(job, err)
 	}
 
@@ -0,0 +1,230 @@
+/*
+ * Copyright (c) 2026 TFG Co <backend@tfgco.com>
+ * Author: TFG Co <backend@tfgco.com>
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy of
+ * this software and associated documentation files (the "Software"), to deal in
+ * the Software without restriction, including without limitation the rights to
+ * use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+ * the Software, and to permit persons to whom the Software is furnished to do so,
+ * subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+ * FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+ * COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+ * IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+ * CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+ */
+
+package worker
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/satori/go.uuid"
+	"github.com/topfreegames/marathon/log"
+	"github.com/topfreegames/marathon/model"
+	"github.com/uber-go/zap"
+	pg "gopkg.in/pg.v5"
+)
+
+const nameScheduler = "scheduler"
+
+// Scheduler creates the jobs of the recurring schedules when they are due
+type Scheduler struct {
+	Logger    zap.Logger
+	Workers   *Worker
+	Interval  time.Duration
+	Lookahead time.Duration
+	MaxDelay  time.Duration
+}
+
+// NewScheduler gets a new Scheduler
+func NewScheduler(workers *Worker) *Scheduler {
+	s := &Scheduler{
+		Logger:    workers.Logger.With(zap.String("worker", "Scheduler")),
+		Workers:   workers,
+		Interval:  workers.Config.GetDuration("workers.scheduler.interval"),
+		Lookahead: workers.Config.GetDuration("workers.scheduler.lookahead"),
+		MaxDelay:  workers.Config.GetDuration("workers.scheduler.maxDelay"),
+	}
+	s.Logger.Debug("Configured Scheduler successfully.")
+	return s
+}
+
+// Start runs the scheduler loop, it never returns
+func (s *Scheduler) Start() {
+	ticker := time.NewTicker(s.Interval)
+	defer ticker.Stop()
+	for {
+		err := s.Tick(time.Now())
+		if err != nil {
+			log.E(s.Logger, "Failed to run scheduler tick.", func(cm log.CM) {
+				cm.Write(zap.Error(err))
+			})
+		}
+		<-ticker.C
+	}
+}
+
+// Tick creates the jobs of every schedule with an occurrence before now plus the lookahead,
+// the created jobs are scheduled to start exactly at the occurrence time
+func (s *Scheduler) Tick(now time.Time) error {
+	var schedules []model.Schedule
+	err := s.Workers.MarathonDB.Model(&schedules).Column("schedule.*", "App").Where(
+		"schedule.paused = false AND schedule.next_run_at > 0 AND schedule.next_run_at <= ?",
+		now.Add(s.Lookahead).UnixNano(),
+	).Select()
+	if err != nil {
+		return err
+	}
+
+	for i := range schedules {
+		schedule := &schedules[i]
+		err = s.runSchedule(schedule, now)
+		if err != nil {
+			s.Workers.Statsd.Incr(SchedulerError, []string{fmt.Sprintf("game:%s", schedule.App.Name)}, 1)
+			log.E(s.Logger, "Failed to run schedule.", func(cm log.CM) {
+				cm.Write(
+					zap.String("scheduleID", schedule.ID.String()),
+					zap.Error(err),
+				)
+			})
+		}
+	}
+	return nil
+}
+
+func (s *Scheduler) runSchedule(schedule *model.Schedule, now time.Time) error {
+	l := s.Logger.With(
+		zap.String("scheduleID", schedule.ID.String()),
+		zap.String("operation", "runSchedule"),
+	)
+	occurrence := time.Unix(0, schedule.NextRunAt)
+	// occurrences missed for too long (e.g. workers were down) are skipped instead of
+	// sending an outdated push, the next run is then computed from now so every missed
+	// occurrence is skipped at once
+	stale := now.Sub(occurrence) > s.MaxDelay
+	from := occurrence
+	if stale {
+		from = now
+	}
+
+	totalRuns := schedule.TotalRuns
+	if !stale {
+		totalRuns++
+	}
+	next, err := (&model.Schedule{
+		CronExpression: schedule.CronExpression,
+		Timezone:       schedule.Timezone,
+		EndsAt:         schedule.EndsAt,
+		MaxRuns:        schedule.MaxRuns,
+		TotalRuns:      totalRuns,
+	}).NextRun(from)
+	if err != nil {
+		return err
+	}
+	var nextRunAt int64
+	if !next.IsZero() {
+		nextRunAt = next.UnixNano()
+	}
+
+	var job *model.Job
+	if !stale {
+		job = schedule.NewJob(occurrence)
+		err = job.PinTemplateVersions(s.Workers.MarathonDB)
+		if err != nil {
+			return err
+		}
+		if schedule.App.RequiresApproval {
+			// the job is only enqueued when another user approves it
+			job.Status = pendingApprovalJobStatus
+		}
+	}
+
+	// the claim and the job are written in the same transaction, so a failure
+	// inserting the job leaves the occurrence to be claimed again
+	tx, err := s.Workers.MarathonDB.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	// claiming the occurrence guarantees only one scheduler creates its job
+	query := tx.Model(schedule).
+		Set("next_run_at = ?", nextRunAt).
+		Set("total_runs = ?", totalRuns).
+		Set("updated_at = ?", now.UnixNano())
+	if !stale {
+		query = query.Set("last_run_at = ?", occurrence.UnixNano())
+	}
+	res, err := query.Where(
+		"id = ? AND next_run_at = ? AND paused = false",
+		schedule.ID,
+		schedule.NextRunAt,
+	).Update()
+	if err != nil {
+		return err
+	}
+	if res.RowsAffected() == 0 {
+		log.D(l, "occurrence already claimed")
+		return nil
+	}
+
+	if stale {
+		log.I(l, "skipped stale occurrence", func(cm log.CM) {
+			cm.Write(zap.Int64("occurrence", occurrence.UnixNano()))
+		})
+		return tx.Commit()
+	}
+
+	err = s.createJob(tx, schedule, job)
+	if err != nil {
+		return err
+	}
+	err = tx.Commit()
+	if err != nil {
+		return err
+	}
+	job.TagSuccess(s.Workers.MarathonDB, nameScheduler, fmt.Sprintf("created by schedule %s", schedule.ID.String()))
+
+	// the job is enqueued after the commit so the workers always find it
+	if job.Status != pendingApprovalJobStatus {
+		if len(job.CSVPath) > 0 {
+			_, err = s.Workers.ScheduleCSVSplitJob(job, job.StartsAt)
+		} else {
+			err = s.Workers.ScheduleDirectBatchesJob(job, job.StartsAt)
+		}
+		if err != nil {
+			job.TagError(s.Workers.MarathonDB, nameScheduler, err.Error())
+			return err
+		}
+	}
+	s.Workers.Statsd.Incr(SchedulerJobCreated, job.Labels(), 1)
+	log.I(l, "created scheduled job", func(cm log.CM) {
+		cm.Write(
+			zap.String("jobID", job.ID.String()),
+			zap.Int64("startsAt", job.StartsAt),
+		)
+	})
+	return nil
+}
+
+// createJob inserts the job and its group in tx
+func (s *Scheduler) createJob(tx *pg.Tx, schedule *model.Schedule, job *model.Job) error {
+	jobGroup := &model.JobGroup{
+		ID:    uuid.NewV4(),
+		AppID: schedule.AppID,
+	}
+	err := tx.Insert(jobGroup)
+	if err != nil {
+		return err
+	}
+	job.JobGroupID = jobGroup.ID
+	return tx.Insert(job)
+}
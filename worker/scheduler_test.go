@@ -0,0 +1,194 @@
+/*
+ * Copyright (c) 2026 TFG Co <backend@tfgco.com>
+ * Author: TFG Co <backend@tfgco.com>
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy of
+ * this software and associated documentation files (the "Software"), to deal in
+ * the Software without restriction, including without limitation the rights to
+ * use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+ * the Software, and to permit persons to whom the Software is furnished to do so,
+ * subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+ * FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+ * COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+ * IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+ * CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+ */
+
+package worker_test
+
+import (
+	"encoding/json"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/topfreegames/marathon/model"
+	. "github.com/topfreegames/marathon/testing"
+	"github.com/topfreegames/marathon/worker"
+	"github.com/uber-go/zap"
+)
+
+var _ = Describe("Scheduler", func() {
+	var scheduler *worker.Scheduler
+	var app *model.App
+	var template *model.Template
+
+	logger := zap.New(
+		zap.NewJSONEncoder(zap.NoTime()), // drop timestamps in tests
+		zap.FatalLevel,
+	)
+	w := worker.NewWorker(logger, GetConfPath())
+
+	BeforeEach(func() {
+		w.RedisClient.FlushAll()
+		w.MarathonDB.Exec("DELETE FROM apps;")
+		scheduler = worker.NewScheduler(w)
+
+		app = CreateTestApp(w.MarathonDB)
+		template = CreateTestTemplate(w.MarathonDB, app.ID)
+	})
+
+	Describe("Tick", func() {
+		It("should create a job for a due occurrence and advance the schedule", func() {
+			now := time.Now()
+			occurrence := now.Add(30 * time.Second).Truncate(time.Minute)
+			schedule := CreateTestSchedule(w.MarathonDB, app.ID, template.Name, map[string]interface{}{
+				"cronExpression": "* * * * *",
+				"nextRunAt":      occurrence.UnixNano(),
+				"csvPath":        "bucket/somecsv",
+				"filters":        map[string]interface{}{},
+			})
+
+			err := scheduler.Tick(now)
+			Expect(err).NotTo(HaveOccurred())
+
+			var jobs []model.Job
+			err = w.MarathonDB.Model(&jobs).Where("schedule_id = ?", schedule.ID).Select()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(jobs).To(HaveLen(1))
+			Expect(jobs[0].StartsAt).To(Equal(occurrence.UnixNano()))
+			Expect(jobs[0].TemplateName).To(Equal(template.Name))
+			Expect(jobs[0].CSVPath).To(Equal("bucket/somecsv"))
+
+			res, err := w.RedisClient.ZRange("schedule", 0, -1).Result()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(res).To(HaveLen(1))
+			var result map[string]interface{}
+			err = json.Unmarshal([]byte(res[0]), &result)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result["queue"]).To(Equal("csv_split_worker"))
+			Expect(result["args"]).To(Equal(jobs[0].ID.String()))
+
+			dbSchedule := &model.Schedule{ID: schedule.ID}
+			err = w.MarathonDB.Select(&dbSchedule)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(dbSchedule.TotalRuns).To(Equal(1))
+			Expect(dbSchedule.LastRunAt).To(Equal(occurrence.UnixNano()))
+			Expect(dbSchedule.NextRunAt).To(Equal(occurrence.Add(time.Minute).UnixNano()))
+		})
+
+		It("should create jobs pending approval without enqueueing them for approval apps", func() {
+			now := time.Now()
+			approvalApp := CreateTestApp(w.MarathonDB, map[string]interface{}{"requiresApproval": true})
+			approvalTemplate := CreateTestTemplate(w.MarathonDB, approvalApp.ID)
+			schedule := CreateTestSchedule(w.MarathonDB, approvalApp.ID, approvalTemplate.Name, map[string]interface{}{
+				"cronExpression": "0 10 * * *",
+				"nextRunAt":      now.UnixNano(),
+				"csvPath":        "bucket/somecsv",
+				"filters":        map[string]interface{}{},
+			})
+
+			Expect(scheduler.Tick(now)).To(Succeed())
+
+			var jobs []model.Job
+			err := w.MarathonDB.Model(&jobs).Where("schedule_id = ?", schedule.ID).Select()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(jobs).To(HaveLen(1))
+			Expect(jobs[0].Status).To(Equal("pending_approval"))
+
+			res, err := w.RedisClient.ZRange("schedule", 0, -1).Result()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(res).To(BeEmpty())
+			queued, err := w.RedisClient.LLen("queue:csv_split_worker").Result()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(queued).To(BeEquivalentTo(0))
+		})
+
+		It("should create the job only once when ticking twice", func() {
+			now := time.Now()
+			schedule := CreateTestSchedule(w.MarathonDB, app.ID, template.Name, map[string]interface{}{
+				"cronExpression": "0 10 * * *",
+				"nextRunAt":      now.UnixNano(),
+				"csvPath":        "bucket/somecsv",
+				"filters":        map[string]interface{}{},
+			})
+
+			Expect(scheduler.Tick(now)).To(Succeed())
+			Expect(scheduler.Tick(now)).To(Succeed())
+
+			count, err := w.MarathonDB.Model(&model.Job{}).Where("schedule_id = ?", schedule.ID).Count()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(count).To(Equal(1))
+		})
+
+		It("should not create jobs for paused schedules", func() {
+			now := time.Now()
+			schedule := CreateTestSchedule(w.MarathonDB, app.ID, template.Name, map[string]interface{}{
+				"nextRunAt": now.UnixNano(),
+				"paused":    true,
+			})
+
+			Expect(scheduler.Tick(now)).To(Succeed())
+
+			count, err := w.MarathonDB.Model(&model.Job{}).Where("schedule_id = ?", schedule.ID).Count()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(count).To(Equal(0))
+		})
+
+		It("should skip occurrences older than the max delay", func() {
+			now := time.Now()
+			schedule := CreateTestSchedule(w.MarathonDB, app.ID, template.Name, map[string]interface{}{
+				"cronExpression": "* * * * *",
+				"nextRunAt":      now.Add(-2 * scheduler.MaxDelay).UnixNano(),
+				"csvPath":        "bucket/somecsv",
+				"filters":        map[string]interface{}{},
+			})
+
+			Expect(scheduler.Tick(now)).To(Succeed())
+
+			count, err := w.MarathonDB.Model(&model.Job{}).Where("schedule_id = ?", schedule.ID).Count()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(count).To(Equal(0))
+
+			dbSchedule := &model.Schedule{ID: schedule.ID}
+			err = w.MarathonDB.Select(&dbSchedule)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(dbSchedule.TotalRuns).To(Equal(0))
+			Expect(dbSchedule.NextRunAt).To(BeNumerically(">", now.UnixNano()))
+		})
+
+		It("should finish the schedule after max runs", func() {
+			now := time.Now()
+			schedule := CreateTestSchedule(w.MarathonDB, app.ID, template.Name, map[string]interface{}{
+				"nextRunAt": now.UnixNano(),
+				"maxRuns":   1,
+				"csvPath":   "bucket/somecsv",
+				"filters":   map[string]interface{}{},
+			})
+
+			Expect(scheduler.Tick(now)).To(Succeed())
+
+			dbSchedule := &model.Schedule{ID: schedule.ID}
+			err := w.MarathonDB.Select(&dbSchedule)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(dbSchedule.TotalRuns).To(Equal(1))
+			Expect(dbSchedule.NextRunAt).To(BeEquivalentTo(0))
+		})
+	})
+})
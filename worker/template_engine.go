@@ -0,0 +1,391 @@
+/*
+ * Copyright (c) 2026 TFG Co <backend@tfgco.com>
+ * Author: TFG Co <backend@tfgco.com>
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy of
+ * this software and associated documentation files (the "Software"), to deal in
+ * the Software without restriction, including without limitation the rights to
+ * use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+ * the Software, and to permit persons to whom the Software is furnished to do so,
+ * subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+ * FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+ * COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+ * IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+ * CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+ */
+
+package worker
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/topfreegames/marathon/model"
+)
+
+// Template variables with the user prefix are replaced by the push db columns of each user
+const userVariablePrefix = "user."
+
+var builtinUserColumns = map[string]bool{
+	"user_id": true,
+	"locale":  true,
+	"region":  true,
+	"tz":      true,
+}
+
+// templateNode is a piece of a parsed template string
+type templateNode struct {
+	text     string
+	variable string
+	fallback string
+	plural   []string
+	ifBranch []*templateNode
+	elseNode []*templateNode
+	isIf     bool
+}
+
+// RenderTemplate renders every string of the template body, variables are looked up in the
+// user columns (user.<column>), then in the user csv columns, in the context and then in the
+// template defaults.
+// The body is rendered value by value, so variables cannot break its structure.
+// Supported tags are {{var}}, {{var|fallback}}, {{#if var}}...{{else}}...{{/if}} and
+// {{plural var "one" "other"}}, # in the plural forms is replaced by the value of var
+func RenderTemplate(template model.Template, context map[string]interface{}, user *User) (map[string]interface{}, error) {
+	vars := templateVariables{defaults: template.Defaults, context: context, user: user}
+	res, err := renderTemplateValue(template.Body, vars)
+	if err != nil {
+		return nil, err
+	}
+	return res.(map[string]interface{}), nil
+}
+
+// ValidateTemplateBody checks the syntax of every string of the template body
+func ValidateTemplateBody(body map[string]interface{}) error {
+	_, err := renderTemplateValue(body, templateVariables{})
+	return err
+}
+
+// TemplateUserColumns returns the push db columns used by the templates besides the ones
+// every user has, they must be fetched in User.Extra
+func TemplateUserColumns(templates ...model.Template) []string {
+	columns := map[string]bool{}
+	for _, template := range templates {
+		walkTemplateStrings(template.Body, func(str string) {
+			nodes, err := parseTemplateString(str)
+			if err != nil {
+				return
+			}
+			walkTemplateNodes(nodes, func(variable string) {
+				if !strings.HasPrefix(variable, userVariablePrefix) {
+					return
+				}
+				column := strings.TrimPrefix(variable, userVariablePrefix)
+				if !builtinUserColumns[column] && columnNameRegex.MatchString(column) {
+					columns[column] = true
+				}
+			})
+		})
+	}
+	res := []string{}
+	for column := range columns {
+		res = append(res, column)
+	}
+	sort.Strings(res)
+	return res
+}
+
+func flattenTemplates(templatesByNameAndLocale map[string]map[string]model.Template) []model.Template {
+	templates := []model.Template{}
+	for _, templatesByLocale := range templatesByNameAndLocale {
+		for _, template := range templatesByLocale {
+			templates = append(templates, template)
+		}
+	}
+	return templates
+}
+
+type templateVariables struct {
+	defaults map[string]interface{}
+	context  map[string]interface{}
+	user     *User
+}
+
+func (v templateVariables) get(name string) (interface{}, bool) {
+	if strings.HasPrefix(name, userVariablePrefix) {
+		if v.user == nil {
+			return nil, false
+		}
+		column := strings.TrimPrefix(name, userVariablePrefix)
+		switch column {
+		case "user_id":
+			return v.user.UserID, v.user.UserID != ""
+		case "locale":
+			return v.user.Locale, v.user.Locale != ""
+		case "region":
+			return v.user.Region, v.user.Region != ""
+		case "tz":
+			return v.user.Tz, v.user.Tz != ""
+		}
+		val, ok := v.user.Extra[column]
+		return val, ok && val != nil
+	}
+	if v.user != nil {
+		if val, ok := v.user.Context[name]; ok {
+			return val, true
+		}
+	}
+	if val, ok := v.context[name]; ok {
+		return val, true
+	}
+	val, ok := v.defaults[name]
+	return val, ok
+}
+
+func (v templateVariables) getString(name string) (string, bool) {
+	val, ok := v.get(name)
+	if !ok {
+		return "", false
+	}
+	switch value := val.(type) {
+	case string:
+		return value, true
+	case float64:
+		return strconv.FormatFloat(value, 'f', -1, 64), true
+	}
+	return fmt.Sprint(val), true
+}
+
+func (v templateVariables) isTrue(name string) bool {
+	val, ok := v.get(name)
+	if !ok {
+		return false
+	}
+	switch value := val.(type) {
+	case nil:
+		return false
+	case bool:
+		return value
+	case float64:
+		return value != 0
+	case string:
+		return value != "" && value != "0" && value != "false"
+	}
+	return true
+}
+
+func renderTemplateValue(value interface{}, vars templateVariables) (interface{}, error) {
+	switch v := value.(type) {
+	case string:
+		nodes, err := parseTemplateString(v)
+		if err != nil {
+			return nil, err
+		}
+		var b strings.Builder
+		renderTemplateNodes(&b, nodes, vars)
+		return b.String(), nil
+	case map[string]interface{}:
+		res := make(map[string]interface{}, len(v))
+		for key, val := range v {
+			renderedKey, err := renderTemplateValue(key, vars)
+			if err != nil {
+				return nil, err
+			}
+			renderedVal, err := renderTemplateValue(val, vars)
+			if err != nil {
+				return nil, err
+			}
+			res[renderedKey.(string)] = renderedVal
+		}
+		return res, nil
+	case []interface{}:
+		res := make([]interface{}, len(v))
+		for i, val := range v {
+			renderedVal, err := renderTemplateValue(val, vars)
+			if err != nil {
+				return nil, err
+			}
+			res[i] = renderedVal
+		}
+		return res, nil
+	}
+	return value, nil
+}
+
+func walkTemplateStrings(value interface{}, fn func(string)) {
+	switch v := value.(type) {
+	case string:
+		fn(v)
+	case map[string]interface{}:
+		for key, val := range v {
+			fn(key)
+			walkTemplateStrings(val, fn)
+		}
+	case []interface{}:
+		for _, val := range v {
+			walkTemplateStrings(val, fn)
+		}
+	}
+}
+
+func walkTemplateNodes(nodes []*templateNode, fn func(variable string)) {
+	for _, node := range nodes {
+		if node.variable != "" {
+			fn(node.variable)
+		}
+		walkTemplateNodes(node.ifBranch, fn)
+		walkTemplateNodes(node.elseNode, fn)
+	}
+}
+
+func renderTemplateNodes(b *strings.Builder, nodes []*templateNode, vars templateVariables) {
+	for _, node := range nodes {
+		switch {
+		case node.isIf:
+			if vars.isTrue(node.variable) {
+				renderTemplateNodes(b, node.ifBranch, vars)
+			} else {
+				renderTemplateNodes(b, node.elseNode, vars)
+			}
+		case node.plural != nil:
+			count, _ := vars.getString(node.variable)
+			form := node.plural[1]
+			if n, err := strconv.ParseFloat(count, 64); err == nil && n == 1 {
+				form = node.plural[0]
+			}
+			b.WriteString(strings.Replace(form, "#", count, -1))
+		case node.variable != "":
+			if val, ok := vars.getString(node.variable); ok && val != "" {
+				b.WriteString(val)
+			} else {
+				b.WriteString(node.fallback)
+			}
+		default:
+			b.WriteString(node.text)
+		}
+	}
+}
+
+// parseTemplateString parses the tags of a template string into nodes
+func parseTemplateString(str string) ([]*templateNode, error) {
+	root := []*templateNode{}
+	// stack of the open if nodes, the nodes are appended to the last one
+	stack := []*templateNode{}
+	inElse := []bool{}
+	appendNode := func(node *templateNode) {
+		if len(stack) == 0 {
+			root = append(root, node)
+			return
+		}
+		parent := stack[len(stack)-1]
+		if inElse[len(inElse)-1] {
+			parent.elseNode = append(parent.elseNode, node)
+		} else {
+			parent.ifBranch = append(parent.ifBranch, node)
+		}
+	}
+
+	for len(str) > 0 {
+		start := strings.Index(str, "{{")
+		if start < 0 {
+			appendNode(&templateNode{text: str})
+			break
+		}
+		if start > 0 {
+			appendNode(&templateNode{text: str[:start]})
+		}
+		end := strings.Index(str[start:], "}}")
+		if end < 0 {
+			return nil, fmt.Errorf("invalid template: unclosed tag in %q", str)
+		}
+		tag := strings.TrimSpace(str[start+2 : start+end])
+		str = str[start+end+2:]
+
+		switch {
+		case strings.HasPrefix(tag, "#if "):
+			variable := strings.TrimSpace(strings.TrimPrefix(tag, "#if "))
+			if variable == "" {
+				return nil, fmt.Errorf("invalid template: if without variable")
+			}
+			node := &templateNode{isIf: true, variable: variable}
+			appendNode(node)
+			stack = append(stack, node)
+			inElse = append(inElse, false)
+		case tag == "else":
+			if len(stack) == 0 || inElse[len(inElse)-1] {
+				return nil, fmt.Errorf("invalid template: else without if")
+			}
+			inElse[len(inElse)-1] = true
+		case tag == "/if":
+			if len(stack) == 0 {
+				return nil, fmt.Errorf("invalid template: /if without if")
+			}
+			stack = stack[:len(stack)-1]
+			inElse = inElse[:len(inElse)-1]
+		case strings.HasPrefix(tag, "plural "):
+			node, err := parsePluralTag(strings.TrimPrefix(tag, "plural "))
+			if err != nil {
+				return nil, err
+			}
+			appendNode(node)
+		case strings.HasPrefix(tag, "#") || strings.HasPrefix(tag, "/"):
+			return nil, fmt.Errorf("invalid template: unknown tag {{%s}}", tag)
+		default:
+			node := &templateNode{variable: tag}
+			if idx := strings.Index(tag, "|"); idx >= 0 {
+				node.variable = strings.TrimSpace(tag[:idx])
+				node.fallback = unquoteTemplateArg(strings.TrimSpace(tag[idx+1:]))
+			}
+			if node.variable == "" {
+				return nil, fmt.Errorf("invalid template: empty tag")
+			}
+			appendNode(node)
+		}
+	}
+
+	if len(stack) > 0 {
+		return nil, fmt.Errorf("invalid template: if %s is not closed", stack[len(stack)-1].variable)
+	}
+	return root, nil
+}
+
+// parsePluralTag parses the arguments of {{plural var "one" "other"}}
+func parsePluralTag(args string) (*templateNode, error) {
+	args = strings.TrimSpace(args)
+	idx := strings.IndexAny(args, " \t")
+	if idx < 0 {
+		return nil, fmt.Errorf("invalid template: plural needs a variable and two forms")
+	}
+	variable := args[:idx]
+	forms := []string{}
+	rest := strings.TrimSpace(args[idx:])
+	for len(rest) > 0 {
+		if rest[0] != '"' {
+			return nil, fmt.Errorf("invalid template: plural forms must be quoted")
+		}
+		end := strings.Index(rest[1:], "\"")
+		if end < 0 {
+			return nil, fmt.Errorf("invalid template: plural forms must be quoted")
+		}
+		forms = append(forms, rest[1:end+1])
+		rest = strings.TrimSpace(rest[end+2:])
+	}
+	if len(forms) != 2 {
+		return nil, fmt.Errorf("invalid template: plural needs a variable and two forms")
+	}
+	return &templateNode{variable: variable, plural: forms}, nil
+}
+
+func unquoteTemplateArg(arg string) string {
+	if len(arg) >= 2 && arg[0] == '"' && arg[len(arg)-1] == '"' {
+		return arg[1 : len(arg)-1]
+	}
+	return arg
+}
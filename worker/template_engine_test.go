@@ -0,0 +1,154 @@
+/*
+ * Copyright (c) 2026 TFG Co <backend@tfgco.com>
+ * Author: TFG Co <backend@tfgco.com>
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy of
+ * this software and associated documentation files (the "Software"), to deal in
+ * the Software without restriction, including without limitation the rights to
+ * use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+ * the Software, and to permit persons to whom the Software is furnished to do so,
+ * subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+ * FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+ * COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+ * IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+ * CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+ */
+
+package worker_test
+
+import (
+	"encoding/json"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/topfreegames/marathon/model"
+	"github.com/topfreegames/marathon/worker"
+)
+
+var _ = Describe("Template Engine", func() {
+	render := func(body string, context map[string]interface{}, user *worker.User) (map[string]interface{}, error) {
+		template := model.Template{
+			Defaults: map[string]interface{}{"name": "player"},
+		}
+		err := json.Unmarshal([]byte(body), &template.Body)
+		Expect(err).NotTo(HaveOccurred())
+		return worker.RenderTemplate(template, context, user)
+	}
+
+	Describe("Render template", func() {
+		It("should replace variables from the context and the defaults", func() {
+			res, err := render(`{"alert": "{{name}} won {{reward}}"}`, map[string]interface{}{"reward": "gold"}, nil)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(res["alert"]).To(Equal("player won gold"))
+		})
+
+		It("should use the fallback if the variable is missing or empty", func() {
+			res, err := render(`{"alert": "hi {{nick|friend}} {{title|\"sir\"}}"}`, map[string]interface{}{"title": ""}, nil)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(res["alert"]).To(Equal("hi friend sir"))
+		})
+
+		It("should render if and else branches", func() {
+			body := `{"alert": "{{#if vip}}welcome back{{else}}hello{{/if}}"}`
+			res, err := render(body, map[string]interface{}{"vip": true}, nil)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(res["alert"]).To(Equal("welcome back"))
+
+			res, err = render(body, map[string]interface{}{"vip": false}, nil)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(res["alert"]).To(Equal("hello"))
+
+			res, err = render(body, nil, nil)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(res["alert"]).To(Equal("hello"))
+		})
+
+		It("should render nested ifs", func() {
+			body := `{"alert": "{{#if vip}}{{#if gold}}gold{{else}}silver{{/if}} vip{{/if}}"}`
+			res, err := render(body, map[string]interface{}{"vip": 1.0, "gold": "false"}, nil)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(res["alert"]).To(Equal("silver vip"))
+		})
+
+		It("should pluralize", func() {
+			body := `{"alert": "{{plural lives \"# life\" \"# lives\"}} left"}`
+			res, err := render(body, map[string]interface{}{"lives": 1.0}, nil)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(res["alert"]).To(Equal("1 life left"))
+
+			res, err = render(body, map[string]interface{}{"lives": 3.0}, nil)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(res["alert"]).To(Equal("3 lives left"))
+		})
+
+		It("should replace user variables", func() {
+			user := &worker.User{
+				UserID: "abc",
+				Locale: "pt",
+				Extra:  map[string]interface{}{"level": 12.0, "nick": nil},
+			}
+			body := `{"alert": "{{user.user_id}} {{user.locale}} {{user.level}} {{user.nick|anon}}"}`
+			res, err := render(body, nil, user)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(res["alert"]).To(Equal("abc pt 12 anon"))
+		})
+
+		It("should replace user csv columns before the context", func() {
+			user := &worker.User{
+				UserID:  "abc",
+				Context: map[string]interface{}{"coins": "1", "friend_name": "Ana"},
+			}
+			body := `{"alert": "{{friend_name}} sent you {{plural coins \"# coin\" \"# coins\"}}, {{name}}"}`
+			res, err := render(body, map[string]interface{}{"coins": "5"}, user)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(res["alert"]).To(Equal("Ana sent you 1 coin, player"))
+		})
+
+		It("should render nested values and keep non string values", func() {
+			body := `{"data": {"{{key}}": ["{{name}}", 1, true]}}`
+			res, err := render(body, map[string]interface{}{"key": "k"}, nil)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(res["data"]).To(Equal(map[string]interface{}{
+				"k": []interface{}{"player", 1.0, true},
+			}))
+		})
+
+		It("should keep the body valid json if variables have quotes", func() {
+			res, err := render(`{"alert": "{{name}}"}`, map[string]interface{}{"name": `say "hi"\`}, nil)
+			Expect(err).NotTo(HaveOccurred())
+			b, err := json.Marshal(res)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(b)).To(Equal(`{"alert":"say \"hi\"\\"}`))
+		})
+
+		It("should return an error if the template is not valid", func() {
+			_, err := render(`{"alert": "{{#if vip}}hi"}`, nil, nil)
+			Expect(err).To(MatchError("invalid template: if vip is not closed"))
+
+			_, err = render(`{"alert": "hi{{/if}}"}`, nil, nil)
+			Expect(err).To(MatchError("invalid template: /if without if"))
+
+			_, err = render(`{"alert": "{{plural lives \"one\"}}"}`, nil, nil)
+			Expect(err).To(MatchError("invalid template: plural needs a variable and two forms"))
+
+			_, err = render(`{"alert": "{{name"}`, nil, nil)
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Describe("Template user columns", func() {
+		It("should return the extra user columns used by the templates", func() {
+			templates := []model.Template{
+				{Body: map[string]interface{}{"alert": "{{user.level}} {{user.locale}} {{#if user.vip}}vip{{/if}}"}},
+				{Body: map[string]interface{}{"alert": "{{user.level}} {{user.bad-column}} {{name}}"}},
+			}
+			Expect(worker.TemplateUserColumns(templates...)).To(Equal([]string{"level", "vip"}))
+		})
+	})
+})
@@ -0,0 +1,261 @@
+/*
+ * Copyright (c) 2026 TFG Co <backend@tfgco.com>
+ * Author: TFG Co <backend@tfgco.com>
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy of
+ * this software and associated documentation files (the "Software"), to deal in
+ * the Software without restriction, including without limitation the rights to
+ * use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+ * the Software, and to permit persons to whom the Software is furnished to do so,
+ * subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+ * FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+ * COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+ * IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+ * CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+ */
+
+package worker
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/topfreegames/marathon/messages"
+	"github.com/topfreegames/marathon/model"
+)
+
+// Payload limits of APNS and FCM, in bytes
+const (
+	APNSPayloadLimit = 4096
+	GCMPayloadLimit  = 4096
+)
+
+var payloadLimits = map[string]int{
+	"apns": APNSPayloadLimit,
+	"gcm":  GCMPayloadLimit,
+}
+
+// apsKeyValidators validates the values of the known keys of the aps dictionary, the body of
+// apns templates is sent as the aps dictionary
+var apsKeyValidators = map[string]func(interface{}) bool{
+	"alert":              isAPSAlert,
+	"badge":              isNonNegativeInt,
+	"sound":              isAPSSound,
+	"content-available":  isNonNegativeInt,
+	"mutable-content":    isNonNegativeInt,
+	"category":           isString,
+	"thread-id":          isString,
+	"target-content-id":  isString,
+	"interruption-level": isInterruptionLevel,
+	"relevance-score":    isNumber,
+	"filter-criteria":    isString,
+}
+
+// apsAlertKeyValidators validates the values of the keys of an alert dictionary
+var apsAlertKeyValidators = map[string]func(interface{}) bool{
+	"title":             isString,
+	"subtitle":          isString,
+	"body":              isString,
+	"launch-image":      isString,
+	"title-loc-key":     isString,
+	"title-loc-args":    isStringArray,
+	"subtitle-loc-key":  isString,
+	"subtitle-loc-args": isStringArray,
+	"loc-key":           isString,
+	"loc-args":          isStringArray,
+	"action-loc-key":    isString,
+	"summary-arg":       isString,
+	"summary-arg-count": isNonNegativeInt,
+}
+
+// apsSoundKeyValidators validates the values of the keys of a critical alert sound dictionary
+var apsSoundKeyValidators = map[string]func(interface{}) bool{
+	"critical": isNonNegativeInt,
+	"name":     isString,
+	"volume": func(value interface{}) bool {
+		volume, ok := value.(float64)
+		return ok && volume >= 0 && volume <= 1
+	},
+}
+
+// ValidateTemplate renders the template with the context and checks that its pushes fit in the
+// payload limit of each service, templates only sent to apns, like the ones of an apns job, must
+// also be valid aps dictionaries. User variables are not rendered, so they are not counted in the
+// payload size
+func ValidateTemplate(template model.Template, services []string, context, metadata map[string]interface{}) error {
+	msg, err := RenderTemplate(template, context, nil)
+	if err != nil {
+		return err
+	}
+	if len(services) == 1 && services[0] == "apns" {
+		err = ValidateAPS(msg)
+		if err != nil {
+			return err
+		}
+	}
+	for _, service := range services {
+		size, err := PushPayloadSize(service, template.Name, msg, metadata, template.PushOptions)
+		if err != nil {
+			return err
+		}
+		if size > payloadLimits[service] {
+			return fmt.Errorf("invalid template: %s payload has %d bytes, the limit is %d", service, size, payloadLimits[service])
+		}
+	}
+	return nil
+}
+
+// ValidateAPS checks that the rendered body of an apns template is a valid aps dictionary, it
+// must have an alert, badge, sound or content-available and only known keys
+func ValidateAPS(aps map[string]interface{}) error {
+	keys := make([]string, 0, len(aps))
+	for key := range aps {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		validate, ok := apsKeyValidators[key]
+		if !ok {
+			return fmt.Errorf("invalid template: unknown aps key %s", key)
+		}
+		if !validate(aps[key]) {
+			return fmt.Errorf("invalid template: invalid aps %s", key)
+		}
+	}
+	for _, key := range []string{"alert", "badge", "sound", "content-available"} {
+		if _, ok := aps[key]; ok {
+			return nil
+		}
+	}
+	return fmt.Errorf("invalid template: aps must have an alert, badge, sound or content-available")
+}
+
+// PushPayloadSize returns the size in bytes of the payload of a push with the rendered message
+// and push options, as it is delivered by the service
+func PushPayloadSize(service, templateName string, msg, metadata map[string]interface{}, options *messages.PushOptions) (int, error) {
+	rendered := make(map[string]interface{}, len(msg))
+	for key, value := range msg {
+		rendered[key] = value
+	}
+	var payload interface{}
+	switch service {
+	case "apns":
+		apnsMessage := messages.NewAPNSMessage("", 0, rendered, metadata, nil, templateName)
+		apnsMessage.SetOptions(options)
+		payload = apnsMessage.Payload
+	case "gcm":
+		gcmMessage := messages.NewGCMMessage("", rendered, metadata, nil, 0, templateName)
+		gcmMessage.SetOptions(options)
+		payload = gcmMessage.Data
+	default:
+		return 0, fmt.Errorf("service should be in ['apns', 'gcm']")
+	}
+	b, err := json.Marshal(payload)
+	if err != nil {
+		return 0, err
+	}
+	return len(b), nil
+}
+
+// MissingTemplateVariables returns the variables of the template that are neither in its
+// defaults nor in the context and have no fallback, user variables and if conditions are not
+// returned since they are filled for each user or can be empty
+func MissingTemplateVariables(template model.Template, context map[string]interface{}) []string {
+	vars := templateVariables{defaults: template.Defaults, context: context}
+	missing := map[string]bool{}
+	walkTemplateStrings(template.Body, func(str string) {
+		nodes, err := parseTemplateString(str)
+		if err != nil {
+			return
+		}
+		findMissingVariables(nodes, vars, missing)
+	})
+	res := []string{}
+	for variable := range missing {
+		res = append(res, variable)
+	}
+	sort.Strings(res)
+	return res
+}
+
+func findMissingVariables(nodes []*templateNode, vars templateVariables, missing map[string]bool) {
+	for _, node := range nodes {
+		if node.isIf {
+			findMissingVariables(node.ifBranch, vars, missing)
+			findMissingVariables(node.elseNode, vars, missing)
+			continue
+		}
+		if node.variable == "" || node.fallback != "" || strings.HasPrefix(node.variable, userVariablePrefix) {
+			continue
+		}
+		if _, ok := vars.get(node.variable); !ok {
+			missing[node.variable] = true
+		}
+	}
+}
+
+func isString(value interface{}) bool {
+	_, ok := value.(string)
+	return ok
+}
+
+func isNumber(value interface{}) bool {
+	_, ok := value.(float64)
+	return ok
+}
+
+func isNonNegativeInt(value interface{}) bool {
+	n, ok := value.(float64)
+	return ok && n >= 0 && n == float64(int64(n))
+}
+
+func isStringArray(value interface{}) bool {
+	values, ok := value.([]interface{})
+	if !ok {
+		return false
+	}
+	for _, v := range values {
+		if !isString(v) {
+			return false
+		}
+	}
+	return true
+}
+
+func isDictionary(value interface{}, validators map[string]func(interface{}) bool) bool {
+	dict, ok := value.(map[string]interface{})
+	if !ok {
+		return false
+	}
+	for key, v := range dict {
+		validate, ok := validators[key]
+		if !ok || !validate(v) {
+			return false
+		}
+	}
+	return true
+}
+
+func isAPSAlert(value interface{}) bool {
+	return isString(value) || isDictionary(value, apsAlertKeyValidators)
+}
+
+func isAPSSound(value interface{}) bool {
+	return isString(value) || isDictionary(value, apsSoundKeyValidators)
+}
+
+func isInterruptionLevel(value interface{}) bool {
+	switch value {
+	case "passive", "active", "time-sensitive", "critical":
+		return true
+	}
+	return false
+}
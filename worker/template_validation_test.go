@@ -0,0 +1,161 @@
+/*
+ * Copyright (c) 2026 TFG Co <backend@tfgco.com>
+ * Author: TFG Co <backend@tfgco.com>
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy of
+ * this software and associated documentation files (the "Software"), to deal in
+ * the Software without restriction, including without limitation the rights to
+ * use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+ * the Software, and to permit persons to whom the Software is furnished to do so,
+ * subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+ * FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+ * COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+ * IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+ * CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+ */
+
+package worker_test
+
+import (
+	"encoding/json"
+	"strings"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/topfreegames/marathon/messages"
+	"github.com/topfreegames/marathon/model"
+	"github.com/topfreegames/marathon/worker"
+)
+
+var _ = Describe("Template Validation", func() {
+	newTemplate := func(service, body string) model.Template {
+		template := model.Template{
+			Name:     "tpl",
+			Service:  service,
+			Defaults: map[string]interface{}{"name": "player"},
+		}
+		err := json.Unmarshal([]byte(body), &template.Body)
+		Expect(err).NotTo(HaveOccurred())
+		return template
+	}
+
+	Describe("Validate template", func() {
+		It("should accept valid apns templates", func() {
+			body := `{
+				"alert": {"title": "Hi {{name}}", "body": "you won", "loc-args": ["a"]},
+				"badge": 1,
+				"sound": {"critical": 1, "name": "bell.caf", "volume": 0.5},
+				"mutable-content": 1,
+				"thread-id": "rewards"
+			}`
+			err := worker.ValidateTemplate(newTemplate("apns", body), []string{"apns"}, nil, nil)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("should accept silent apns templates", func() {
+			err := worker.ValidateTemplate(newTemplate("apns", `{"content-available": 1}`), []string{"apns"}, nil, nil)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("should fail if an apns template has no alert, badge, sound or content-available", func() {
+			err := worker.ValidateTemplate(newTemplate("apns", `{"category": "reward"}`), []string{"apns"}, nil, nil)
+			Expect(err).To(MatchError("invalid template: aps must have an alert, badge, sound or content-available"))
+		})
+
+		It("should fail if an apns template has unknown keys", func() {
+			err := worker.ValidateTemplate(newTemplate("apns", `{"alert": "hi", "reward": "gold"}`), []string{"apns"}, nil, nil)
+			Expect(err).To(MatchError("invalid template: unknown aps key reward"))
+		})
+
+		It("should fail if the aps values have the wrong types", func() {
+			for _, body := range []string{
+				`{"alert": 1}`,
+				`{"alert": {"text": "hi"}}`,
+				`{"alert": {"loc-args": [1]}}`,
+				`{"badge": "{{count}}"}`,
+				`{"badge": -1}`,
+				`{"badge": 1.5}`,
+				`{"sound": true}`,
+				`{"sound": {"volume": 2}}`,
+			} {
+				err := worker.ValidateTemplate(newTemplate("apns", body), []string{"apns"}, nil, nil)
+				Expect(err).To(HaveOccurred(), body)
+				Expect(err.Error()).To(HavePrefix("invalid template: invalid aps"), body)
+			}
+		})
+
+		It("should not check the aps keys of templates sent to both services", func() {
+			err := worker.ValidateTemplate(newTemplate("", `{"value": "hi"}`), []string{"apns", "gcm"}, nil, nil)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("should check the aps keys of templates without service sent to apns", func() {
+			err := worker.ValidateTemplate(newTemplate("", `{"alert": "hi", "value": "hi"}`), []string{"apns"}, nil, nil)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(Equal("invalid template: unknown aps key value"))
+		})
+
+		It("should fail if the rendered payload is over the service limit", func() {
+			body := `{"alert": "{{text}}"}`
+			context := map[string]interface{}{"text": strings.Repeat("a", worker.APNSPayloadLimit)}
+			err := worker.ValidateTemplate(newTemplate("", body), []string{"gcm"}, context, nil)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(HavePrefix("invalid template: gcm payload has"))
+
+			err = worker.ValidateTemplate(newTemplate("", body), []string{"gcm"}, nil, nil)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("should count the metadata in the payload size", func() {
+			metadata := map[string]interface{}{"data": strings.Repeat("a", worker.APNSPayloadLimit)}
+			err := worker.ValidateTemplate(newTemplate("apns", `{"alert": "hi"}`), []string{"apns"}, nil, metadata)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(HavePrefix("invalid template: apns payload has"))
+		})
+	})
+
+	Describe("Push payload size", func() {
+		It("should return the size of the payload delivered by each service", func() {
+			msg := map[string]interface{}{"alert": "hi"}
+			size, err := worker.PushPayloadSize("apns", "tpl", msg, nil, nil)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(size).To(Equal(len(`{"aps":{"alert":"hi"},"templateName":"tpl"}`)))
+
+			size, err = worker.PushPayloadSize("gcm", "tpl", msg, map[string]interface{}{"a": 1}, nil)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(size).To(Equal(len(`{"alert":"hi","m":{"a":1},"templateName":"tpl"}`)))
+			Expect(msg).NotTo(HaveKey("templateName"))
+		})
+
+		It("should count the push options in the payload size", func() {
+			msg := map[string]interface{}{"alert": "hi"}
+			options := &messages.PushOptions{MediaURL: "https://a.co/i.png", ThreadID: "t", ChannelID: "c"}
+			size, err := worker.PushPayloadSize("apns", "tpl", msg, nil, options)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(size).To(Equal(len(`{"aps":{"alert":"hi","mutable-content":1,"thread-id":"t"},"media-url":"https://a.co/i.png","templateName":"tpl"}`)))
+			Expect(msg).To(Equal(map[string]interface{}{"alert": "hi"}))
+
+			size, err = worker.PushPayloadSize("gcm", "tpl", msg, nil, options)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(size).To(Equal(len(`{"alert":"hi","android_channel_id":"c","image":"https://a.co/i.png","templateName":"tpl"}`)))
+		})
+	})
+
+	Describe("Missing template variables", func() {
+		It("should return the variables that are not in the defaults or the context", func() {
+			body := `{
+				"alert": "{{name}} won {{reward}} in {{game}}",
+				"title": "{{nick|friend}} {{user.level}}",
+				"{{key}}": "{{#if vip}}{{vipText}}{{else}}hi{{/if}}"
+			}`
+			missing := worker.MissingTemplateVariables(newTemplate("", body), map[string]interface{}{"reward": "gold"})
+			Expect(missing).To(Equal([]string{"game", "key", "vipText"}))
+		})
+	})
+})
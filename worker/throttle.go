@@ -0,0 +1,130 @@
+/*
+ * Copyright (c) 2026 TFG Co <backend@tfgco.com>
+ * Author: TFG Co <backend@tfgco.com>
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy of
+ * this software and associated documentation files (the "Software"), to deal in
+ * the Software without restriction, including without limitation the rights to
+ * use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+ * the Software, and to permit persons to whom the Software is furnished to do so,
+ * subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+ * FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+ * COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+ * IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+ * CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+ */
+
+package worker
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/topfreegames/marathon/model"
+	redis "gopkg.in/redis.v5"
+)
+
+// throttleScript takes up to the requested tokens from the bucket of the job. The bucket holds
+// at most a second of pushes and is refilled at the push rate, ARGV has the push rate, the
+// requested tokens and the time in milliseconds. It returns the granted tokens and, if none
+// was granted, the milliseconds until the next token
+var throttleScript = redis.NewScript(`
+local rate = tonumber(ARGV[1])
+local requested = tonumber(ARGV[2])
+local now = tonumber(ARGV[3])
+local capacity = math.max(rate, 1)
+local bucket = redis.call("HMGET", KEYS[1], "tokens", "updatedAt")
+local tokens = tonumber(bucket[1]) or capacity
+local updatedAt = tonumber(bucket[2]) or now
+tokens = math.min(capacity, tokens + math.max(now - updatedAt, 0) * rate / 1000)
+local granted = math.min(requested, math.floor(tokens))
+tokens = tokens - granted
+redis.call("HMSET", KEYS[1], "tokens", tostring(tokens), "updatedAt", now)
+redis.call("PEXPIRE", KEYS[1], 60000)
+local wait = 0
+if granted == 0 then
+  wait = math.ceil((1 - tokens) * 1000 / rate)
+end
+return {granted, wait}
+`)
+
+// GetThrottleRedisKey returns the token bucket of the job
+func GetThrottleRedisKey(job *model.Job) string {
+	return fmt.Sprintf("%s-THROTTLE", job.ID.String())
+}
+
+// Throttle limits the pushes of a job to its push rate with a token bucket shared by every
+// worker, the tokens are taken from the bucket in chunks of a tenth of a second of pushes
+type Throttle struct {
+	client *redis.Client
+	job    *model.Job
+	rate   float64
+	tokens int
+}
+
+// NewThrottle returns the throttle of the job, it never waits if the job is not throttled
+func NewThrottle(client *redis.Client, job *model.Job) *Throttle {
+	return &Throttle{
+		client: client,
+		job:    job,
+		rate:   job.PushRate(),
+	}
+}
+
+// Take takes a token for a push of the job, pending is the number of pushes the caller still
+// has to send including this one. If the bucket is empty it returns false and the delay after
+// which the bucket holds the tokens of the pending pushes, at most a second of pushes, so the
+// caller can schedule them instead of blocking its worker
+func (t *Throttle) Take(pending int) (bool, time.Duration, error) {
+	if t.rate <= 0 {
+		return true, 0, nil
+	}
+	if t.tokens == 0 {
+		granted, wait, err := t.take(int(math.Ceil(t.rate / 10)))
+		if err != nil {
+			return false, 0, err
+		}
+		if granted == 0 {
+			refill := math.Min(float64(pending), math.Max(t.rate, 1)) / t.rate
+			return false, wait + time.Duration(refill*float64(time.Second)), nil
+		}
+		t.tokens = granted
+	}
+	t.tokens--
+	return true, 0, nil
+}
+
+// ScheduleThrottledUsers schedules a process batch job for the users that could not be sent
+// because the job reached its push rate, the batch is added to the job total batches so it
+// only completes after it is processed
+func ScheduleThrottledUsers(w *Worker, job *model.Job, appName string, users []User, at int64) error {
+	if len(users) == 0 {
+		return nil
+	}
+	_, err := w.MarathonDB.Exec(
+		"UPDATE jobs SET total_batches = coalesce(total_batches, 0) + 1 WHERE id = ?",
+		job.ID,
+	)
+	if err != nil {
+		return err
+	}
+	_, err = w.ScheduleProcessBatchJob(job.ID.String(), appName, job.Priority, &users, at)
+	return err
+}
+
+func (t *Throttle) take(requested int) (int, time.Duration, error) {
+	now := time.Now().UnixNano() / int64(time.Millisecond)
+	res, err := throttleScript.Run(t.client, []string{GetThrottleRedisKey(t.job)}, t.rate, requested, now).Result()
+	if err != nil {
+		return 0, 0, err
+	}
+	values := res.([]interface{})
+	return int(values[0].(int64)), time.Duration(values[1].(int64)) * time.Millisecond, nil
+}
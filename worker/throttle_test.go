@@ -0,0 +1,133 @@
+/*
+ * Copyright (c) 2026 TFG Co <backend@tfgco.com>
+ * Author: TFG Co <backend@tfgco.com>
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy of
+ * this software and associated documentation files (the "Software"), to deal in
+ * the Software without restriction, including without limitation the rights to
+ * use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+ * the Software, and to permit persons to whom the Software is furnished to do so,
+ * subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+ * FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+ * COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+ * IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+ * CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+ */
+
+package worker_test
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	uuid "github.com/satori/go.uuid"
+	"github.com/topfreegames/marathon/model"
+	. "github.com/topfreegames/marathon/testing"
+	"github.com/topfreegames/marathon/worker"
+	"github.com/uber-go/zap"
+)
+
+var _ = Describe("Throttle", func() {
+	logger := zap.New(
+		zap.NewJSONEncoder(zap.NoTime()),
+		zap.FatalLevel,
+	)
+	w := worker.NewWorker(logger, GetConfPath())
+
+	BeforeEach(func() {
+		w.RedisClient.FlushAll()
+	})
+
+	Describe("Push rate", func() {
+		It("should use the max pushes per second", func() {
+			job := &model.Job{MaxPushesPerSecond: 100, TotalTokens: 1000}
+			Expect(job.PushRate()).To(Equal(100.0))
+		})
+
+		It("should send the total tokens in the spread duration", func() {
+			job := &model.Job{SpreadOver: int64(10 * time.Minute), TotalTokens: 6000}
+			Expect(job.PushRate()).To(Equal(10.0))
+		})
+
+		It("should not throttle the job otherwise", func() {
+			Expect((&model.Job{TotalTokens: 1000}).PushRate()).To(BeZero())
+			Expect((&model.Job{SpreadOver: int64(time.Minute)}).PushRate()).To(BeZero())
+		})
+	})
+
+	Describe("Progress", func() {
+		It("should compare the completed tokens with the tokens sent at the push rate", func() {
+			startsAt := time.Date(2026, 3, 10, 10, 0, 0, 0, time.UTC)
+			job := &model.Job{
+				MaxPushesPerSecond: 10,
+				TotalTokens:        1000,
+				CompletedTokens:    200,
+				StartsAt:           startsAt.UnixNano(),
+			}
+			throttle := job.GetThrottle(startsAt.Add(30 * time.Second))
+			Expect(throttle).To(Equal(&model.JobThrottle{
+				PushRate:             10,
+				TargetTokens:         300,
+				CompletedTokens:      200,
+				EstimatedCompletedAt: startsAt.Add(110 * time.Second).UnixNano(),
+			}))
+
+			Expect(job.GetThrottle(startsAt.Add(-time.Second))).To(BeNil())
+			Expect(job.GetThrottle(startsAt.Add(time.Hour)).TargetTokens).To(Equal(1000))
+		})
+	})
+
+	Describe("Take", func() {
+		It("should always send if the job is not throttled", func() {
+			throttle := worker.NewThrottle(w.RedisClient, &model.Job{ID: uuid.NewV4()})
+			for i := 0; i < 1000; i++ {
+				sendNow, wait, err := throttle.Take(1000 - i)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(sendNow).To(BeTrue())
+				Expect(wait).To(BeZero())
+			}
+		})
+
+		It("should share the push rate between the throttles of the job", func() {
+			job := &model.Job{ID: uuid.NewV4(), MaxPushesPerSecond: 20}
+			throttles := []*worker.Throttle{
+				worker.NewThrottle(w.RedisClient, job),
+				worker.NewThrottle(w.RedisClient, job),
+			}
+			// the bucket starts with a second of pushes
+			sent := 0
+			for i := 0; i < 40; i++ {
+				sendNow, _, err := throttles[i%2].Take(40 - i)
+				Expect(err).NotTo(HaveOccurred())
+				if sendNow {
+					sent++
+				}
+			}
+			Expect(sent).To(BeNumerically("~", 20, 2))
+		})
+
+		It("should return when the bucket refills for the pending pushes without waiting", func() {
+			job := &model.Job{ID: uuid.NewV4(), MaxPushesPerSecond: 20}
+			throttle := worker.NewThrottle(w.RedisClient, job)
+			for i := 0; i < 20; i++ {
+				throttle.Take(1)
+			}
+
+			start := time.Now()
+			sendNow, wait, err := throttle.Take(10)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(time.Since(start)).To(BeNumerically("<", 100*time.Millisecond))
+			Expect(sendNow).To(BeFalse())
+			// half a second refills the 10 pending pushes
+			Expect(wait).To(BeNumerically(">=", 500*time.Millisecond))
+			Expect(wait).To(BeNumerically("<", 700*time.Millisecond))
+		})
+	})
+})
@@ -0,0 +1,140 @@
+/*
+ * Copyright (c) 2026 TFG Co <backend@tfgco.com>
+ * Author: TFG Co <backend@tfgco.com>
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy of
+ * this software and associated documentation files (the "Software"), to deal in
+ * the Software without restriction, including without limitation the rights to
+ * use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+ * the Software, and to permit persons to whom the Software is furnished to do so,
+ * subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+ * FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+ * COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+ * IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+ * CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+ */
+
+package worker
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/topfreegames/marathon/interfaces"
+	"github.com/topfreegames/marathon/model"
+	"gopkg.in/pg.v5"
+	redis "gopkg.in/redis.v5"
+)
+
+var tzOffsetRegex = regexp.MustCompile(`^([+-])(\d{2}):?(\d{2})$`)
+
+// TimezoneGroup has the tz values of the users that are sent a localized job at the same time
+type TimezoneGroup struct {
+	StartsAt  int64
+	Timezones []string
+}
+
+// UserLocation returns the location of the tz of a user, tz is an IANA zone name like
+// America/Sao_Paulo or an offset from UTC like -0300
+func UserLocation(tz string) (*time.Location, bool) {
+	matches := tzOffsetRegex.FindStringSubmatch(tz)
+	if matches == nil {
+		if tz == "" || tz == "Local" {
+			return nil, false
+		}
+		location, err := time.LoadLocation(tz)
+		return location, err == nil
+	}
+	hours, _ := strconv.Atoi(matches[2])
+	minutes, _ := strconv.Atoi(matches[3])
+	if hours > 14 || minutes >= 60 {
+		return nil, false
+	}
+	offset := (hours*60 + minutes) * 60
+	if matches[1] == "-" {
+		offset *= -1
+	}
+	return time.FixedZone(tz, offset), true
+}
+
+// LocalSendTime returns the instant in which the location clock shows the UTC clock of startsAt
+func LocalSendTime(startsAt int64, location *time.Location) time.Time {
+	t := time.Unix(0, startsAt).UTC()
+	return time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), location)
+}
+
+// GroupTimezones groups the tz values by the instant in which the job is sent to them, values
+// that are not a valid tz are sent at startsAt in UTC. If the instant already passed the group
+// is skipped or sent in the next day, according to pastTimeStrategy
+func GroupTimezones(timezones []string, startsAt int64, pastTimeStrategy string, now time.Time) []TimezoneGroup {
+	byStartsAt := map[int64][]string{}
+	for _, tz := range timezones {
+		location, ok := UserLocation(tz)
+		if !ok {
+			location = time.UTC
+		}
+		sendTime := LocalSendTime(startsAt, location)
+		if sendTime.Before(now) {
+			if pastTimeStrategy == "skip" {
+				continue
+			}
+			sendTime = sendTime.AddDate(0, 0, 1)
+		}
+		byStartsAt[sendTime.UnixNano()] = append(byStartsAt[sendTime.UnixNano()], tz)
+	}
+
+	groups := make([]TimezoneGroup, 0, len(byStartsAt))
+	for at, tzs := range byStartsAt {
+		sort.Strings(tzs)
+		groups = append(groups, TimezoneGroup{StartsAt: at, Timezones: tzs})
+	}
+	sort.Slice(groups, func(i, j int) bool {
+		return groups[i].StartsAt < groups[j].StartsAt
+	})
+	return groups
+}
+
+// GetTimezonesRedisKey returns the key of the cached tz values of the push db table
+func GetTimezonesRedisKey(tableName string) string {
+	return fmt.Sprintf("%s-TIMEZONES", tableName)
+}
+
+// GetPushDBTimezones returns the distinct tz values of the users of the push db table of the
+// job, null tz values are returned as empty strings. The values are cached in redis for ttl so
+// creating localized jobs does not scan the table every time, the job filters are not applied
+// so the job of a tz without filtered users just sends nothing
+func GetPushDBTimezones(client *redis.Client, db interfaces.DB, job *model.Job, ttl time.Duration) ([]string, error) {
+	tableName := GetPushDBTableName(job.App.Name, job.Service)
+	key := GetTimezonesRedisKey(tableName)
+	var timezones []string
+	cached, err := client.Get(key).Result()
+	if err == nil && json.Unmarshal([]byte(cached), &timezones) == nil {
+		return timezones, nil
+	}
+	if err != nil && err != redis.Nil {
+		return nil, err
+	}
+
+	var values pg.Strings
+	_, err = db.Query(&values, fmt.Sprintf("SELECT DISTINCT coalesce(tz, '') FROM %s", tableName))
+	if err != nil {
+		return nil, err
+	}
+	timezones = values
+	b, err := json.Marshal(timezones)
+	if err != nil {
+		return nil, err
+	}
+	err = client.Set(key, string(b), ttl).Err()
+	return timezones, err
+}
@@ -0,0 +1,147 @@
+/*
+ * Copyright (c) 2026 TFG Co <backend@tfgco.com>
+ * Author: TFG Co <backend@tfgco.com>
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy of
+ * this software and associated documentation files (the "Software"), to deal in
+ * the Software without restriction, including without limitation the rights to
+ * use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+ * the Software, and to permit persons to whom the Software is furnished to do so,
+ * subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+ * FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+ * COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+ * IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+ * CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+ */
+
+package worker_test
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/topfreegames/marathon/model"
+	. "github.com/topfreegames/marathon/testing"
+	"github.com/topfreegames/marathon/worker"
+	"github.com/uber-go/zap"
+)
+
+var _ = Describe("Timezone", func() {
+	Describe("User location", func() {
+		It("should parse the user tz offset", func() {
+			location, ok := worker.UserLocation("-0330")
+			Expect(ok).To(BeTrue())
+			_, offset := time.Date(2026, 1, 1, 0, 0, 0, 0, location).Zone()
+			Expect(offset).To(Equal(-(3*60 + 30) * 60))
+
+			location, ok = worker.UserLocation("+05:45")
+			Expect(ok).To(BeTrue())
+			_, offset = time.Date(2026, 1, 1, 0, 0, 0, 0, location).Zone()
+			Expect(offset).To(Equal((5*60 + 45) * 60))
+		})
+
+		It("should load an IANA zone name", func() {
+			location, ok := worker.UserLocation("America/New_York")
+			Expect(ok).To(BeTrue())
+			_, offset := time.Date(2026, 1, 1, 0, 0, 0, 0, location).Zone()
+			Expect(offset).To(Equal(-5 * 60 * 60))
+			_, offset = time.Date(2026, 7, 1, 0, 0, 0, 0, location).Zone()
+			Expect(offset).To(Equal(-4 * 60 * 60))
+		})
+
+		It("should not parse an invalid tz", func() {
+			for _, tz := range []string{"", "Local", "utc", "0300", "+3", "-4440", "Mars/Olympus"} {
+				_, ok := worker.UserLocation(tz)
+				Expect(ok).To(BeFalse())
+			}
+		})
+	})
+
+	Describe("Local send time", func() {
+		It("should send at the clock of startsAt in the location across DST changes", func() {
+			location, _ := worker.UserLocation("America/New_York")
+
+			// DST starts in New York in 2026-03-08
+			before := time.Date(2026, 3, 7, 10, 0, 0, 0, time.UTC).UnixNano()
+			Expect(worker.LocalSendTime(before, location).UTC()).To(Equal(time.Date(2026, 3, 7, 15, 0, 0, 0, time.UTC)))
+			after := time.Date(2026, 3, 9, 10, 0, 0, 0, time.UTC).UnixNano()
+			Expect(worker.LocalSendTime(after, location).UTC()).To(Equal(time.Date(2026, 3, 9, 14, 0, 0, 0, time.UTC)))
+		})
+	})
+
+	Describe("Group timezones", func() {
+		startsAt := time.Date(2026, 3, 10, 10, 0, 0, 0, time.UTC).UnixNano()
+		timezones := []string{"America/Sao_Paulo", "-0300", "+0530", "Asia/Kolkata", "-4440", "Europe/London"}
+
+		It("should group the timezones sent at the same time", func() {
+			now := time.Date(2026, 3, 10, 0, 0, 0, 0, time.UTC)
+			groups := worker.GroupTimezones(timezones, startsAt, "", now)
+			Expect(groups).To(Equal([]worker.TimezoneGroup{
+				{StartsAt: time.Date(2026, 3, 10, 4, 30, 0, 0, time.UTC).UnixNano(), Timezones: []string{"+0530", "Asia/Kolkata"}},
+				{StartsAt: time.Date(2026, 3, 10, 10, 0, 0, 0, time.UTC).UnixNano(), Timezones: []string{"-4440", "Europe/London"}},
+				{StartsAt: time.Date(2026, 3, 10, 13, 0, 0, 0, time.UTC).UnixNano(), Timezones: []string{"-0300", "America/Sao_Paulo"}},
+			}))
+		})
+
+		It("should send the groups that passed in the next day", func() {
+			now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+			groups := worker.GroupTimezones(timezones, startsAt, "nextDay", now)
+			Expect(groups).To(HaveLen(3))
+			Expect(groups[0].Timezones).To(Equal([]string{"-0300", "America/Sao_Paulo"}))
+			Expect(groups[1].StartsAt).To(Equal(time.Date(2026, 3, 11, 4, 30, 0, 0, time.UTC).UnixNano()))
+			Expect(groups[2].StartsAt).To(Equal(time.Date(2026, 3, 11, 10, 0, 0, 0, time.UTC).UnixNano()))
+		})
+
+		It("should skip the groups that passed", func() {
+			now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+			groups := worker.GroupTimezones(timezones, startsAt, "skip", now)
+			Expect(groups).To(HaveLen(1))
+			Expect(groups[0].Timezones).To(Equal([]string{"-0300", "America/Sao_Paulo"}))
+		})
+	})
+
+	Describe("Push DB timezones", func() {
+		logger := zap.New(
+			zap.NewJSONEncoder(zap.NoTime()),
+			zap.FatalLevel,
+		)
+		w := worker.NewWorker(logger, GetConfPath())
+
+		BeforeEach(func() {
+			w.RedisClient.FlushAll()
+		})
+
+		It("should return the cached timezones without querying the push db", func() {
+			job := &model.Job{App: model.App{Name: "cachedapp"}, Service: "apns"}
+			key := worker.GetTimezonesRedisKey(worker.GetPushDBTableName("cachedapp", "apns"))
+			Expect(w.RedisClient.Set(key, `["","-0300"]`, time.Minute).Err()).To(Succeed())
+
+			timezones, err := worker.GetPushDBTimezones(w.RedisClient, nil, job, time.Hour)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(timezones).To(Equal([]string{"", "-0300"}))
+		})
+
+		It("should cache the timezones of the push db table", func() {
+			app := CreateTestApp(w.MarathonDB)
+			template := CreateTestTemplate(w.MarathonDB, app.ID)
+			job := CreateTestJob(w.MarathonDB, app.ID, template.Name)
+			job.GetJobInfoAndApp(w.MarathonDB)
+
+			timezones, err := worker.GetPushDBTimezones(w.RedisClient, w.PushDB, job, time.Hour)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(timezones).NotTo(BeEmpty())
+
+			key := worker.GetTimezonesRedisKey(worker.GetPushDBTableName(job.App.Name, job.Service))
+			ttl, err := w.RedisClient.TTL(key).Result()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(ttl).To(BeNumerically("~", time.Hour, time.Minute))
+		})
+	})
+})
@@ -0,0 +1,193 @@
+/*
+ * Copyright (c) 2026 TFG Co <backend@tfgco.com>
+ * Author: TFG Co <backend@tfgco.com>
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy of
+ * this software and associated documentation files (the "Software"), to deal in
+ * the Software without restriction, including without limitation the rights to
+ * use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+ * the Software, and to permit persons to whom the Software is furnished to do so,
+ * subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+ * FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+ * COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+ * IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+ * CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+ */
+
+package worker
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/topfreegames/marathon/model"
+)
+
+// upliftZScore is the z score of the 95% confidence interval of the uplift
+const upliftZScore = 1.96
+
+// UpliftEvent is a conversion event of a user, its timestamp is in nanoseconds since epoch
+type UpliftEvent struct {
+	UserID    string
+	Event     string
+	Timestamp int64
+}
+
+// ReadUpliftEvents reads a csv with the header user_id,event,timestamp or a file with a json
+// object by line with the same keys, timestamps are seconds since epoch or RFC3339 dates
+func ReadUpliftEvents(buffer []byte) ([]UpliftEvent, error) {
+	if bytes.HasPrefix(bytes.TrimSpace(buffer), []byte("{")) {
+		return readUpliftEventsJSONL(buffer)
+	}
+	return readUpliftEventsCSV(buffer)
+}
+
+func readUpliftEventsCSV(buffer []byte) ([]UpliftEvent, error) {
+	rows, err := readCSVRows(buffer)
+	if err != nil {
+		return nil, err
+	}
+	if len(rows) == 0 {
+		return nil, fmt.Errorf("events file is empty")
+	}
+	indexes := map[string]int{}
+	for i, column := range rows[0] {
+		indexes[strings.TrimSpace(column)] = i
+	}
+	for _, column := range []string{"user_id", "event", "timestamp"} {
+		if _, ok := indexes[column]; !ok {
+			return nil, fmt.Errorf("events file has no %s column", column)
+		}
+	}
+
+	events := make([]UpliftEvent, 0, len(rows)-1)
+	for line, row := range rows[1:] {
+		if len(row) < len(rows[0]) {
+			return nil, fmt.Errorf("invalid event in line %d", line+2)
+		}
+		timestamp, err := parseUpliftTimestamp(row[indexes["timestamp"]])
+		if err != nil {
+			return nil, fmt.Errorf("invalid event in line %d: %s", line+2, err.Error())
+		}
+		events = append(events, UpliftEvent{
+			UserID:    row[indexes["user_id"]],
+			Event:     row[indexes["event"]],
+			Timestamp: timestamp,
+		})
+	}
+	return events, nil
+}
+
+func readUpliftEventsJSONL(buffer []byte) ([]UpliftEvent, error) {
+	events := []UpliftEvent{}
+	scanner := bufio.NewScanner(bytes.NewReader(buffer))
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	line := 0
+	for scanner.Scan() {
+		line++
+		if len(bytes.TrimSpace(scanner.Bytes())) == 0 {
+			continue
+		}
+		var event struct {
+			UserID    string      `json:"user_id"`
+			Event     string      `json:"event"`
+			Timestamp interface{} `json:"timestamp"`
+		}
+		err := json.Unmarshal(scanner.Bytes(), &event)
+		if err != nil {
+			return nil, fmt.Errorf("invalid event in line %d: %s", line, err.Error())
+		}
+		timestamp, err := parseUpliftTimestamp(fmt.Sprint(event.Timestamp))
+		if err != nil {
+			return nil, fmt.Errorf("invalid event in line %d: %s", line, err.Error())
+		}
+		events = append(events, UpliftEvent{
+			UserID:    event.UserID,
+			Event:     event.Event,
+			Timestamp: timestamp,
+		})
+	}
+	return events, scanner.Err()
+}
+
+func parseUpliftTimestamp(value string) (int64, error) {
+	value = strings.TrimSpace(value)
+	if seconds, err := strconv.ParseFloat(value, 64); err == nil {
+		return int64(seconds * float64(time.Second)), nil
+	}
+	date, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid timestamp %q", value)
+	}
+	return date.UnixNano(), nil
+}
+
+// ReadUpliftUsers returns the user ids of the first column of a csv with a header, like the
+// control group and treated group files written by the job completed worker
+func ReadUpliftUsers(buffer []byte) (map[string]bool, error) {
+	rows, err := readCSVRows(buffer)
+	if err != nil {
+		return nil, err
+	}
+	users := map[string]bool{}
+	for i, row := range rows {
+		if i == 0 || len(row) == 0 || row[0] == "" {
+			continue
+		}
+		users[row[0]] = true
+	}
+	return users, nil
+}
+
+// ComputeUplift fills the report with the conversions of the treated and control users, a user
+// converts if it has an event of the report after the job started
+func ComputeUplift(report *model.UpliftReport, treated, control map[string]bool, events []UpliftEvent, startedAt int64) {
+	converted := map[string]bool{}
+	for _, event := range events {
+		if event.Timestamp < startedAt || (report.Event != "" && event.Event != report.Event) {
+			continue
+		}
+		converted[event.UserID] = true
+	}
+
+	report.Treated = upliftGroup(treated, converted)
+	report.Control = upliftGroup(control, converted)
+	report.AbsoluteUplift = report.Treated.ConversionRate - report.Control.ConversionRate
+	report.RelativeUplift = 0
+	if report.Control.ConversionRate > 0 {
+		report.RelativeUplift = report.AbsoluteUplift / report.Control.ConversionRate
+	}
+
+	margin := 0.0
+	if report.Treated.Users > 0 && report.Control.Users > 0 {
+		pt := report.Treated.ConversionRate
+		pc := report.Control.ConversionRate
+		margin = upliftZScore * math.Sqrt(pt*(1-pt)/float64(report.Treated.Users)+pc*(1-pc)/float64(report.Control.Users))
+	}
+	report.ConfidenceInterval = []float64{report.AbsoluteUplift - margin, report.AbsoluteUplift + margin}
+}
+
+func upliftGroup(users, converted map[string]bool) model.UpliftGroup {
+	group := model.UpliftGroup{Users: len(users)}
+	for user := range users {
+		if converted[user] {
+			group.Conversions++
+		}
+	}
+	if group.Users > 0 {
+		group.ConversionRate = float64(group.Conversions) / float64(group.Users)
+	}
+	return group
+}
@@ -0,0 +1,109 @@
+/*
+ * Copyright (c) 2026 TFG Co <backend@tfgco.com>
+ * Author: TFG Co <backend@tfgco.com>
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy of
+ * this software and associated documentation files (the "Software"), to deal in
+ * the Software without restriction, including without limitation the rights to
+ * use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+ * the Software, and to permit persons to whom the Software is furnished to do so,
+ * subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+ * FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+ * COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+ * IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+ * CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+ */
+
+package worker_test
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/topfreegames/marathon/model"
+	"github.com/topfreegames/marathon/worker"
+)
+
+var _ = Describe("Uplift", func() {
+	Describe("Read uplift events", func() {
+		It("should read a csv with the columns in any order", func() {
+			events, err := worker.ReadUpliftEvents([]byte("event,user_id,timestamp\npurchase,user1,1500000000\nlogin,user2,2017-07-14T02:40:00Z\n"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(events).To(Equal([]worker.UpliftEvent{
+				{UserID: "user1", Event: "purchase", Timestamp: 1500000000 * int64(time.Second)},
+				{UserID: "user2", Event: "login", Timestamp: 1500000000 * int64(time.Second)},
+			}))
+		})
+
+		It("should read a json object by line", func() {
+			events, err := worker.ReadUpliftEvents([]byte("{\"user_id\":\"user1\",\"event\":\"purchase\",\"timestamp\":1500000000}\n\n{\"user_id\":\"user2\",\"event\":\"login\",\"timestamp\":\"2017-07-14T02:40:00Z\"}\n"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(events).To(Equal([]worker.UpliftEvent{
+				{UserID: "user1", Event: "purchase", Timestamp: 1500000000 * int64(time.Second)},
+				{UserID: "user2", Event: "login", Timestamp: 1500000000 * int64(time.Second)},
+			}))
+		})
+
+		It("should return an error if a column is missing", func() {
+			_, err := worker.ReadUpliftEvents([]byte("user_id,timestamp\nuser1,1500000000\n"))
+			Expect(err).To(MatchError("events file has no event column"))
+		})
+
+		It("should return an error if a timestamp is invalid", func() {
+			_, err := worker.ReadUpliftEvents([]byte("user_id,event,timestamp\nuser1,purchase,yesterday\n"))
+			Expect(err).To(MatchError("invalid event in line 2: invalid timestamp \"yesterday\""))
+			_, err = worker.ReadUpliftEvents([]byte("{\"user_id\":\"user1\",\"event\":\"purchase\",\"timestamp\":\"yesterday\"}\n"))
+			Expect(err).To(MatchError("invalid event in line 1: invalid timestamp \"yesterday\""))
+		})
+	})
+
+	Describe("Read uplift users", func() {
+		It("should skip the header", func() {
+			users, err := worker.ReadUpliftUsers([]byte("controlGroupUserIds\nuser1\nuser2\n"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(users).To(Equal(map[string]bool{"user1": true, "user2": true}))
+		})
+	})
+
+	Describe("Compute uplift", func() {
+		It("should compare the conversions of the treated and control users", func() {
+			treated := map[string]bool{"t1": true, "t2": true, "t3": true, "t4": true}
+			control := map[string]bool{"c1": true, "c2": true, "c3": true, "c4": true}
+			events := []worker.UpliftEvent{
+				{UserID: "t1", Event: "purchase", Timestamp: 20},
+				{UserID: "t1", Event: "purchase", Timestamp: 30},
+				{UserID: "t2", Event: "purchase", Timestamp: 20},
+				{UserID: "t3", Event: "login", Timestamp: 20},
+				{UserID: "t4", Event: "purchase", Timestamp: 5},
+				{UserID: "c1", Event: "purchase", Timestamp: 20},
+			}
+			report := &model.UpliftReport{Event: "purchase"}
+			worker.ComputeUplift(report, treated, control, events, 10)
+
+			Expect(report.Treated).To(Equal(model.UpliftGroup{Users: 4, Conversions: 2, ConversionRate: 0.5}))
+			Expect(report.Control).To(Equal(model.UpliftGroup{Users: 4, Conversions: 1, ConversionRate: 0.25}))
+			Expect(report.AbsoluteUplift).To(BeNumerically("~", 0.25, 1e-9))
+			Expect(report.RelativeUplift).To(BeNumerically("~", 1, 1e-9))
+			Expect(report.ConfidenceInterval).To(HaveLen(2))
+			Expect(report.ConfidenceInterval[0]).To(BeNumerically("~", 0.25-0.6482, 1e-4))
+			Expect(report.ConfidenceInterval[1]).To(BeNumerically("~", 0.25+0.6482, 1e-4))
+		})
+
+		It("should count any event if the report has no event", func() {
+			report := &model.UpliftReport{}
+			worker.ComputeUplift(report, map[string]bool{"t1": true}, map[string]bool{"c1": true}, []worker.UpliftEvent{
+				{UserID: "t1", Event: "login", Timestamp: 20},
+			}, 10)
+			Expect(report.Treated.Conversions).To(Equal(1))
+			Expect(report.Control.Conversions).To(Equal(0))
+			Expect(report.RelativeUplift).To(Equal(0.0))
+		})
+	})
+})
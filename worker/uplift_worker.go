@@ -0,0 +1,135 @@
+/*
+ * Copyright (c) 2026 TFG Co <backend@tfgco.com>
+ * Author: TFG Co <backend@tfgco.com>
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy of
+ * this software and associated documentation files (the "Software"), to deal in
+ * the Software without restriction, including without limitation the rights to
+ * use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+ * the Software, and to permit persons to whom the Software is furnished to do so,
+ * subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+ * FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+ * COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+ * IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+ * CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+ */
+
+package worker
+
+import (
+	"time"
+
+	goworkers2 "github.com/digitalocean/go-workers2"
+	uuid "github.com/satori/go.uuid"
+	"github.com/topfreegames/marathon/log"
+	"github.com/topfreegames/marathon/model"
+	"github.com/uber-go/zap"
+)
+
+const nameUplift = "uplift_worker"
+
+// UpliftWorker is the UpliftWorker struct
+type UpliftWorker struct {
+	Workers *Worker
+	Logger  zap.Logger
+}
+
+// NewUpliftWorker gets a new UpliftWorker
+func NewUpliftWorker(workers *Worker) *UpliftWorker {
+	b := &UpliftWorker{
+		Logger:  workers.Logger.With(zap.String("worker", "UpliftWorker")),
+		Workers: workers,
+	}
+	b.Logger.Debug("Configured UpliftWorker successfully.")
+	return b
+}
+
+func (b *UpliftWorker) getUsers(job *model.Job, path string) (map[string]bool, error) {
+	csv, err := b.Workers.S3Client.GetObject(path)
+	if err != nil {
+		return nil, err
+	}
+	return ReadUpliftUsers(csv)
+}
+
+func (b *UpliftWorker) updateJobUpliftReport(job *model.Job) {
+	_, err := b.Workers.MarathonDB.Model(job).Set("uplift_report = ?uplift_report").Update()
+	b.checkErr(job, err)
+}
+
+// Process processes the messages sent to worker queue
+func (b *UpliftWorker) Process(message *goworkers2.Msg) error {
+	arr, err := message.Args().Array()
+	checkErr(b.Logger, err)
+	jobID := arr[0]
+	id, err := uuid.FromString(jobID.(string))
+	checkErr(b.Logger, err)
+	l := b.Logger.With(
+		zap.String("jobID", id.String()),
+		zap.String("worker", nameUplift),
+	)
+	log.I(l, "starting")
+
+	job, err := b.Workers.GetJob(id)
+	checkErr(l, err)
+	if job.UpliftReport == nil {
+		log.I(l, "job has no uplift report")
+		return nil
+	}
+
+	b.Workers.Statsd.Incr(UpliftWorkerStart, job.Labels(), 1)
+	report := job.UpliftReport
+
+	treated, err := b.getUsers(job, job.TreatedCSVPath)
+	var control map[string]bool
+	if err == nil {
+		control, err = b.getUsers(job, job.ControlGroupCSVPath)
+	}
+	var events []UpliftEvent
+	if err == nil {
+		var buffer []byte
+		buffer, err = b.Workers.S3Client.GetObject(report.EventsPath)
+		if err == nil {
+			events, err = ReadUpliftEvents(buffer)
+		}
+	}
+	if err != nil {
+		log.E(l, "Failed to read uplift files.", func(cm log.CM) {
+			cm.Write(zap.Error(err))
+		})
+		report.Status = model.UpliftReportFailed
+		report.Error = err.Error()
+		report.CompletedAt = time.Now().UnixNano()
+		b.updateJobUpliftReport(job)
+		b.Workers.Statsd.Incr(UpliftWorkerError, job.Labels(), 1)
+		return nil
+	}
+
+	startedAt := job.StartsAt
+	if startedAt == 0 {
+		startedAt = job.CreatedAt
+	}
+	ComputeUplift(report, treated, control, events, startedAt)
+	report.Status = model.UpliftReportCompleted
+	report.Error = ""
+	report.CompletedAt = time.Now().UnixNano()
+	b.updateJobUpliftReport(job)
+
+	b.Workers.Statsd.Incr(UpliftWorkerCompleted, job.Labels(), 1)
+	log.I(l, "finished")
+
+	return nil
+}
+
+func (b *UpliftWorker) checkErr(job *model.Job, err error) {
+	if err != nil {
+		b.Workers.Statsd.Incr(UpliftWorkerError, job.Labels(), 1)
+		checkErr(b.Logger, err)
+	}
+}
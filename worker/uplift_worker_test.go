@@ -0,0 +1,166 @@
+/*
+ * Copyright (c) 2026 TFG Co <backend@tfgco.com>
+ * Author: TFG Co <backend@tfgco.com>
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy of
+ * this software and associated documentation files (the "Software"), to deal in
+ * the Software without restriction, including without limitation the rights to
+ * use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+ * the Software, and to permit persons to whom the Software is furnished to do so,
+ * subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+ * FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+ * COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+ * IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+ * CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+ */
+
+package worker_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	goworkers2 "github.com/digitalocean/go-workers2"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/topfreegames/marathon/model"
+	. "github.com/topfreegames/marathon/testing"
+	"github.com/topfreegames/marathon/worker"
+	"github.com/uber-go/zap"
+)
+
+var _ = Describe("Uplift Worker", func() {
+	var upliftWorker *worker.UpliftWorker
+	var app *model.App
+	var template *model.Template
+	var job *model.Job
+
+	logger := zap.New(
+		zap.NewJSONEncoder(zap.NoTime()), // drop timestamps in tests
+		zap.FatalLevel,
+	)
+	w := worker.NewWorker(logger, GetConfPath())
+	fakeS3 := NewFakeS3(w.Config)
+	w.S3Client = fakeS3
+
+	putObject := func(path, content string) {
+		body := []byte(content)
+		_, err := fakeS3.PutObject(path, &body)
+		Expect(err).NotTo(HaveOccurred())
+	}
+
+	process := func(jobID string) {
+		msgB, err := json.Marshal(map[string][]interface{}{
+			"args": {jobID},
+		})
+		Expect(err).NotTo(HaveOccurred())
+		message, err := goworkers2.NewMsg(string(msgB))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(func() { upliftWorker.Process(message) }).ShouldNot(Panic())
+	}
+
+	BeforeEach(func() {
+		upliftWorker = worker.NewUpliftWorker(w)
+
+		app = CreateTestApp(w.MarathonDB)
+		template = CreateTestTemplate(w.MarathonDB, app.ID)
+		job = CreateTestJob(w.MarathonDB, app.ID, template.Name, map[string]interface{}{
+			"controlGroup":        0.5,
+			"controlGroupCsvPath": fmt.Sprintf("bucket/control/job-%s.csv", app.ID),
+			"treatedCsvPath":      fmt.Sprintf("bucket/control/job-%s-treated.csv", app.ID),
+			"startsAt":            time.Now().Add(-time.Hour).UnixNano(),
+		})
+		putObject(job.ControlGroupCSVPath, "controlGroupUserIds\nc1\nc2\n")
+		putObject(job.TreatedCSVPath, "treatedUserIds\nt1\nt2\n")
+	})
+
+	Describe("Process", func() {
+		It("should store the uplift report in the job", func() {
+			eventsPath := fmt.Sprintf("bucket/folder/events-%s.csv", job.ID)
+			putObject(eventsPath, fmt.Sprintf("user_id,event,timestamp\nt1,purchase,%d\nc1,login,%d\n", time.Now().Unix(), time.Now().Unix()))
+			job.UpliftReport = &model.UpliftReport{
+				Status:     model.UpliftReportPending,
+				EventsPath: eventsPath,
+				Event:      "purchase",
+			}
+			_, err := w.MarathonDB.Model(job).Column("uplift_report").Update()
+			Expect(err).NotTo(HaveOccurred())
+
+			process(job.ID.String())
+
+			dbJob := &model.Job{}
+			err = w.MarathonDB.Model(dbJob).Where("id = ?", job.ID).Select()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(dbJob.UpliftReport.Status).To(Equal(model.UpliftReportCompleted))
+			Expect(dbJob.UpliftReport.Treated).To(Equal(model.UpliftGroup{Users: 2, Conversions: 1, ConversionRate: 0.5}))
+			Expect(dbJob.UpliftReport.Control).To(Equal(model.UpliftGroup{Users: 2, Conversions: 0, ConversionRate: 0}))
+			Expect(dbJob.UpliftReport.AbsoluteUplift).To(Equal(0.5))
+			Expect(dbJob.UpliftReport.ConfidenceInterval).To(HaveLen(2))
+			Expect(dbJob.UpliftReport.CompletedAt).NotTo(BeZero())
+		})
+
+		It("should fail the uplift report if the events file is invalid", func() {
+			eventsPath := fmt.Sprintf("bucket/folder/events-%s.csv", job.ID)
+			putObject(eventsPath, "user_id,timestamp\nt1,1500000000\n")
+			job.UpliftReport = &model.UpliftReport{
+				Status:     model.UpliftReportPending,
+				EventsPath: eventsPath,
+			}
+			_, err := w.MarathonDB.Model(job).Column("uplift_report").Update()
+			Expect(err).NotTo(HaveOccurred())
+
+			process(job.ID.String())
+
+			dbJob := &model.Job{}
+			err = w.MarathonDB.Model(dbJob).Where("id = ?", job.ID).Select()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(dbJob.UpliftReport.Status).To(Equal(model.UpliftReportFailed))
+			Expect(dbJob.UpliftReport.Error).To(Equal("events file has no event column"))
+		})
+
+		It("should fail the uplift report if the events file does not exist", func() {
+			job.UpliftReport = &model.UpliftReport{
+				Status:     model.UpliftReportPending,
+				EventsPath: "bucket/folder/missing.csv",
+			}
+			_, err := w.MarathonDB.Model(job).Column("uplift_report").Update()
+			Expect(err).NotTo(HaveOccurred())
+
+			process(job.ID.String())
+
+			dbJob := &model.Job{}
+			err = w.MarathonDB.Model(dbJob).Where("id = ?", job.ID).Select()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(dbJob.UpliftReport.Status).To(Equal(model.UpliftReportFailed))
+			Expect(dbJob.UpliftReport.Error).To(ContainSubstring("NoSuchKey"))
+			Expect(dbJob.UpliftReport.CompletedAt).NotTo(BeZero())
+		})
+
+		It("should fail the uplift report if the treated users file does not exist", func() {
+			eventsPath := fmt.Sprintf("bucket/folder/events-%s.csv", job.ID)
+			putObject(eventsPath, "user_id,event,timestamp\n")
+			job.TreatedCSVPath = "bucket/control/missing-treated.csv"
+			job.UpliftReport = &model.UpliftReport{
+				Status:     model.UpliftReportPending,
+				EventsPath: eventsPath,
+			}
+			_, err := w.MarathonDB.Model(job).Column("uplift_report").Column("treated_csv_path").Update()
+			Expect(err).NotTo(HaveOccurred())
+
+			process(job.ID.String())
+
+			dbJob := &model.Job{}
+			err = w.MarathonDB.Model(dbJob).Where("id = ?", job.ID).Select()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(dbJob.UpliftReport.Status).To(Equal(model.UpliftReportFailed))
+			Expect(dbJob.UpliftReport.Error).To(ContainSubstring("NoSuchKey"))
+		})
+	})
+})
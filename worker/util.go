@@ -41,10 +41,12 @@ import (
 	"github.com/topfreegames/marathon/log"
 	"github.com/topfreegames/marathon/model"
 	"github.com/uber-go/zap"
-	"github.com/valyala/fasttemplate"
 )
 
-const stoppedJobStatus = "stopped"
+const (
+	stoppedJobStatus         = "stopped"
+	pendingApprovalJobStatus = "pending_approval"
+)
 
 // User is the struct that will keep users before sending them to send batches worker
 type User struct {
@@ -53,12 +55,30 @@ type User struct {
 	Locale string `json:"locale,omitempty" sql:"locale"`
 	Region string `json:"region,omitempty" sql:"region"`
 	Tz     string `json:"tz,omitempty" sql:"tz"`
+	// Extra has the push db columns used by the job templates, see TemplateUserColumns
+	Extra map[string]interface{} `json:"extra,omitempty" sql:"extra"`
+	// Context has the values of the csv columns of the user, see BatchPart.Columns
+	Context map[string]interface{} `json:"context,omitempty" sql:"-"`
 	// CreatedAt pg.NullTime `json:"created_at,omitempty" sql:"created_at"`
 	// Fiu       string      `json:"fiu,omitempty" sql:"fiu"`
 	// Adid      string      `json:"adid,omitempty" sql:"adid"`
 	// VendorID  string      `json:"vendor_id,omitempty" sql:"vendor_id"`
 }
 
+// GetUsersSelect returns the user columns fetched from the push db, the extra columns
+// are fetched as a json object in User.Extra
+func GetUsersSelect(extraColumns []string) string {
+	columns := "user_id, token, locale, region, tz"
+	if len(extraColumns) == 0 {
+		return columns
+	}
+	pairs := []string{}
+	for _, column := range extraColumns {
+		pairs = append(pairs, fmt.Sprintf("'%s', \"%s\"", column, column))
+	}
+	return fmt.Sprintf("%s, json_build_object(%s) AS extra", columns, strings.Join(pairs, ", "))
+}
+
 // Batch is a struct that helps tracking processes pages
 type Batch struct {
 	UserIds *[]string
@@ -137,36 +157,6 @@ func checkErr(l zap.Logger, err error) {
 	}
 }
 
-// GetWhereClauseFromFilters returns a string cointaining the where clause to use in the query
-func GetWhereClauseFromFilters(filters map[string]interface{}) string {
-	if len(filters) == 0 {
-		return ""
-	}
-
-	queryFilters := []string{}
-	for key, val := range filters {
-		operator := "="
-		connector := " OR "
-		if strings.Contains(key, "NOT") {
-			key = strings.Trim(key, "NOT")
-			operator = "!="
-			connector = " AND "
-		}
-		strVal := val.(string)
-		if strings.Contains(strVal, ",") {
-			filterArray := []string{}
-			vals := strings.Split(strVal, ",")
-			for _, fVal := range vals {
-				filterArray = append(filterArray, fmt.Sprintf("\"%s\"%s'%s'", key, operator, fVal))
-			}
-			queryFilters = append(queryFilters, fmt.Sprintf("(%s)", strings.Join(filterArray, connector)))
-		} else {
-			queryFilters = append(queryFilters, fmt.Sprintf("\"%s\"%s'%s'", key, operator, val))
-		}
-	}
-	return strings.Join(queryFilters, " AND ")
-}
-
 // GetPushDBTableName get the table name using appName and service
 func GetPushDBTableName(appName, service string) string {
 	return fmt.Sprintf("%s_%s", appName, service)
@@ -272,22 +262,15 @@ func ParseProcessBatchWorkerMessageArray(arr []interface{}) (*BatchWorkerMessage
 
 // BuildMessageFromTemplate build a message using a template and the context
 func BuildMessageFromTemplate(template model.Template, context map[string]interface{}) (string, error) {
-	body, err := json.Marshal(template.Body)
+	msg, err := RenderTemplate(template, context, nil)
 	if err != nil {
 		return "", err
 	}
-	bodyString := string(body)
-	t := fasttemplate.New(bodyString, "{{", "}}")
-
-	substitutions := make(map[string]interface{})
-	for k, v := range template.Defaults {
-		substitutions[k] = v
-	}
-	for k, v := range context {
-		substitutions[k] = v
+	message, err := json.Marshal(msg)
+	if err != nil {
+		return "", err
 	}
-	message := t.ExecuteString(substitutions)
-	return message, nil
+	return string(message), nil
 }
 
 // RandomElementFromSlice gets a random element from a slice
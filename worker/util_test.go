@@ -32,6 +32,7 @@ import (
 	uuid "github.com/satori/go.uuid"
 	"github.com/topfreegames/marathon/model"
 	"github.com/topfreegames/marathon/worker"
+	"gopkg.in/pg.v5/orm"
 )
 
 var _ = Describe("Worker Util", func() {
@@ -204,42 +205,52 @@ var _ = Describe("Worker Util", func() {
 	})
 
 	Describe("Get Clause From Filters", func() {
+		formatWhere := func(where string, params []interface{}) string {
+			return string(orm.Formatter{}.FormatQuery(nil, where, params...))
+		}
+
 		It("should return empty string if filters is empty", func() {
 			filters := map[string]interface{}{}
-			where := worker.GetWhereClauseFromFilters(filters)
+			where, params, err := worker.GetWhereClauseFromFilters(filters)
+			Expect(err).NotTo(HaveOccurred())
 			Expect(where).To(Equal(""))
+			Expect(params).To(BeEmpty())
 		})
 
 		It("should succeed with one simple filter", func() {
 			filters := map[string]interface{}{
 				"region": "US",
 			}
-			where := worker.GetWhereClauseFromFilters(filters)
-			Expect(where).To(Equal("\"region\"='US'"))
+			where, params, err := worker.GetWhereClauseFromFilters(filters)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(formatWhere(where, params)).To(Equal("(\"region\" IN ('US'))"))
 		})
 
 		It("should succeedd with one comma separated filter", func() {
 			filters := map[string]interface{}{
 				"region": "US,CA",
 			}
-			where := worker.GetWhereClauseFromFilters(filters)
-			Expect(where).To(Equal("(\"region\"='US' OR \"region\"='CA')"))
+			where, params, err := worker.GetWhereClauseFromFilters(filters)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(formatWhere(where, params)).To(Equal("(\"region\" IN ('US','CA'))"))
 		})
 
 		It("should succeed with one negative simple filter", func() {
 			filters := map[string]interface{}{
 				"NOTregion": "US",
 			}
-			where := worker.GetWhereClauseFromFilters(filters)
-			Expect(where).To(Equal("\"region\"!='US'"))
+			where, params, err := worker.GetWhereClauseFromFilters(filters)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(formatWhere(where, params)).To(Equal("(\"region\" NOT IN ('US'))"))
 		})
 
 		It("should succeed with one negative comma separated filter", func() {
 			filters := map[string]interface{}{
 				"NOTregion": "US,CA",
 			}
-			where := worker.GetWhereClauseFromFilters(filters)
-			Expect(where).To(Equal("(\"region\"!='US' AND \"region\"!='CA')"))
+			where, params, err := worker.GetWhereClauseFromFilters(filters)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(formatWhere(where, params)).To(Equal("(\"region\" NOT IN ('US','CA'))"))
 		})
 
 		It("should succeed with multiple filters", func() {
@@ -247,10 +258,28 @@ var _ = Describe("Worker Util", func() {
 				"NOTregion": "US,CA",
 				"locale":    "en,fr",
 			}
-			where := worker.GetWhereClauseFromFilters(filters)
-			Expect(where).To(ContainSubstring("(\"locale\"='en' OR \"locale\"='fr')"))
-			Expect(where).To(ContainSubstring("(\"region\"!='US' AND \"region\"!='CA')"))
-			Expect(where).To(ContainSubstring(") AND ("))
+			where, params, err := worker.GetWhereClauseFromFilters(filters)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(formatWhere(where, params)).To(Equal("(\"locale\" IN ('en','fr') AND \"region\" NOT IN ('US','CA'))"))
+		})
+
+		It("should not interpolate filter values in the query", func() {
+			filters := map[string]interface{}{
+				"region": "US' OR '1'='1",
+			}
+			where, params, err := worker.GetWhereClauseFromFilters(filters)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(where).To(Equal("(\"region\" IN (?))"))
+			Expect(formatWhere(where, params)).To(Equal("(\"region\" IN ('US'' OR ''1''=''1'))"))
+		})
+
+		It("should fail if a filter column is not a valid identifier", func() {
+			filters := map[string]interface{}{
+				"region\" = 'US' OR \"1": "US",
+			}
+			_, _, err := worker.GetWhereClauseFromFilters(filters)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("invalid column"))
 		})
 	})
 })
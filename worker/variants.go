@@ -0,0 +1,78 @@
+/*
+ * Copyright (c) 2026 TFG Co <backend@tfgco.com>
+ * Author: TFG Co <backend@tfgco.com>
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy of
+ * this software and associated documentation files (the "Software"), to deal in
+ * the Software without restriction, including without limitation the rights to
+ * use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+ * the Software, and to permit persons to whom the Software is furnished to do so,
+ * subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+ * FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+ * COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+ * IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+ * CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+ */
+
+package worker
+
+import (
+	"fmt"
+	"hash/fnv"
+
+	"github.com/topfreegames/marathon/interfaces"
+	"github.com/topfreegames/marathon/model"
+)
+
+// SelectVariant returns the template of the user, it is picked by hashing the job and user ids
+// so the user gets the same template if a batch is retried
+func SelectVariant(job *model.Job, userID string) string {
+	names := job.TemplateNames()
+	if len(names) == 1 {
+		return names[0]
+	}
+
+	total := 0
+	for _, name := range names {
+		total += variantWeight(job, name)
+	}
+	h := fnv.New32a()
+	h.Write([]byte(job.ID.String()))
+	h.Write([]byte(userID))
+	point := int(h.Sum32() % uint32(total))
+	for _, name := range names {
+		point -= variantWeight(job, name)
+		if point < 0 {
+			return name
+		}
+	}
+	return names[len(names)-1]
+}
+
+func variantWeight(job *model.Job, name string) int {
+	if len(job.TemplateWeights) == 0 {
+		return 1
+	}
+	return job.TemplateWeights[name]
+}
+
+// GetVariantRedisKey returns the redis list with the users ids of the variant
+func GetVariantRedisKey(job *model.Job, variant string) string {
+	return fmt.Sprintf("%s-VARIANT-%s", job.ID.String(), variant)
+}
+
+// IncrVariantFeedbacks adds the counters to the variant feedbacks of the job
+func IncrVariantFeedbacks(db interfaces.DB, job *model.Job, values map[string]map[string]int) error {
+	if len(values) == 0 {
+		return nil
+	}
+	query, params := model.VariantFeedbacksIncrQuery(job.ID.String(), values)
+	_, err := db.Exec(query, params...)
+	return err
+}
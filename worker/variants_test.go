@@ -0,0 +1,107 @@
+/*
+ * Copyright (c) 2026 TFG Co <backend@tfgco.com>
+ * Author: TFG Co <backend@tfgco.com>
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy of
+ * this software and associated documentation files (the "Software"), to deal in
+ * the Software without restriction, including without limitation the rights to
+ * use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+ * the Software, and to permit persons to whom the Software is furnished to do so,
+ * subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+ * FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+ * COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+ * IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+ * CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+ */
+
+package worker_test
+
+import (
+	"fmt"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	uuid "github.com/satori/go.uuid"
+	"github.com/topfreegames/marathon/model"
+	"github.com/topfreegames/marathon/worker"
+)
+
+var _ = Describe("Variants", func() {
+	Describe("Select variant", func() {
+		It("should return the template if the job has only one", func() {
+			job := &model.Job{ID: uuid.NewV4(), TemplateName: "tpl1"}
+			Expect(job.HasVariants()).To(BeFalse())
+			Expect(worker.SelectVariant(job, "user")).To(Equal("tpl1"))
+		})
+
+		It("should always return the same template to the same user", func() {
+			job := &model.Job{ID: uuid.NewV4(), TemplateName: "tpl1,tpl2,tpl3"}
+			Expect(job.HasVariants()).To(BeTrue())
+			for i := 0; i < 20; i++ {
+				userID := fmt.Sprintf("user-%d", i)
+				variant := worker.SelectVariant(job, userID)
+				Expect(variant).To(BeElementOf("tpl1", "tpl2", "tpl3"))
+				Expect(worker.SelectVariant(job, userID)).To(Equal(variant))
+			}
+		})
+
+		It("should split the users according to the template weights", func() {
+			job := &model.Job{
+				ID:              uuid.NewV4(),
+				TemplateName:    "tpl1,tpl2",
+				TemplateWeights: map[string]int{"tpl1": 9, "tpl2": 1},
+			}
+			counts := map[string]int{}
+			for i := 0; i < 1000; i++ {
+				counts[worker.SelectVariant(job, fmt.Sprintf("user-%d", i))]++
+			}
+			Expect(counts["tpl1"]).To(BeNumerically(">", 800))
+			Expect(counts["tpl2"]).To(BeNumerically(">", 50))
+			Expect(counts["tpl1"] + counts["tpl2"]).To(Equal(1000))
+		})
+	})
+
+	Describe("Template weights", func() {
+		It("should be valid if empty or if every template has a positive weight", func() {
+			job := &model.Job{TemplateName: "tpl1,tpl2"}
+			Expect(job.ValidTemplateWeights()).To(BeTrue())
+			job.TemplateWeights = map[string]int{"tpl1": 1, "tpl2": 3}
+			Expect(job.ValidTemplateWeights()).To(BeTrue())
+		})
+
+		It("should be invalid if a template is missing, unknown or has no weight", func() {
+			job := &model.Job{TemplateName: "tpl1,tpl2"}
+			job.TemplateWeights = map[string]int{"tpl1": 1}
+			Expect(job.ValidTemplateWeights()).To(BeFalse())
+			job.TemplateWeights = map[string]int{"tpl1": 1, "tpl3": 1}
+			Expect(job.ValidTemplateWeights()).To(BeFalse())
+			job.TemplateWeights = map[string]int{"tpl1": 1, "tpl2": 0}
+			Expect(job.ValidTemplateWeights()).To(BeFalse())
+		})
+	})
+
+	Describe("Variant feedbacks query", func() {
+		It("should increment every key of every variant", func() {
+			query, params := model.VariantFeedbacksIncrQuery("job-id", map[string]map[string]int{
+				"tpl2": {"ack": 2},
+				"tpl1": {"sent": 3, "ack": 1},
+			})
+			Expect(query).To(ContainSubstring("UPDATE jobs SET variant_feedbacks = variant_feedbacks || jsonb_build_object("))
+			Expect(query).To(HaveSuffix("WHERE id = ?"))
+			Expect(params).To(Equal([]interface{}{
+				"tpl1", "tpl1",
+				"ack", "tpl1", "ack", 1,
+				"sent", "tpl1", "sent", 3,
+				"tpl2", "tpl2",
+				"ack", "tpl2", "ack", 2,
+				"job-id",
+			}))
+		})
+	})
+})
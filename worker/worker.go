@@ -28,11 +28,13 @@ import (
 	"fmt"
 	"net/http"
 	"os"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/DataDog/datadog-go/statsd"
 	goworkers2 "github.com/digitalocean/go-workers2"
+	"github.com/digitalocean/go-workers2/storage"
 	raven "github.com/getsentry/raven-go"
 	uuid "github.com/satori/go.uuid"
 	"github.com/spf13/viper"
@@ -107,6 +109,16 @@ func (w *Worker) loadConfigurationDefaults() {
 	w.Config.SetDefault("database.url", "postgres://localhost:5432/marathon?sslmode=disable")
 	w.Config.SetDefault("workers.statsd.host", "127.0.0.1:8125")
 	w.Config.SetDefault("workers.statsd.prefix", "marathon.")
+	w.Config.SetDefault("workers.scheduler.interval", "30s")
+	w.Config.SetDefault("workers.scheduler.lookahead", "1m")
+	w.Config.SetDefault("workers.scheduler.maxDelay", "1h")
+	w.Config.SetDefault("workers.priorities.weights.high", 6)
+	w.Config.SetDefault("workers.priorities.weights.normal", 3)
+	w.Config.SetDefault("workers.priorities.weights.low", 1)
+	w.Config.SetDefault("workers.fairness.slotTimeout", "10m")
+	w.Config.SetDefault("workers.fairness.retryDelay", "5s")
+	w.Config.SetDefault("workers.timezones.cacheTTL", "1h")
+	w.Config.SetDefault("workers.jobCompleted.pageSize", 10000)
 }
 
 func (w *Worker) configureSendgrid() {
@@ -198,22 +210,26 @@ func (w *Worker) configureWorkers() {
 	c := NewCreateBatchesWorker(w)
 	r := NewResumeJobWorker(w)
 	j := NewJobCompletedWorker(w)
+	u := NewUpliftWorker(w)
 	directWorker := NewDirectWorker(w)
 
 	createCSVSplitWorkerConcurrency := w.Config.GetInt("workers.csvSplitWorker.concurrency")
-	processBatchWorkerConcurrency := w.Config.GetInt("workers.processBatch.concurrency")
 	resumeJobWorkerConcurrency := w.Config.GetInt("workers.resume.concurrency")
 	jobCompletedWorkerConcurrency := w.Config.GetInt("workers.jobCompleted.concurrency")
 	createBatchesWorkerConcurrency := w.Config.GetInt("workers.createBatches.concurrency")
-
-	jobDirectWorkerConcurrency := w.Config.GetInt("workers.direct.concurrency")
+	upliftWorkerConcurrency := w.Config.GetInt("workers.uplift.concurrency")
 
 	w.Manager.AddWorker("csv_split_worker", createCSVSplitWorkerConcurrency, k.Process)
 	w.Manager.AddWorker("create_batches_worker", createBatchesWorkerConcurrency, c.Process)
-	w.Manager.AddWorker("process_batch_worker", processBatchWorkerConcurrency, p.Process)
+	for priority, concurrency := range PriorityConcurrency(w.Config, "processBatch") {
+		w.Manager.AddWorker(PriorityQueue(nameProcessBatchWorker, priority), concurrency, p.Process)
+	}
 	w.Manager.AddWorker("resume_job_worker", resumeJobWorkerConcurrency, r.Process)
 	w.Manager.AddWorker("job_completed_worker", jobCompletedWorkerConcurrency, j.Process)
-	w.Manager.AddWorker("direct_worker", jobDirectWorkerConcurrency, directWorker.Process)
+	w.Manager.AddWorker("uplift_worker", upliftWorkerConcurrency, u.Process)
+	for priority, concurrency := range PriorityConcurrency(w.Config, "direct") {
+		w.Manager.AddWorker(PriorityQueue(nameDirectWorker, priority), concurrency, directWorker.Process)
+	}
 }
 
 func (w *Worker) configureSentry() {
@@ -311,6 +327,14 @@ func (w *Worker) createDirectBatchesJobWithOption(job *model.Job, options gowork
 	if err != nil {
 		return err
 	}
+	// the push rate of jobs spread over a duration sends the total tokens in it, so they are
+	// the users that match the filters instead of the estimate of the whole table
+	if job.SpreadOver > 0 {
+		rownsEstimative, err = w.countFilteredUsers(job, tableName)
+		if err != nil {
+			return err
+		}
+	}
 	if rownsEstimative == 0 {
 		rownsEstimative = 1
 	}
@@ -321,7 +345,7 @@ func (w *Worker) createDirectBatchesJobWithOption(job *model.Job, options gowork
 	producer := w.Manager.Producer()
 
 	for i = 0; i < maxSeqID+1; {
-		_, err = producer.EnqueueWithOptions("direct_worker", "Add",
+		_, err = producer.EnqueueWithOptions(PriorityQueue(nameDirectWorker, job.Priority), "Add",
 			DirectPartMsg{
 				SmallestSeqID: i,
 				BiggestSeqID:  i + testBatchSize,
@@ -347,15 +371,44 @@ func (w *Worker) createDirectBatchesJobWithOption(job *model.Job, options gowork
 	return nil
 }
 
-// CreateProcessBatchJob creates a new ProcessBatchWorker job
-func (w *Worker) CreateProcessBatchJob(jobID string, appName string, users *[]User) (string, error) {
+func (w *Worker) countFilteredUsers(job *model.Job, tableName string) (uint64, error) {
+	var count uint64
+	whereClause, params, err := GetWhereClauseFromFilters(job.Filters)
+	if err != nil {
+		return 0, err
+	}
+	query := fmt.Sprintf("SELECT count(*) FROM %s", tableName)
+	if whereClause != "" {
+		query = fmt.Sprintf("%s WHERE %s", query, whereClause)
+	}
+	_, err = w.PushDB.QueryOne(&count, query, params...)
+	return count, err
+}
+
+// ScheduleDirectPartJob schedules a DirectWorker job that sends again a part of the job
+func (w *Worker) ScheduleDirectPartJob(job *model.Job, part *DirectPartMsg, at int64) (string, error) {
+	maxRetries := w.Config.GetInt("workers.direct.maxRetries")
+	producer := w.Manager.Producer()
+	return producer.EnqueueWithOptions(
+		PriorityQueue(nameDirectWorker, job.Priority),
+		"Add",
+		part,
+		goworkers2.EnqueueOptions{
+			Retry:      true,
+			RetryCount: maxRetries,
+			At:         float64(at) / goworkers2.NanoSecondPrecision,
+		})
+}
+
+// CreateProcessBatchJob creates a new ProcessBatchWorker job in the queue of the job priority
+func (w *Worker) CreateProcessBatchJob(jobID string, appName string, priority string, users *[]User) (string, error) {
 	compressedUsers, err := CompressUsers(users)
 	if err != nil {
 		return "", err
 	}
 	producer := w.Manager.Producer()
 	return producer.Enqueue(
-		"process_batch_worker",
+		PriorityQueue(nameProcessBatchWorker, priority),
 		"Add",
 		[]interface{}{jobID, appName, compressedUsers},
 	)
@@ -398,15 +451,15 @@ func (w *Worker) ScheduleCreateBatchesFromFiltersJob(jobID *[]string, at int64)
 		})
 }
 
-// ScheduleProcessBatchJob schedules a new ProcessBatchWorker job
-func (w *Worker) ScheduleProcessBatchJob(jobID string, appName string, users *[]User, at int64) (string, error) {
+// ScheduleProcessBatchJob schedules a new ProcessBatchWorker job in the queue of the job priority
+func (w *Worker) ScheduleProcessBatchJob(jobID string, appName string, priority string, users *[]User, at int64) (string, error) {
 	compressedUsers, err := CompressUsers(users)
 	if err != nil {
 		return "", err
 	}
 	producer := w.Manager.Producer()
 	return producer.EnqueueWithOptions(
-		"process_batch_worker",
+		PriorityQueue(nameProcessBatchWorker, priority),
 		"Add",
 		[]interface{}{jobID, appName, compressedUsers},
 		goworkers2.EnqueueOptions{
@@ -430,6 +483,64 @@ func (w *Worker) ScheduleJobCompletedJob(jobID string, at int64) (string, error)
 		})
 }
 
+// CreateUpliftJob creates a new UpliftWorker job
+func (w *Worker) CreateUpliftJob(jobID string) (string, error) {
+	maxRetries := w.Config.GetInt("workers.uplift.maxRetries")
+	producer := w.Manager.Producer()
+	return producer.EnqueueWithOptions(
+		"uplift_worker",
+		"Add",
+		[]interface{}{jobID},
+		goworkers2.EnqueueOptions{
+			Retry:      true,
+			RetryCount: maxRetries,
+		})
+}
+
+// RemoveScheduledJob removes from the scheduled set the messages that would start the job,
+// it returns the amount of removed messages, zero means the job was not scheduled or already started
+func (w *Worker) RemoveScheduledJob(job *model.Job) (int, error) {
+	at := float64(job.StartsAt) / goworkers2.NanoSecondPrecision
+	entries, err := w.RedisClient.ZRangeByScore(storage.ScheduledJobsKey, redis.ZRangeBy{
+		Min: strconv.FormatFloat(at-1, 'f', -1, 64),
+		Max: strconv.FormatFloat(at+1, 'f', -1, 64),
+	}).Result()
+	if err != nil {
+		return 0, err
+	}
+
+	removed := 0
+	for _, entry := range entries {
+		if !isJobStartMessage(entry, job.ID.String()) {
+			continue
+		}
+		// ZRem fails to remove the entry if the go-workers scheduler already enqueued it
+		n, err := w.RedisClient.ZRem(storage.ScheduledJobsKey, entry).Result()
+		if err != nil {
+			return removed, err
+		}
+		removed += int(n)
+	}
+	return removed, nil
+}
+
+func isJobStartMessage(entry, jobID string) bool {
+	msg, err := goworkers2.NewMsg(entry)
+	if err != nil {
+		return false
+	}
+	queue, _ := msg.Get("queue").String()
+	if queue == "csv_split_worker" {
+		id, _ := msg.Args().String()
+		return id == jobID
+	}
+	if _, ok := QueuePriority(nameDirectWorker, queue); ok {
+		id, _ := msg.Args().Get("JobUUID").String()
+		return id == jobID
+	}
+	return false
+}
+
 // Start starts the worker
 func (w *Worker) Start() {
 	jobsStatsPort := w.Config.GetInt("workers.statsPort")
@@ -474,6 +585,32 @@ func (w *Worker) SendControlGroupToRedis(job *model.Job, ids []string) {
 	w.Statsd.Timing("save_control_group", time.Now().Sub(start), job.Labels(), 1)
 }
 
+// SendTreatedGroupToRedis send the users ids that received the job to redis, they are compared
+// with the control group by the uplift report
+func (w *Worker) SendTreatedGroupToRedis(job *model.Job, ids []string) {
+	start := time.Now()
+	hash := job.ID.String()
+	var args []interface{}
+	for _, id := range ids {
+		args = append(args, id)
+	}
+	w.RedisClient.LPush(fmt.Sprintf("%s-TREATED", hash), args...).Result()
+	w.Statsd.Timing("save_treated_group", time.Now().Sub(start), job.Labels(), 1)
+}
+
+// SendVariantsToRedis send the users ids of each variant to redis
+func (w *Worker) SendVariantsToRedis(job *model.Job, idsByVariant map[string][]string) {
+	start := time.Now()
+	for variant, ids := range idsByVariant {
+		var args []interface{}
+		for _, id := range ids {
+			args = append(args, id)
+		}
+		w.RedisClient.LPush(GetVariantRedisKey(job, variant), args...).Result()
+	}
+	w.Statsd.Timing("save_variants", time.Now().Sub(start), job.Labels(), 1)
+}
+
 // GetJob get a job from the db
 func (w *Worker) GetJob(jobID uuid.UUID) (*model.Job, error) {
 	job := model.Job{